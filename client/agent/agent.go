@@ -2,24 +2,67 @@ package agent
 
 import (
 	"bufio"
-	"bytes"
+	"cloudcadetest/common/compress"
+	"cloudcadetest/common/encrypt/aes"
+	"cloudcadetest/common/encrypt/kex"
 	"cloudcadetest/framework/agent"
+	"cloudcadetest/framework/msg/codec"
 	"cloudcadetest/framework/network"
-	"cloudcadetest/framework/network/protobuf"
 	"cloudcadetest/pb"
+	"crypto/ed25519"
 	"errors"
 	"fmt"
 	"github.com/golang/protobuf/proto"
 	"math/rand"
 	"os"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+const (
+	maxMsgLen       = 10000
+	minCompressSize = 1024
+)
+
+var (
+	ServerAddr       = "127.0.0.1:3066"
+	ServerPubKeyFile string // 服务端身份公钥, 不为空则先进行密钥交换
+	Username         string // 登录名, 为空则随机生成
+	NameKey          string // 登录名已保留时的密钥
+	Password         string // 服务端开启认证时使用密码或令牌登录
+	AuthToken        string
+	AllowPlaintext   bool // 未指定服务端公钥时需显式允许明文发送密码或令牌
+
+	serverIdentity ed25519.PublicKey
+
+	client *network.TCPClient
+	inputs = make(chan string) // 标准输入, 断线重连后由新连接继续读取
+)
+
 func New() {
 	registerHandlers()
-	client := new(network.TCPClient)
-	client.Addr = "127.0.0.1:3066"
+
+	if ServerPubKeyFile != "" {
+		if AllowPlaintext {
+			pureLog("plaintext cannot be allowed with a server public key")
+			os.Exit(-1)
+		}
+		var e error
+		if serverIdentity, e = kex.LoadPublicKey(ServerPubKeyFile); e != nil {
+			pureLog("load server public key failed:%s", e.Error())
+			os.Exit(-1)
+		}
+	} else if (Password != "" || AuthToken != "") && !AllowPlaintext {
+		pureLog("refuse to send credentials in plaintext, set -server_pubkey or -plaintext")
+		os.Exit(-1)
+	}
+
+	go readInput()
+
+	client = new(network.TCPClient)
+	client.Addr = ServerAddr
 	client.ConnectInterval = 3 * time.Second
 	client.PendingWriteNum = 100
 	client.NewAgent = NewPlayer
@@ -36,37 +79,77 @@ type chat struct {
 }
 
 type Player struct {
-	conn     network.IConn
-	proc     *protobuf.Processor
-	username string
-	chats    chan *chat
+	conn       network.IConn
+	dec        *codec.Decoder
+	enc        *codec.Encoder
+	compressor compress.ID // 服务端选定的压缩算法, 登录前不压缩
+	username   string
+	chats      chan *chat
+	eph        *kex.Ephemeral
+	session    *aes.Session
+	members    map[string]int64 // 当前房间成员 -> 加入时间, 由NTF_ROOM_PRESENCE维护
+	lastRecv   int64            // 最近一次收到消息的时间(unix纳秒)
+	oldestID   int64            // 当前房间已显示的最早消息ID, 用于向前翻页
+	lastSearch atomic.Value     // *pb.CSReqSearchHistory, 最近一次发出的搜索
+	nextSearch atomic.Value     // *pb.CSReqSearchHistory, 搜索结果的下一页, 没有时为nil
+	window     codec.SeqWindow  // 下行序号, 只在读协程中使用
+	acked      int64            // 已连续收到的最大下行序号, 随心跳发送
+	done       chan struct{}    // 连接关闭时关闭
+	closed     bool
+	sync.Mutex // 保护closed, 关闭后不再写入
 }
 
 func NewPlayer(conn *network.TCPConn) agent.Agent {
 	printTitle()
 
 	p := &Player{
-		conn:  conn,
-		proc:  protobuf.NewProcessor(),
-		chats: make(chan *chat, 100),
+		conn:     conn,
+		enc:      codec.NewEncoder(minCompressSize),
+		chats:    make(chan *chat, 100),
+		lastRecv: time.Now().UnixNano(),
+		done:     make(chan struct{}),
+	}
+	p.dec = codec.NewDecoder(maxMsgLen, true)
+	p.dec.Cipher = func(pb.CSMsgID) (codec.Cipher, error) {
+		// 握手应答为明文, 之后的消息均需解密
+		if p.session != nil {
+			return p.session, nil
+		}
+		return nil, nil
 	}
 
 	go p.conn.WriteTask()
 	go p.handleChats()
 
-	p.login()
+	if serverIdentity != nil {
+		p.handshake()
+	} else {
+		p.login()
+	}
 
 	p.readTask()
 
 	return p
 }
 
+func readInput() {
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		inputs <- scanner.Text()
+	}
+}
+
 func (p *Player) userInput(hint string, cb func(string)) {
 	go func() {
-		scanner := bufio.NewScanner(os.Stdin)
 		fmt.Println(hint)
-		scanner.Scan()
-		cb(scanner.Text())
+		for {
+			select {
+			case input := <-inputs:
+				cb(input)
+			case <-p.done:
+				return
+			}
+		}
 	}()
 }
 
@@ -75,22 +158,69 @@ func (p *Player) handleChats() {
 		select {
 		case c := <-p.chats:
 			printChat(p.username, c.from, c.content, c.ts.Format("2006-01-02 15:04:05"))
-		default:
+		case <-p.done:
+			return
 		}
 	}
 }
 
+func (p *Player) handshake() {
+	eph, e := kex.NewEphemeral()
+	if e != nil {
+		pureLog("new ephemeral key failed:%s", e.Error())
+		p.quit(1)
+		return
+	}
+	p.eph = eph
+	p.send(pb.CSMsgID_REQ_HANDSHAKE, &pb.CSReqBody{Handshake: &pb.CSReqHandshake{
+		PublicKey: eph.PublicKey(),
+		Nonce:     eph.Nonce,
+	}})
+}
+
 func (p *Player) login() {
 	rand.Seed(time.Now().UnixNano())
-	p.send(pb.CSMsgID_REQ_LOGIN, &pb.CSReqBody{Login: &pb.CSReqLogin{
-		Username: "test_" + strconv.Itoa(rand.Intn(1000)),
-	}})
+	codecs := make([]pb.COMPRESS_CODEC, 0)
+	for _, id := range compress.IDs() {
+		codecs = append(codecs, pb.COMPRESS_CODEC(id))
+	}
+	req := &pb.CSReqLogin{
+		Username:  Username,
+		Codecs:    codecs,
+		NameKey:   NameKey,
+		Password:  Password,
+		AuthToken: AuthToken,
+	}
+	if req.Username == "" {
+		req.Username = "test_" + strconv.Itoa(rand.Intn(1000))
+	}
+	if resume.token != "" {
+		req.Username = resume.username
+		req.ResumeToken = resume.token
+		req.LastSeq = resume.lastSeq
+		req.Ack = resume.ack
+	}
+	p.send(pb.CSMsgID_REQ_LOGIN, &pb.CSReqBody{Login: req})
 }
 
+// 关闭当前连接, 由TCPClient负责重连
 func (p *Player) OnClose(code uint) {
+	p.Lock()
+	defer p.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+
 	pureLog("player destroyed:%d", code)
 	p.conn.Close()
-	os.Exit(-1)
+	close(p.done)
+}
+
+// 不再重连, 客户端退出
+func (p *Player) quit(code uint) {
+	client.Close()
+	p.OnClose(code)
 }
 
 func (p *Player) Addr() string {
@@ -98,36 +228,41 @@ func (p *Player) Addr() string {
 }
 
 func (p *Player) readTask() {
-	recvBuffer := new(bytes.Buffer)
 	onceBuffer := make([]byte, 4096)
 	for {
-		msgHandler := func(msgID pb.CSMsgID, bodyBuf []byte) bool {
-			var body proto.Message
-			if msgID < pb.CSMsgID_NTF_BEGIN {
-				body = &pb.CSRspBody{}
-			} else {
-				body = &pb.CSNtfBody{}
+		msgHandler := func(f *codec.Frame) bool {
+			atomic.StoreInt64(&p.lastRecv, time.Now().UnixNano())
+			// 重发的消息已处理过
+			if !p.window.Accept(f.Seq) {
+				return true
 			}
-			err := proto.Unmarshal(bodyBuf, body)
+			p.updateAck()
+
+			body := codec.NewBody(f.MsgID)
+			err := proto.Unmarshal(f.Body, body)
 			if err != nil {
-				pureLog("DealMsg %s Unmarshal fail[%s]", msgID, err.Error())
+				pureLog("DealMsg %s Unmarshal fail[%s]", f.MsgID, err.Error())
 				return false
 			}
-			router(msgID, p, body)
+			router(f.MsgID, p, body)
 			return true
 		}
 
-		if e := dealMsg(p.conn, recvBuffer, onceBuffer, msgHandler); e != nil {
+		if e := dealMsg(p.conn, p.dec, onceBuffer, msgHandler); e != nil {
+			pureLog("deal msg failed:%s", e.Error())
 			break
 		}
 	}
 
-	//close(taskReady)
-
 	p.OnClose(uint(999))
 }
 
-func dealMsg(conn network.IConn, recvBuffer *bytes.Buffer, onceBuffer []byte, msgHandler func(pb.CSMsgID, []byte) bool) error {
+func (p *Player) updateAck() {
+	atomic.StoreInt64(&p.acked, p.window.Ack())
+	resume.ack = p.window.Ack()
+}
+
+func dealMsg(conn network.IConn, dec *codec.Decoder, onceBuffer []byte, msgHandler func(*codec.Frame) bool) error {
 	// 从网络层读取数据
 	n, err := conn.Read(onceBuffer)
 	if err != nil {
@@ -135,63 +270,23 @@ func dealMsg(conn network.IConn, recvBuffer *bytes.Buffer, onceBuffer []byte, ms
 	}
 
 	// 将数据串起来,方便处理粘包
-	_, err = recvBuffer.Write(onceBuffer[:n])
+	_, err = dec.Write(onceBuffer[:n])
 	if err != nil {
 		return err
 	}
 
 	// 处理消息包(处理粘包 一次最大处理16个包)
 	for i := 0; i < 16; i++ {
-		rlen := recvBuffer.Len()
-		if rlen < 1 { // 包不够长度
-			break
+		f, e := dec.Next()
+		if e != nil {
+			return e
 		}
-
-		buf := recvBuffer.Bytes()
-		hlen := int32(buf[0])
-		if hlen > 1000 {
-			return fmt.Errorf("message too long %d", hlen)
-		} else if hlen <= 1 {
-			return fmt.Errorf("message too short %d", hlen)
-		}
-
-		if rlen < int(hlen)+1 {
+		if f == nil { // 包不够长度
 			break
 		}
 
-		//parse head
-		h := &pb.CSHead{}
-		if err = proto.Unmarshal(buf[1:hlen+1], h); err != nil {
-			return errors.New("Unmarshal head error:" + err.Error() + fmt.Sprintf(" headLen:%v", hlen))
-		}
-
-		if h.BodyLen > 1000 {
-			return fmt.Errorf("message too long %v", h.BodyLen)
-		} else if h.BodyLen < 1 {
-			return fmt.Errorf("message too short %v", h.BodyLen)
-		}
-
-		pktLen := int(hlen) + 1 + int(h.BodyLen)
-		if rlen < pktLen {
-			break
-		}
-
-		data := recvBuffer.Next(pktLen)
-		var bodyBuf []byte
-		if h.BodyLen > 0 {
-			bodyBuf = make([]byte, h.BodyLen, h.BodyLen)
-			if copy(bodyBuf, data[int(hlen)+1:pktLen]) != int(h.BodyLen) { // 拷贝出错了
-				pureLog("copy err:%v", h)
-				return errors.New("copy err")
-			}
-
-			if h.IsCompressed {
-				//todo 需要解压缩
-			}
-		}
-
 		if msgHandler != nil {
-			if !msgHandler(h.MsgID, bodyBuf) {
+			if !msgHandler(f) {
 				return errors.New("msg handler err")
 			}
 		}
@@ -202,14 +297,39 @@ func dealMsg(conn network.IConn, recvBuffer *bytes.Buffer, onceBuffer []byte, ms
 var callbacks = map[pb.CSMsgID]func(*Player, interface{}){}
 
 func registerHandlers() {
+	callbacks[pb.CSMsgID_RSP_HANDSHAKE] = rspHandshake
 	callbacks[pb.CSMsgID_RSP_LOGIN] = rspLogin
 	callbacks[pb.CSMsgID_RSP_ROOM_LIST] = rspRoomList
 	callbacks[pb.CSMsgID_RSP_JOIN_ROOM] = rspJoinRoom
 	callbacks[pb.CSMsgID_RSP_ROOM_CHAT] = rspRoomChat
+	callbacks[pb.CSMsgID_RSP_LEAVE_ROOM] = rspLeaveRoom
+	callbacks[pb.CSMsgID_RSP_ROOM_MEMBERS] = rspRoomMembers
+	callbacks[pb.CSMsgID_RSP_ROOM_HISTORY] = rspRoomHistory
+	callbacks[pb.CSMsgID_RSP_SEARCH_HISTORY] = rspSearchHistory
+	callbacks[pb.CSMsgID_RSP_CHAT] = rspChat
+	callbacks[pb.CSMsgID_RSP_INBOX_READ] = rspInboxRead
+	callbacks[pb.CSMsgID_RSP_SET_ROLE] = rspModerate
+	callbacks[pb.CSMsgID_RSP_ROOM_MUTE] = rspModerate
+	callbacks[pb.CSMsgID_RSP_ROOM_KICK] = rspModerate
+	callbacks[pb.CSMsgID_RSP_BAN] = rspModerate
+	callbacks[pb.CSMsgID_RSP_SET_USERNAME] = rspSetUsername
+	callbacks[pb.CSMsgID_RSP_HEARTBEAT] = rspHeartbeat
 
 	callbacks[pb.CSMsgID_NTF_ROOM_CHAT] = ntfRoomChat
 	callbacks[pb.CSMsgID_NTF_HISTROY_MSG] = ntfHistoryMsgs
 	callbacks[pb.CSMsgID_NTF_ROOM_MEMBER_ONLINE] = ntfRoomMemberOnline
+	callbacks[pb.CSMsgID_NTF_ROOM_MEMBER_LEAVE] = ntfRoomMemberLeave
+	callbacks[pb.CSMsgID_NTF_ROOM_MEMBER_OFFLINE] = ntfRoomMemberOffline
+	callbacks[pb.CSMsgID_NTF_ROOM_PRESENCE] = ntfRoomPresence
+	callbacks[pb.CSMsgID_NTF_ROOM_CLOSED] = ntfRoomClosed
+	callbacks[pb.CSMsgID_NTF_KICK] = ntfKick
+	callbacks[pb.CSMsgID_NTF_RESYNC] = ntfResync
+	callbacks[pb.CSMsgID_NTF_CHAT] = ntfChat
+	callbacks[pb.CSMsgID_NTF_INBOX] = ntfInbox
+	callbacks[pb.CSMsgID_NTF_ROOM_ROLE] = ntfRoomRole
+	callbacks[pb.CSMsgID_NTF_ROOM_MUTE] = ntfRoomMute
+	callbacks[pb.CSMsgID_NTF_ROOM_KICKED] = ntfRoomKicked
+	callbacks[pb.CSMsgID_NTF_RENAME] = ntfRename
 }
 
 func router(id pb.CSMsgID, args ...interface{}) {
@@ -223,6 +343,44 @@ func router(id pb.CSMsgID, args ...interface{}) {
 	cb(p, body)
 }
 
+func rspHandshake(p *Player, body interface{}) {
+	rsp, ok := body.(*pb.CSRspBody)
+	if !ok {
+		return
+	}
+	if rsp.Handshake == nil || p.eph == nil {
+		return
+	}
+
+	// 应答未经认证, 指定了公钥时不能降级为明文, 以免泄露密码或令牌
+	if rsp.ErrCode != pb.ERROR_CODE_SUCCESS {
+		pureLog("handshake failed:%s", rsp.ErrMsg)
+		p.eph = nil
+		p.quit(1)
+		return
+	}
+
+	keys, e := p.eph.ClientFinish(serverIdentity, &kex.ServerHello{
+		PublicKey: rsp.Handshake.PublicKey,
+		Nonce:     rsp.Handshake.Nonce,
+		Signature: rsp.Handshake.Signature,
+	})
+	p.eph = nil
+	if e != nil {
+		pureLog("handshake failed:%s", e.Error())
+		p.quit(1)
+		return
+	}
+
+	if p.session, e = aes.NewSession(keys.ClientToServer, keys.ServerToClient); e != nil {
+		pureLog("new session failed:%s", e.Error())
+		p.quit(1)
+		return
+	}
+
+	p.login()
+}
+
 func rspLogin(p *Player, body interface{}) {
 	rsp, ok := body.(*pb.CSRspBody)
 	if !ok {
@@ -234,11 +392,22 @@ func rspLogin(p *Player, body interface{}) {
 
 	if rsp.ErrCode != pb.ERROR_CODE_SUCCESS {
 		pureLog("login failed:%s", rsp.ErrMsg)
-		p.OnClose(1)
+		p.quit(1)
 	} else {
 		p.username = rsp.Login.Username
+		p.compressor = compress.ID(rsp.Login.Codec)
+		resume.username = rsp.Login.Username
+		resume.token = rsp.Login.ResumeToken
+		if rsp.Login.Resumed {
+			pureLog("session resumed in room[%d]", rsp.Login.RoomID)
+		}
+
+		go p.heartbeat(time.Duration(rsp.Login.HeartbeatInterval) * time.Second)
 
 		p.userInput("say something:", func(input string) {
+			if p.command(input) {
+				return
+			}
 			p.send(pb.CSMsgID_REQ_ROOM_CHAT, &pb.CSReqBody{
 				RoomChat: &pb.CSReqRoomChat{Content: input},
 			})
@@ -255,22 +424,13 @@ func rspRoomList(p *Player, body interface{}) {
 		return
 	}
 
-	rooms := map[int64]struct{}{}
 	pureLog(`
                  ROOMS(%d):
 --------------------------------------------`, len(rsp.RoomList.Rooms))
 
 	for _, room := range rsp.RoomList.Rooms {
-		pureLog("|%d| -- ( %d/%d )", room.RoomID, room.CurrentMemberNum, room.TotalMemberNum)
-		rooms[room.RoomID] = struct{}{}
+		pureLog("|%d| %s -- ( %d/%d )", room.RoomID, room.Name, room.CurrentMemberNum, room.TotalMemberNum)
 	}
-
-	time.Sleep(time.Second * 2)
-	p.send(pb.CSMsgID_REQ_JOIN_ROOM, &pb.CSReqBody{
-		JoinRoom: &pb.CSReqJoinRoom{RoomID: int64(1)},
-	})
-
-	return
 }
 
 func rspJoinRoom(p *Player, body interface{}) {
@@ -287,7 +447,26 @@ func rspJoinRoom(p *Player, body interface{}) {
 		return
 	}
 
-	pureLog("finish joining room")
+	pureLog("finish joining room[%d] %s", rsp.JoinRoom.RoomID, rsp.JoinRoom.Name)
+}
+
+func rspLeaveRoom(p *Player, body interface{}) {
+	rsp, ok := body.(*pb.CSRspBody)
+	if !ok {
+		return
+	}
+
+	if rsp.ErrCode != pb.ERROR_CODE_SUCCESS {
+		pureLog("leave room failed:%s", rsp.ErrMsg)
+		return
+	}
+
+	if rsp.LeaveRoom == nil {
+		return
+	}
+
+	p.switchRoom(0)
+	pureLog("You left room[%d]", rsp.LeaveRoom.RoomID)
 }
 
 func rspRoomChat(p *Player, body interface{}) {
@@ -296,6 +475,11 @@ func rspRoomChat(p *Player, body interface{}) {
 		return
 	}
 
+	if rsp.ErrCode != pb.ERROR_CODE_SUCCESS {
+		pureLog("room chat failed:%s", rsp.ErrMsg)
+		return
+	}
+
 	if rsp.RoomChat == nil {
 		return
 	}
@@ -309,10 +493,16 @@ func ntfRoomChat(p *Player, body interface{}) {
 	if ntf.RoomChat == nil {
 		return
 	}
+	ackSeq(ntf.RoomChat.Seq)
+	p.seenMsgID(ntf.RoomChat.MsgID)
+	ts := time.Now()
+	if ntf.RoomChat.Time > 0 {
+		ts = time.Unix(0, ntf.RoomChat.Time*int64(time.Millisecond))
+	}
 	p.chats <- &chat{
 		content: ntf.RoomChat.Content,
 		from:    ntf.RoomChat.Username,
-		ts:      time.Now(),
+		ts:      ts,
 	}
 }
 
@@ -328,7 +518,16 @@ func ntfHistoryMsgs(p *Player, body interface{}) {
 
 	pureLog("room[%d] msgs:", ntf.HistoryMsg.RoomID)
 	for _, hm := range ntf.HistoryMsg.History {
-		printChat(p.username, hm.From, hm.Content, hm.Dt)
+		// 断线恢复时重发的消息可能已经显示过
+		if seen(ntf.HistoryMsg.RoomID, hm.Seq) {
+			continue
+		}
+		ackSeq(hm.Seq)
+		p.seenMsgID(hm.Id)
+		printChat(p.username, hm.From, hm.Content, chatTime(hm))
+	}
+	if ntf.HistoryMsg.More {
+		pureLog("#older to load older msgs")
 	}
 }
 
@@ -349,34 +548,137 @@ func ntfRoomMemberOnline(p *Player, body interface{}) {
 	}
 }
 
-func (p *Player) send(msgID pb.CSMsgID, body interface{}) {
-	bodyData, e := p.proc.Marshal([]interface{}{int32(msgID), body})
-	if e != nil {
-		pureLog(e.Error())
+func ntfRoomMemberLeave(p *Player, body interface{}) {
+	ntf, ok := body.(*pb.CSNtfBody)
+	if !ok {
+		return
+	}
+
+	if ntf.RoomMemberLeave == nil {
+		return
+	}
+
+	pureLog("%s left your room[%d]", ntf.RoomMemberLeave.Username, ntf.RoomMemberLeave.RoomID)
+}
+
+func ntfRoomMemberOffline(p *Player, body interface{}) {
+	ntf, ok := body.(*pb.CSNtfBody)
+	if !ok {
 		return
 	}
 
-	bodyLen := int32(len(bodyData))
+	if ntf.RoomMemberOffline == nil {
+		return
+	}
 
-	h := &pb.CSHead{
-		MsgID:        msgID,
-		BodyLen:      bodyLen,
-		IsCompressed: false,
+	pureLog("%s went offline in room[%d]", ntf.RoomMemberOffline.Username, ntf.RoomMemberOffline.RoomID)
+}
+
+func ntfRoomPresence(p *Player, body interface{}) {
+	ntf, ok := body.(*pb.CSNtfBody)
+	if !ok {
+		return
 	}
 
-	headData, err := p.proc.Marshal([]interface{}{int32(msgID), h})
-	if err != nil {
-		pureLog("Marshal head error:" + err.Error())
+	presence := ntf.RoomPresence
+	if presence == nil {
 		return
 	}
-	headLen := int32(len(headData))
 
-	data := make([]byte, 1+headLen+bodyLen)
-	data[0] = byte(headLen)
+	if presence.Snapshot {
+		p.switchRoom(presence.RoomID)
+	}
+	if presence.Snapshot || p.members == nil {
+		p.members = map[string]int64{}
+	}
+	for _, m := range presence.Joined {
+		p.members[m.Username] = m.JoinTime
+	}
+	for _, name := range presence.Left {
+		delete(p.members, name)
+	}
 
-	copy(data[1:], headData)
-	if bodyLen != 0 {
-		copy(data[1+headLen:], bodyData)
+	if presence.Snapshot {
+		pureLog("room[%d] has %d members", presence.RoomID, len(p.members))
+	}
+}
+
+func rspRoomMembers(p *Player, body interface{}) {
+	rsp, ok := body.(*pb.CSRspBody)
+	if !ok {
+		return
+	}
+
+	if rsp.ErrCode != pb.ERROR_CODE_SUCCESS {
+		pureLog("get room members failed:%s", rsp.ErrMsg)
+		return
+	}
+
+	if rsp.RoomMembers == nil {
+		return
+	}
+
+	pureLog("room[%d] members(%d):", rsp.RoomMembers.RoomID, len(rsp.RoomMembers.Members))
+	for _, m := range rsp.RoomMembers.Members {
+		if m.Role != pb.ROOM_ROLE_ROLE_MEMBER {
+			pureLog("  %s(%s) joined at %s", m.Username, roleName(m.Role), msTime(m.JoinTime))
+		} else {
+			pureLog("  %s joined at %s", m.Username, msTime(m.JoinTime))
+		}
+	}
+}
+
+func ntfRoomClosed(p *Player, body interface{}) {
+	ntf, ok := body.(*pb.CSNtfBody)
+	if !ok {
+		return
+	}
+
+	if ntf.RoomClosed == nil {
+		return
+	}
+
+	p.switchRoom(0)
+	pureLog("room[%d] closed", ntf.RoomClosed.RoomID)
+}
+
+func ntfKick(p *Player, body interface{}) {
+	ntf, ok := body.(*pb.CSNtfBody)
+	if !ok {
+		return
+	}
+
+	if ntf.Kick == nil {
+		return
+	}
+
+	pureLog("kicked by server[%s]: %s", ntf.Kick.Reason, ntf.Kick.Msg)
+	// 被管理员踢出、因刷屏被踢或被封禁后不再重连
+	switch ntf.Kick.Reason {
+	case pb.KICK_REASON_KICK_BY_ADMIN, pb.KICK_REASON_KICK_FLOOD, pb.KICK_REASON_KICK_BANNED:
+		client.Close()
+	}
+}
+
+func (p *Player) send(msgID pb.CSMsgID, body proto.Message) {
+	p.Lock()
+	defer p.Unlock()
+	if p.closed {
+		return
+	}
+
+	// 加密与写入需在同一把锁内, 保证帧序号有序
+	var c codec.Cipher
+	if p.session != nil {
+		p.session.Lock()
+		defer p.session.Unlock()
+		c = p.session
+	}
+
+	data, e := p.enc.EncodeMsg(codec.FrameV1, msgID, body, p.compressor, c)
+	if e != nil {
+		pureLog("encode %s failed:%s", msgID, e.Error())
+		return
 	}
 
 	e = p.conn.Write(data)
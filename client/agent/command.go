@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"cloudcadetest/pb"
+	"strconv"
+	"strings"
+)
+
+// 以#开头的输入为客户端命令, 其余作为房间聊天发送
+//
+//	#rooms          房间列表
+//	#join <id>      加入指定房间, id为0时自动选择
+//	#create [name]  新建房间并加入
+//	#leave          离开当前房间
+//	#members        当前房间成员
+//	#older [n]      向前翻页加载更早的历史消息, n为条数
+//	#search [@user] <words>  搜索当前房间的历史消息, 双引号内为短语, @user只搜该用户的发言
+//	#more           上次搜索结果的下一页
+//	#dm <user> <content>  私聊, 对方不在线时存入其收件箱
+//	#role <user> <member|moderator|owner>  在当前房间任免管理员或转让房间
+//	#mute <user> <seconds>  在当前房间禁言, #unmute <user> 解除
+//	#kick <user> [reason]   移出当前房间
+//	#ban <user> [seconds] [reason]  封禁用户名, 需要服务器管理员; #banip同时封禁其IP, #unban <user> 解除
+//	#name <user>    改名, #reserve 保留当前名字, 之后登录需带上下发的密钥
+func (p *Player) command(input string) bool {
+	if !strings.HasPrefix(input, "#") {
+		return false
+	}
+
+	fields := strings.Fields(input[1:])
+	if len(fields) == 0 {
+		return false
+	}
+
+	switch fields[0] {
+	case "rooms":
+		p.send(pb.CSMsgID_REQ_ROOM_LIST, &pb.CSReqBody{
+			RoomList: &pb.CSReqRoomList{MaxRoomCount: 100},
+		})
+	case "join":
+		if len(fields) != 2 {
+			pureLog("usage: #join <room id>")
+			return true
+		}
+		id, e := strconv.ParseInt(fields[1], 10, 64)
+		if e != nil {
+			pureLog("invalid room id:%s", fields[1])
+			return true
+		}
+		p.send(pb.CSMsgID_REQ_JOIN_ROOM, &pb.CSReqBody{
+			JoinRoom: &pb.CSReqJoinRoom{RoomID: id},
+		})
+	case "create":
+		p.send(pb.CSMsgID_REQ_JOIN_ROOM, &pb.CSReqBody{
+			JoinRoom: &pb.CSReqJoinRoom{CreateNew: true, Name: strings.Join(fields[1:], " ")},
+		})
+	case "leave":
+		p.send(pb.CSMsgID_REQ_LEAVE_ROOM, &pb.CSReqBody{
+			LeaveRoom: &pb.CSReqLeaveRoom{},
+		})
+	case "members":
+		p.send(pb.CSMsgID_REQ_ROOM_MEMBERS, &pb.CSReqBody{
+			RoomMembers: &pb.CSReqRoomMembers{},
+		})
+	case "older":
+		var limit int64
+		if len(fields) > 1 {
+			n, e := strconv.ParseInt(fields[1], 10, 32)
+			if e != nil || n <= 0 {
+				pureLog("usage: #older [count]")
+				return true
+			}
+			limit = n
+		}
+		p.loadOlder(int32(limit))
+	case "search":
+		p.search(fields[1:])
+	case "more":
+		p.searchMore()
+	case "dm":
+		p.privateChat(fields[1:])
+	case "role":
+		p.setRole(fields[1:])
+	case "mute", "unmute":
+		p.roomMute(fields[1:], fields[0] == "unmute")
+	case "kick":
+		p.roomKick(fields[1:])
+	case "ban", "banip", "unban":
+		p.ban(fields[1:], fields[0] == "banip", fields[0] == "unban")
+	case "name", "reserve":
+		p.setUsername(fields[1:], fields[0] == "reserve")
+	default:
+		return false
+	}
+	return true
+}
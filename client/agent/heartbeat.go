@@ -0,0 +1,57 @@
+package agent
+
+import (
+	"cloudcadetest/pb"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultHeartbeatInterval = 10 * time.Second
+	deadHeartbeats           = 3 // 连续多少个心跳间隔未收到任何消息视为服务端失联
+	slowRTT                  = time.Second
+)
+
+// 登录成功后定时发送心跳, 服务端失联时关闭连接触发重连
+func (p *Player) heartbeat(interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case now := <-t.C:
+			last := time.Unix(0, atomic.LoadInt64(&p.lastRecv))
+			if now.Sub(last) > interval*deadHeartbeats {
+				pureLog("server not responding for %s, reconnecting", now.Sub(last).Truncate(time.Second))
+				p.OnClose(2)
+				return
+			}
+
+			p.send(pb.CSMsgID_REQ_HEARTBEAT, &pb.CSReqBody{Heartbeat: &pb.CSReqHeartbeat{
+				ClientTime: now.UnixNano() / int64(time.Millisecond),
+				Ack:        atomic.LoadInt64(&p.acked),
+			}})
+		}
+	}
+}
+
+func rspHeartbeat(p *Player, body interface{}) {
+	rsp, ok := body.(*pb.CSRspBody)
+	if !ok {
+		return
+	}
+	if rsp.Heartbeat == nil {
+		return
+	}
+
+	rtt := time.Duration(time.Now().UnixNano()/int64(time.Millisecond)-rsp.Heartbeat.ClientTime) * time.Millisecond
+	if rtt > slowRTT {
+		pureLog("high latency:%s", rtt)
+	}
+}
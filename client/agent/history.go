@@ -0,0 +1,71 @@
+package agent
+
+import (
+	"cloudcadetest/pb"
+	"sync/atomic"
+	"time"
+)
+
+// 换了房间时清空已显示的最早消息
+func (p *Player) switchRoom(roomID int64) {
+	if resetSeq(roomID) {
+		atomic.StoreInt64(&p.oldestID, 0)
+	}
+}
+
+// 记录已显示的最早消息ID, 作为向前翻页的起点
+func (p *Player) seenMsgID(id int64) {
+	if id <= 0 {
+		return
+	}
+	if old := atomic.LoadInt64(&p.oldestID); old == 0 || id < old {
+		atomic.StoreInt64(&p.oldestID, id)
+	}
+}
+
+// 拉取已显示的最早消息之前的一页, 还没有显示过消息时取最新的一页
+func (p *Player) loadOlder(limit int32) {
+	p.send(pb.CSMsgID_REQ_ROOM_HISTORY, &pb.CSReqBody{
+		RoomHistory: &pb.CSReqRoomHistory{
+			BeforeID: atomic.LoadInt64(&p.oldestID),
+			Limit:    limit,
+		},
+	})
+}
+
+func rspRoomHistory(p *Player, body interface{}) {
+	rsp, ok := body.(*pb.CSRspBody)
+	if !ok {
+		return
+	}
+
+	if rsp.ErrCode != pb.ERROR_CODE_SUCCESS {
+		pureLog("load history failed:%s", rsp.ErrMsg)
+		return
+	}
+
+	page := rsp.RoomHistory
+	if page == nil {
+		return
+	}
+
+	if len(page.History) == 0 {
+		pureLog("room[%d] has no older msgs", page.RoomID)
+		return
+	}
+	pureLog("room[%d] older msgs:", page.RoomID)
+	for _, hm := range page.History {
+		p.seenMsgID(hm.Id)
+		printChat(p.username, hm.From, hm.Content, chatTime(hm))
+	}
+	if page.More {
+		pureLog("#older to load more")
+	}
+}
+
+func chatTime(hm *pb.HistoryChat) string {
+	if hm.Time > 0 {
+		return time.Unix(0, hm.Time*int64(time.Millisecond)).Format("2006-01-02 15:04:05")
+	}
+	return hm.Dt
+}
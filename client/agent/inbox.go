@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"cloudcadetest/pb"
+	"strings"
+	"time"
+)
+
+// 已确认的收件箱消息ID, 确认丢失后重复下发的消息不再显示
+var inboxRead int64
+
+func (p *Player) privateChat(args []string) {
+	if len(args) < 2 {
+		pureLog("usage: #dm <username> <content>")
+		return
+	}
+	p.send(pb.CSMsgID_REQ_CHAT, &pb.CSReqBody{
+		Chat: &pb.CSReqChat{Username: args[0], Content: strings.Join(args[1:], " ")},
+	})
+}
+
+func printPrivate(from, content string, ms int64) {
+	ts := time.Unix(0, ms*int64(time.Millisecond)).Format("2006-01-02 15:04:05")
+	pureLog("[%s] %s whispers: %s", ts, from, content)
+}
+
+func rspChat(p *Player, body interface{}) {
+	rsp, ok := body.(*pb.CSRspBody)
+	if !ok {
+		return
+	}
+
+	if rsp.ErrCode != pb.ERROR_CODE_SUCCESS {
+		pureLog("private chat failed:%s", rsp.ErrMsg)
+		return
+	}
+	if rsp.Chat != nil && rsp.Chat.Stored {
+		pureLog("target is offline, message saved to inbox")
+	}
+}
+
+func ntfChat(p *Player, body interface{}) {
+	ntf, ok := body.(*pb.CSNtfBody)
+	if !ok {
+		return
+	}
+	if ntf.Chat == nil {
+		return
+	}
+	printPrivate(ntf.Chat.From, ntf.Chat.Content, ntf.Chat.Time)
+}
+
+func ntfInbox(p *Player, body interface{}) {
+	ntf, ok := body.(*pb.CSNtfBody)
+	if !ok {
+		return
+	}
+	if ntf.Inbox == nil || len(ntf.Inbox.Msgs) == 0 {
+		return
+	}
+
+	for _, u := range ntf.Inbox.Unread {
+		pureLog("%d unread msgs from %s", u.Count, u.From)
+	}
+	for _, msg := range ntf.Inbox.Msgs {
+		if msg.ID <= inboxRead {
+			continue
+		}
+		printPrivate(msg.From, msg.Content, msg.Time)
+	}
+
+	inboxRead = ntf.Inbox.Msgs[len(ntf.Inbox.Msgs)-1].ID
+	p.send(pb.CSMsgID_REQ_INBOX_READ, &pb.CSReqBody{
+		InboxRead: &pb.CSReqInboxRead{UpToID: inboxRead},
+	})
+}
+
+func rspInboxRead(p *Player, body interface{}) {
+	rsp, ok := body.(*pb.CSRspBody)
+	if !ok {
+		return
+	}
+
+	if rsp.ErrCode != pb.ERROR_CODE_SUCCESS {
+		pureLog("mark inbox read failed:%s", rsp.ErrMsg)
+	}
+}
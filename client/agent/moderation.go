@@ -0,0 +1,138 @@
+package agent
+
+import (
+	"cloudcadetest/pb"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var roleNames = map[string]pb.ROOM_ROLE{
+	"member":    pb.ROOM_ROLE_ROLE_MEMBER,
+	"moderator": pb.ROOM_ROLE_ROLE_MODERATOR,
+	"owner":     pb.ROOM_ROLE_ROLE_OWNER,
+}
+
+func roleName(role pb.ROOM_ROLE) string {
+	return strings.ToLower(strings.TrimPrefix(role.String(), "ROLE_"))
+}
+
+func msTime(ms int64) string {
+	return time.Unix(0, ms*int64(time.Millisecond)).Format("2006-01-02 15:04:05")
+}
+
+func (p *Player) setRole(args []string) {
+	if len(args) != 2 {
+		pureLog("usage: #role <username> <member|moderator|owner>")
+		return
+	}
+	role, ok := roleNames[args[1]]
+	if !ok {
+		pureLog("unknown role %s", args[1])
+		return
+	}
+	p.send(pb.CSMsgID_REQ_SET_ROLE, &pb.CSReqBody{
+		SetRole: &pb.CSReqSetRole{Username: args[0], Role: role},
+	})
+}
+
+// seconds为0时解除禁言
+func (p *Player) roomMute(args []string, unmute bool) {
+	var secs int64
+	if unmute {
+		if len(args) != 1 {
+			pureLog("usage: #unmute <username>")
+			return
+		}
+	} else {
+		var e error
+		if len(args) == 2 {
+			secs, e = strconv.ParseInt(args[1], 10, 64)
+		}
+		if len(args) != 2 || e != nil || secs <= 0 {
+			pureLog("usage: #mute <username> <seconds>")
+			return
+		}
+	}
+	p.send(pb.CSMsgID_REQ_ROOM_MUTE, &pb.CSReqBody{
+		RoomMute: &pb.CSReqRoomMute{Username: args[0], Seconds: secs},
+	})
+}
+
+func (p *Player) roomKick(args []string) {
+	if len(args) == 0 {
+		pureLog("usage: #kick <username> [reason]")
+		return
+	}
+	p.send(pb.CSMsgID_REQ_ROOM_KICK, &pb.CSReqBody{
+		RoomKick: &pb.CSReqRoomKick{Username: args[0], Reason: strings.Join(args[1:], " ")},
+	})
+}
+
+// #ban <username> [seconds] [reason], withIP时同时封禁对方当前的IP
+func (p *Player) ban(args []string, withIP, lift bool) {
+	if len(args) == 0 {
+		if lift {
+			pureLog("usage: #unban <username>")
+		} else {
+			pureLog("usage: #ban|#banip <username> [seconds] [reason]")
+		}
+		return
+	}
+
+	req := &pb.CSReqBan{Username: args[0], WithIP: withIP, Lift: lift}
+	if !lift {
+		rest := args[1:]
+		if len(rest) > 0 {
+			if secs, e := strconv.ParseInt(rest[0], 10, 64); e == nil && secs >= 0 {
+				req.Seconds = secs
+				rest = rest[1:]
+			}
+		}
+		req.Reason = strings.Join(rest, " ")
+	}
+	p.send(pb.CSMsgID_REQ_BAN, &pb.CSReqBody{Ban: req})
+}
+
+func rspModerate(p *Player, body interface{}) {
+	rsp, ok := body.(*pb.CSRspBody)
+	if !ok {
+		return
+	}
+	if rsp.ErrCode != pb.ERROR_CODE_SUCCESS {
+		pureLog("failed[%s]:%s", rsp.ErrCode, rsp.ErrMsg)
+		return
+	}
+	if rsp.Ban != nil {
+		pureLog("ban updated")
+	}
+}
+
+func ntfRoomRole(p *Player, body interface{}) {
+	ntf, ok := body.(*pb.CSNtfBody)
+	if !ok || ntf.RoomRole == nil {
+		return
+	}
+	pureLog("%s is now %s of room[%d], set by %s", ntf.RoomRole.Username, roleName(ntf.RoomRole.Role), ntf.RoomRole.RoomID, ntf.RoomRole.By)
+}
+
+func ntfRoomMute(p *Player, body interface{}) {
+	ntf, ok := body.(*pb.CSNtfBody)
+	if !ok || ntf.RoomMute == nil {
+		return
+	}
+	if ntf.RoomMute.Until == 0 {
+		pureLog("%s was unmuted in room[%d] by %s", ntf.RoomMute.Username, ntf.RoomMute.RoomID, ntf.RoomMute.By)
+		return
+	}
+	pureLog("%s was muted in room[%d] by %s until %s", ntf.RoomMute.Username, ntf.RoomMute.RoomID, ntf.RoomMute.By, msTime(ntf.RoomMute.Until))
+}
+
+func ntfRoomKicked(p *Player, body interface{}) {
+	ntf, ok := body.(*pb.CSNtfBody)
+	if !ok || ntf.RoomKicked == nil {
+		return
+	}
+	p.switchRoom(0)
+	pureLog("You were kicked from room[%d] by %s: %s", ntf.RoomKicked.RoomID, ntf.RoomKicked.By, ntf.RoomKicked.Reason)
+}
@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"cloudcadetest/pb"
+)
+
+// #name <username> 改名, #reserve 保留当前名字
+func (p *Player) setUsername(args []string, reserve bool) {
+	req := &pb.CSReqSetUsername{Username: p.username, Reserve: reserve}
+	if !reserve {
+		if len(args) != 1 {
+			pureLog("usage: #name <username>")
+			return
+		}
+		req.Username = args[0]
+	}
+	p.send(pb.CSMsgID_REQ_SET_USERNAME, &pb.CSReqBody{SetUsername: req})
+}
+
+// 重连时以新名字登录
+func rspSetUsername(p *Player, body interface{}) {
+	rsp, ok := body.(*pb.CSRspBody)
+	if !ok || rsp.SetUsername == nil {
+		return
+	}
+	if rsp.ErrCode != pb.ERROR_CODE_SUCCESS {
+		pureLog("set username failed:%s", rsp.ErrMsg)
+	}
+	if name := rsp.SetUsername.Username; name != "" && name != p.username {
+		p.username = name
+		Username = name
+		resume.username = name
+		NameKey = ""
+	}
+	if rsp.SetUsername.NameKey != "" {
+		NameKey = rsp.SetUsername.NameKey
+		pureLog("name %s reserved, log in with -name_key %s", p.username, NameKey)
+	}
+}
+
+func ntfRename(p *Player, body interface{}) {
+	ntf, ok := body.(*pb.CSNtfBody)
+	if !ok || ntf.Rename == nil {
+		return
+	}
+	if ntf.Rename.OldName == p.username || ntf.Rename.NewName == p.username {
+		pureLog("You are now known as %s", ntf.Rename.NewName)
+		return
+	}
+	pureLog("%s is now known as %s", ntf.Rename.OldName, ntf.Rename.NewName)
+}
@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"cloudcadetest/pb"
+)
+
+// 断线重连时用于恢复会话, 只在连接所在的协程中读写
+var resume struct {
+	username string
+	token    string
+	roomID   int64 // 序号所属的房间
+	lastSeq  int64 // 已收到的最后一条房间消息序号
+	ack      int64 // 已连续收到的最大下行序号
+}
+
+// 进入房间时收到成员快照, 换了房间则序号重新计数并返回true
+func resetSeq(roomID int64) bool {
+	if resume.roomID == roomID {
+		return false
+	}
+	resume.roomID = roomID
+	resume.lastSeq = 0
+	return true
+}
+
+func seen(roomID, seq int64) bool {
+	return seq != 0 && roomID == resume.roomID && seq <= resume.lastSeq
+}
+
+func ackSeq(seq int64) {
+	if seq > resume.lastSeq {
+		resume.lastSeq = seq
+	}
+}
+
+// 服务端的重传缓冲已丢弃部分消息, 跳过缺口并重新拉取房间成员
+func ntfResync(p *Player, body interface{}) {
+	ntf, ok := body.(*pb.CSNtfBody)
+	if !ok {
+		return
+	}
+	if ntf.Resync == nil {
+		return
+	}
+
+	p.window.Skip(ntf.Resync.NextSeq)
+	p.updateAck()
+	pureLog("missed %d messages, resyncing", ntf.Resync.Missed)
+	p.send(pb.CSMsgID_REQ_ROOM_MEMBERS, &pb.CSReqBody{
+		RoomMembers: &pb.CSReqRoomMembers{},
+	})
+}
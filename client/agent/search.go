@@ -0,0 +1,70 @@
+package agent
+
+import (
+	"cloudcadetest/pb"
+	"strings"
+)
+
+// #search [@用户名] 关键词, 双引号内为短语
+func (p *Player) search(args []string) {
+	req := &pb.CSReqSearchHistory{}
+	if len(args) > 0 && strings.HasPrefix(args[0], "@") {
+		req.From = args[0][1:]
+		args = args[1:]
+	}
+	req.Query = strings.Join(args, " ")
+	if req.Query == "" && req.From == "" {
+		pureLog(`usage: #search [@username] words or "phrase"`)
+		return
+	}
+
+	p.nextSearch.Store((*pb.CSReqSearchHistory)(nil))
+	p.lastSearch.Store(req)
+	p.send(pb.CSMsgID_REQ_SEARCH_HISTORY, &pb.CSReqBody{SearchHistory: req})
+}
+
+// 上次搜索结果的下一页
+func (p *Player) searchMore() {
+	req, _ := p.nextSearch.Load().(*pb.CSReqSearchHistory)
+	if req == nil {
+		pureLog("no more results")
+		return
+	}
+	p.nextSearch.Store((*pb.CSReqSearchHistory)(nil))
+	p.lastSearch.Store(req)
+	p.send(pb.CSMsgID_REQ_SEARCH_HISTORY, &pb.CSReqBody{SearchHistory: req})
+}
+
+func rspSearchHistory(p *Player, body interface{}) {
+	rsp, ok := body.(*pb.CSRspBody)
+	if !ok {
+		return
+	}
+
+	if rsp.ErrCode != pb.ERROR_CODE_SUCCESS {
+		pureLog("search failed:%s", rsp.ErrMsg)
+		return
+	}
+
+	ret := rsp.SearchHistory
+	if ret == nil {
+		return
+	}
+
+	if len(ret.Results) == 0 {
+		pureLog("room[%d] no matched msgs", ret.RoomID)
+		return
+	}
+	pureLog("room[%d] matched msgs:", ret.RoomID)
+	for _, hm := range ret.Results {
+		printChat(p.username, hm.From, hm.Content, chatTime(hm))
+	}
+
+	last, _ := p.lastSearch.Load().(*pb.CSReqSearchHistory)
+	if ret.More && last != nil {
+		next := *last
+		next.BeforeID = ret.Results[len(ret.Results)-1].Id
+		p.nextSearch.Store(&next)
+		pureLog("#more for older results")
+	}
+}
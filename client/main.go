@@ -2,10 +2,20 @@ package main
 
 import (
 	"cloudcadetest/client/agent"
+	"flag"
 	_ "net/http/pprof"
 )
 
 func main() {
+	flag.StringVar(&agent.ServerAddr, "addr", agent.ServerAddr, "chat server address")
+	flag.StringVar(&agent.ServerPubKeyFile, "server_pubkey", "", "server identity public key, enables encryption")
+	flag.StringVar(&agent.Username, "username", "", "login name, random if empty")
+	flag.StringVar(&agent.NameKey, "name_key", "", "key of a reserved login name")
+	flag.StringVar(&agent.Password, "password", "", "account password if the server requires auth")
+	flag.StringVar(&agent.AuthToken, "token", "", "token issued by web login, instead of password")
+	flag.BoolVar(&agent.AllowPlaintext, "plaintext", false, "allow sending password or token without encryption, conflicts with -server_pubkey")
+	flag.Parse()
+
 	//go func() {
 	//	fmt.Println(http.ListenAndServe("localhost:6060", nil))
 	//}()
@@ -0,0 +1,105 @@
+package compress
+
+import (
+	"bytes"
+	"cloudcadetest/common/compress/zlib"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+func init() {
+	Register(Zlib, "zlib", zlibCompressor{})
+	Register(Flate, "flate", newFlate(nil, flate.DefaultCompression))
+	Register(Gzip, "gzip", &gzipCompressor{})
+	// 小于128字节的输入只有最高压缩等级才会引用字典
+	Register(FlateDict, "flate_dict", newFlate(ChatDict, flate.BestCompression))
+}
+
+type zlibCompressor struct{}
+
+func (zlibCompressor) Compress(src []byte) ([]byte, error) {
+	return zlib.Compress(src)
+}
+
+func (zlibCompressor) Decompress(src []byte) ([]byte, error) {
+	return zlib.Decompress(src)
+}
+
+// 原始deflate流, dict不为空时双方使用同一份预置字典
+type flateCompressor struct {
+	dict    []byte
+	level   int
+	writers sync.Pool
+}
+
+func newFlate(dict []byte, level int) *flateCompressor {
+	f := &flateCompressor{dict: dict, level: level}
+	f.writers.New = func() interface{} {
+		w, err := flate.NewWriterDict(nil, f.level, f.dict)
+		if err != nil {
+			panic(err)
+		}
+		return w
+	}
+	return f
+}
+
+func (f *flateCompressor) Compress(src []byte) ([]byte, error) {
+	var out bytes.Buffer
+	w := f.writers.Get().(*flate.Writer)
+	defer f.writers.Put(w)
+
+	w.Reset(&out)
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func (f *flateCompressor) Decompress(src []byte) ([]byte, error) {
+	r := flate.NewReaderDict(bytes.NewReader(src), f.dict)
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+type gzipCompressor struct {
+	writers sync.Pool
+}
+
+func (g *gzipCompressor) Compress(src []byte) ([]byte, error) {
+	var out bytes.Buffer
+	w, ok := g.writers.Get().(*gzip.Writer)
+	if ok {
+		w.Reset(&out)
+	} else {
+		w = gzip.NewWriter(&out)
+	}
+	defer g.writers.Put(w)
+
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func (g *gzipCompressor) Decompress(src []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if _, err = io.Copy(&out, r); err != nil {
+		return nil, err
+	}
+	err = r.Close()
+	return out.Bytes(), err
+}
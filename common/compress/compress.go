@@ -0,0 +1,101 @@
+package compress
+
+import (
+	"sort"
+	"sync"
+)
+
+// 压缩算法编号, 会写入消息头, 与pb.COMPRESS_CODEC保持一致
+// 已发布的编号及其参数(如字典内容)不能再修改, 变更时需使用新编号
+type ID int32
+
+const (
+	None      ID = 0
+	Zlib      ID = 1
+	Flate     ID = 2
+	Gzip      ID = 3
+	FlateDict ID = 4
+)
+
+type Compressor interface {
+	Compress(src []byte) ([]byte, error)
+	Decompress(src []byte) ([]byte, error)
+}
+
+type entry struct {
+	name string
+	c    Compressor
+}
+
+var (
+	mutex    sync.RWMutex
+	registry = map[ID]entry{}
+)
+
+// 注册压缩算法, 编号重复时panic
+func Register(id ID, name string, c Compressor) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if id == None || c == nil {
+		panic("compress: invalid compressor " + name)
+	}
+	if _, ok := registry[id]; ok {
+		panic("compress: duplicate compressor " + name)
+	}
+	registry[id] = entry{name: name, c: c}
+}
+
+func Get(id ID) Compressor {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return registry[id].c
+}
+
+func Name(id ID) string {
+	if id == None {
+		return "none"
+	}
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return registry[id].name
+}
+
+func ByName(name string) (ID, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	for id, e := range registry {
+		if e.name == name {
+			return id, true
+		}
+	}
+	return None, false
+}
+
+// 已注册的全部算法, 按编号排序
+func IDs() []ID {
+	mutex.RLock()
+	ids := make([]ID, 0, len(registry))
+	for id := range registry {
+		ids = append(ids, id)
+	}
+	mutex.RUnlock()
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// 按本端的偏好顺序选出对端也支持的第一个算法, 没有则返回None
+func Select(preferred, offered []ID) ID {
+	for _, p := range preferred {
+		if Get(p) == nil {
+			continue
+		}
+		for _, o := range offered {
+			if p == o {
+				return p
+			}
+		}
+	}
+	return None
+}
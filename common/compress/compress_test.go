@@ -0,0 +1,66 @@
+package compress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCompressors_RoundTrip(t *testing.T) {
+	inputs := [][]byte{
+		[]byte("hello everyone"),
+		[]byte(strings.Repeat("大家好, see you tomorrow ", 100)),
+		bytes.Repeat([]byte{0}, 4096),
+	}
+
+	for _, id := range IDs() {
+		c := Get(id)
+		for _, in := range inputs {
+			z, err := c.Compress(in)
+			if err != nil {
+				t.Fatalf("%s compress:%v", Name(id), err)
+			}
+			out, err := c.Decompress(z)
+			if err != nil {
+				t.Fatalf("%s decompress:%v", Name(id), err)
+			}
+			if !bytes.Equal(in, out) {
+				t.Fatalf("%s round trip mismatch", Name(id))
+			}
+		}
+	}
+}
+
+// 预置字典对短小的聊天消息应明显优于普通deflate
+func TestFlateDict_ShortChat(t *testing.T) {
+	msg := []byte("hello everyone, anyone here? 大家好, 一起玩吗")
+	plain, _ := Get(Flate).Compress(msg)
+	dict, _ := Get(FlateDict).Compress(msg)
+	if len(dict) >= len(plain) {
+		t.Fatalf("dict:%d plain:%d", len(dict), len(plain))
+	}
+}
+
+func TestTrainDict(t *testing.T) {
+	d1 := TrainDict(chatCorpus, dictSize)
+	d2 := TrainDict(chatCorpus, dictSize)
+	if !bytes.Equal(d1, d2) {
+		t.Fatal("dictionary must be deterministic")
+	}
+	if len(d1) == 0 || len(d1) > dictSize {
+		t.Fatalf("dictionary size %d", len(d1))
+	}
+	if !bytes.Contains(d1, []byte("hello")) {
+		t.Fatal("frequent piece missing from dictionary")
+	}
+}
+
+func TestSelect(t *testing.T) {
+	pref := []ID{FlateDict, Flate, Zlib}
+	if id := Select(pref, []ID{Gzip, Zlib, Flate}); id != Flate {
+		t.Fatalf("got %s", Name(id))
+	}
+	if id := Select(pref, []ID{Gzip}); id != None {
+		t.Fatalf("got %s", Name(id))
+	}
+}
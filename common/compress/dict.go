@@ -0,0 +1,102 @@
+package compress
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	dictSize      = 4 << 10
+	minPieceLen   = 4
+	maxPieceLen   = 32
+	minPieceCount = 2
+)
+
+// 训练用的聊天语料, 与协议中常见的字段内容相近
+// 修改语料会改变字典, 需同时启用新的压缩编号
+var chatCorpus = []string{
+	"hello everyone", "hello, how are you?", "hi there", "good morning", "good night",
+	"how is it going?", "what are you doing?", "see you later", "see you tomorrow",
+	"thank you", "thanks a lot", "you are welcome", "no problem", "sounds good",
+	"I don't know", "I think so", "let's go", "are you there?", "nice to meet you",
+	"what's up", "lol", "haha", "hahaha", "ok", "okay", "yes", "no", "sure",
+	"anyone here?", "who wants to play?", "join my room", "welcome to the room",
+	"test_", "said: ", "joined your room", "room", "message", "username",
+	" +0000 UTC m=+", "2026-01-01 00:00:00",
+	"你好", "大家好", "你好吗", "早上好", "晚上好", "晚安", "谢谢", "不客气",
+	"没问题", "好的", "是的", "不是", "哈哈", "哈哈哈", "有人吗", "在吗", "在的",
+	"一起玩吗", "欢迎来到房间", "我也是", "我不知道", "我觉得", "明天见", "回头见",
+	"什么时候", "为什么", "怎么了", "太好了", "加油",
+}
+
+// 字典预置在压缩器中, 使短小的聊天消息也能获得较好的压缩率
+var ChatDict = TrainDict(chatCorpus, dictSize)
+
+type piece struct {
+	s     string
+	score int
+}
+
+// 统计语料中重复出现的片段, 按节省的字节数挑选直到填满size
+// 得分高的放在字典末尾, deflate引用距离越近编码越短
+func TrainDict(samples []string, size int) []byte {
+	counts := map[string]int{}
+	for _, s := range samples {
+		seen := map[string]bool{}
+		for i := 0; i < len(s); i++ {
+			for l := minPieceLen; l <= maxPieceLen && i+l <= len(s); l++ {
+				p := s[i : i+l]
+				if !seen[p] {
+					seen[p] = true
+					counts[p]++
+				}
+			}
+		}
+	}
+
+	pieces := make([]piece, 0, len(counts))
+	for s, n := range counts {
+		if n >= minPieceCount {
+			pieces = append(pieces, piece{s: s, score: n * (len(s) - minPieceLen + 1)})
+		}
+	}
+	// 单条样本整体也纳入, 保证常用短语完整出现
+	for _, s := range samples {
+		if len(s) >= minPieceLen && counts[s] < minPieceCount {
+			pieces = append(pieces, piece{s: s, score: len(s) - minPieceLen + 1})
+		}
+	}
+	sort.Slice(pieces, func(i, j int) bool {
+		if pieces[i].score != pieces[j].score {
+			return pieces[i].score > pieces[j].score
+		}
+		return pieces[i].s < pieces[j].s
+	})
+
+	var (
+		chosen []string
+		total  int
+	)
+	for _, p := range pieces {
+		if total+len(p.s) > size || contained(chosen, p.s) {
+			continue
+		}
+		chosen = append(chosen, p.s)
+		total += len(p.s)
+	}
+
+	dict := make([]byte, 0, total)
+	for i := len(chosen) - 1; i >= 0; i-- {
+		dict = append(dict, chosen[i]...)
+	}
+	return dict
+}
+
+func contained(chosen []string, s string) bool {
+	for _, c := range chosen {
+		if strings.Contains(c, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"cloudcadetest/framework/log"
 	"os"
+	"strings"
 	"unicode/utf8"
 )
 
@@ -15,6 +16,15 @@ type Trie struct {
 type trieNode struct {
 	children map[rune]*trieNode
 	end      bool
+	category string // 词的分类, 词表中以tab分隔, 可为空
+}
+
+// 原文中命中的一个词, 位置按rune计算, 范围内可能夹杂不在词中的字符
+type Match struct {
+	Start    int    `json:"start"`
+	End      int    `json:"end"` // 不含
+	Word     string `json:"word"`
+	Category string `json:"category,omitempty"`
 }
 
 func New() *Trie {
@@ -31,11 +41,17 @@ func newNode() *trieNode {
 }
 
 func (t *Trie) InsertFile(path string) {
-	f, e := os.Open(path)
-	if e != nil {
+	if e := t.LoadFile(path); e != nil {
 		wd, _ := os.Getwd()
 		log.Warn("wd:%s, e:%s", wd, e.Error())
-		return
+	}
+}
+
+// 按行读入词表, 每行为 词[\t分类], 文件无法打开时返回错误
+func (t *Trie) LoadFile(path string) error {
+	f, e := os.Open(path)
+	if e != nil {
+		return e
 	}
 
 	defer func() {
@@ -50,17 +66,26 @@ func (t *Trie) InsertFile(path string) {
 		if err != nil {
 			break
 		}
-		t.insert(string(bs))
+		word, category := string(bs), ""
+		if i := strings.IndexByte(word, '\t'); i >= 0 {
+			word, category = word[:i], strings.TrimSpace(word[i+1:])
+		}
+		t.insert(word, category)
 	}
 
 	//log.Release("word inserted:%d", t.count)
+	return nil
 }
 
 func (t *Trie) Insert(word string) {
-	t.insert(word)
+	t.insert(word, "")
 }
 
-func (t *Trie) insert(txt string) {
+func (t *Trie) InsertCategory(word, category string) {
+	t.insert(word, category)
+}
+
+func (t *Trie) insert(txt, category string) {
 	if len(txt) < 1 {
 		return
 	}
@@ -75,6 +100,7 @@ func (t *Trie) insert(txt string) {
 
 	if !node.end {
 		node.end = true
+		node.category = category
 		t.count++
 	} else {
 		log.Release("duplicate txt:%s", txt)
@@ -151,3 +177,36 @@ func (t *Trie) Replace(txt string) string {
 		return string(chars)
 	}
 }
+
+// 命中的词, 扫描方式与Replace相同, Replace替换的正是这些范围
+func (t *Trie) Match(txt string) []Match {
+	var matches []Match
+	key := []rune(txt)
+	slen := len(key)
+	for i := 0; i < slen; i++ {
+		node, exists := t.root.children[key[i]]
+		if !exists {
+			continue
+		}
+		word := []rune{key[i]}
+		for j := i + 1; j < slen; j++ {
+			next, exists := node.children[key[j]]
+			if !exists {
+				continue
+			}
+			node = next
+			word = append(word, key[j])
+			if node.end {
+				matches = append(matches, Match{
+					Start:    i,
+					End:      j + 1,
+					Word:     string(word),
+					Category: node.category,
+				})
+				i = j
+				break
+			}
+		}
+	}
+	return matches
+}
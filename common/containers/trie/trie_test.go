@@ -0,0 +1,51 @@
+package trie
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func mask(txt string, matches []Match) string {
+	chars := []rune(txt)
+	for _, m := range matches {
+		for i := m.Start; i < m.End; i++ {
+			chars[i] = '*'
+		}
+	}
+	return string(chars)
+}
+
+func TestMatch(t *testing.T) {
+	tr := New()
+	tr.InsertCategory("shit", "profanity")
+	tr.Insert("坏蛋")
+	tr.Insert("ab")
+
+	ms := tr.Match("oh shit, 你这个坏蛋")
+	if len(ms) != 2 {
+		t.Fatalf("matches %v", ms)
+	}
+	if ms[0] != (Match{Start: 3, End: 7, Word: "shit", Category: "profanity"}) || ms[1] != (Match{Start: 12, End: 14, Word: "坏蛋"}) {
+		t.Fatalf("matches %v", ms)
+	}
+	// 词中夹杂的字符一并计入范围
+	if ms = tr.Match("s.h.i.t"); len(ms) != 1 || ms[0].Word != "shit" || ms[0].End != 7 {
+		t.Fatalf("matches %v", ms)
+	}
+	if ms = tr.Match("hello"); len(ms) != 0 {
+		t.Fatalf("matches %v", ms)
+	}
+
+	// 与Replace替换的范围一致
+	alphabet := []rune("abshit坏蛋 .")
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 1000; n++ {
+		txt := make([]rune, r.Intn(20))
+		for i := range txt {
+			txt[i] = alphabet[r.Intn(len(alphabet))]
+		}
+		if got, want := mask(string(txt), tr.Match(string(txt))), tr.Replace(string(txt)); got != want {
+			t.Fatalf("%q: mask %q, replace %q", string(txt), got, want)
+		}
+	}
+}
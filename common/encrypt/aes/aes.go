@@ -14,10 +14,12 @@ var (
 	keyPool    = []byte("fWk2sdXsMDd133fQ6faje38013X2K44iisf42f33d0d4dEfdFf440RE58foB28Zrerok5jl2kdzG9w43eDZqw7dfnT5364cdQ45dff4ga0dVn3fUddsSEah4Nd62zdIfWP2S4Wdh4f83Rd3uT5L9Upj32nPWgL6AO7df9dq8F0IwOe1")
 )
 
+// Deprecated: 静态密钥且以密钥作IV, 会话加密请使用Session
 type Key struct {
 	K []byte
 }
 
+// Deprecated: 密钥取自固定的keyPool, 不可用于会话加密
 func MakeKey16() []byte {
 	lenKeyPool := len(keyPool)
 	if lenKeyPool < 16 {
@@ -28,6 +30,7 @@ func MakeKey16() []byte {
 	return keyPool[r : r+16]
 }
 
+// Deprecated: 使用Session.Seal
 func Encrypt(origData, key []byte) ([]byte, error) {
 	if key == nil {
 		key = DefaultKey
@@ -45,6 +48,7 @@ func Encrypt(origData, key []byte) ([]byte, error) {
 	return crypted, nil
 }
 
+// Deprecated: 使用Session.Open
 func Decrypt(crypted, key []byte) ([]byte, error) {
 	if key == nil {
 		key = DefaultKey
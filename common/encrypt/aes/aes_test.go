@@ -12,3 +12,27 @@ func TestMakeKey16(t *testing.T) {
 	t.Log(len(DefaultKey))
 	t.Log(DefaultKey)
 }
+
+func TestSession_Replay(t *testing.T) {
+	k1, k2 := make([]byte, 32), make([]byte, 32)
+	k2[0] = 1
+	client, _ := NewSession(k1, k2)
+	server, _ := NewSession(k2, k1)
+
+	aad := []byte{0, 0, 0, 4, 0}
+	f1, _ := client.Seal(aad, []byte("hello"))
+	f2, _ := client.Seal(aad, []byte("world"))
+
+	if plain, err := server.Open(aad, f1); err != nil || string(plain) != "hello" {
+		t.Fatalf("open f1 failed:%v", err)
+	}
+	if _, err := server.Open(aad, f1); err == nil {
+		t.Fatal("replayed frame accepted")
+	}
+	if _, err := server.Open([]byte{0, 0, 0, 5, 0}, f2); err == nil {
+		t.Fatal("tampered head accepted")
+	}
+	if plain, err := server.Open(aad, f2); err != nil || string(plain) != "world" {
+		t.Fatalf("open f2 failed:%v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"math"
+	"sync"
+)
+
+const SeqSize = 8
+
+// 握手后建立的会话加密(AES-GCM)
+// 每帧携带8字节递增序号, 作为nonce的一部分并用于防重放
+// 发送方需持锁完成加密与写入, 保证序号与写入顺序一致
+type Session struct {
+	sync.Mutex
+	sealer  cipher.AEAD
+	opener  cipher.AEAD
+	sendSeq uint64
+	recvSeq uint64
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func NewSession(sendKey, recvKey []byte) (*Session, error) {
+	sealer, err := newGCM(sendKey)
+	if err != nil {
+		return nil, err
+	}
+	opener, err := newGCM(recvKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{sealer: sealer, opener: opener}, nil
+}
+
+func (s *Session) nonce(seq uint64) []byte {
+	n := make([]byte, s.sealer.NonceSize())
+	binary.BigEndian.PutUint64(n[len(n)-SeqSize:], seq)
+	return n
+}
+
+// | seq(8) | ciphertext+tag |
+func (s *Session) Seal(aad, plain []byte) ([]byte, error) {
+	if s.sendSeq == math.MaxUint64 {
+		return nil, errors.New("session sequence exhausted")
+	}
+	s.sendSeq++
+
+	out := make([]byte, SeqSize, SeqSize+len(plain)+s.sealer.Overhead())
+	binary.BigEndian.PutUint64(out, s.sendSeq)
+	return s.sealer.Seal(out, s.nonce(s.sendSeq), plain, aad), nil
+}
+
+// 收回最近一次Seal使用的序号, 只能在该帧未发出(如写队列已满)时调用, 否则对端会拒绝之后的帧
+// 被丢弃的密文从未发出, 以同一nonce重新加密不会泄露明文
+func (s *Session) Unseal() {
+	if s.sendSeq > 0 {
+		s.sendSeq--
+	}
+}
+
+// 只接受紧随其后的序号, 重放或乱序的帧直接拒绝
+func (s *Session) Open(aad, data []byte) ([]byte, error) {
+	if len(data) < SeqSize+s.opener.Overhead() {
+		return nil, errors.New("encrypted frame too short")
+	}
+
+	seq := binary.BigEndian.Uint64(data)
+	if seq != s.recvSeq+1 {
+		return nil, errors.New("replayed or out-of-order frame")
+	}
+
+	plain, err := s.opener.Open(nil, s.nonce(seq), data[SeqSize:], aad)
+	if err != nil {
+		return nil, err
+	}
+	s.recvSeq = seq
+	return plain, nil
+}
@@ -0,0 +1,214 @@
+package kex
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"os"
+)
+
+const (
+	NonceSize = 16
+	KeySize   = 32 // AES-256
+
+	transcriptLabel = "cctest-kex-v1"
+)
+
+// 双方各自方向的会话密钥
+type SessionKeys struct {
+	ClientToServer []byte
+	ServerToClient []byte
+}
+
+// 一次握手使用的临时密钥
+type Ephemeral struct {
+	priv  *ecdh.PrivateKey
+	Nonce []byte
+}
+
+func NewEphemeral() (*Ephemeral, error) {
+	priv, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, NonceSize)
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return &Ephemeral{priv: priv, Nonce: nonce}, nil
+}
+
+func (e *Ephemeral) PublicKey() []byte {
+	return e.priv.PublicKey().Bytes()
+}
+
+// 服务端响应
+type ServerHello struct {
+	PublicKey []byte
+	Nonce     []byte
+	Signature []byte
+}
+
+// 服务端: 用身份密钥签名握手记录并派生会话密钥
+func ServerHandshake(identity ed25519.PrivateKey, clientPub, clientNonce []byte) (*ServerHello, *SessionKeys, error) {
+	if len(clientNonce) != NonceSize {
+		return nil, nil, errors.New("invalid client nonce")
+	}
+
+	e, err := NewEphemeral()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shared, err := e.sharedSecret(clientPub)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t := transcript(clientPub, clientNonce, e.PublicKey(), e.Nonce)
+	hello := &ServerHello{
+		PublicKey: e.PublicKey(),
+		Nonce:     e.Nonce,
+		Signature: ed25519.Sign(identity, t),
+	}
+
+	return hello, deriveKeys(shared, t), nil
+}
+
+// 客户端: 校验服务端签名后派生会话密钥
+func (e *Ephemeral) ClientFinish(serverIdentity ed25519.PublicKey, hello *ServerHello) (*SessionKeys, error) {
+	if hello == nil || len(hello.Nonce) != NonceSize {
+		return nil, errors.New("invalid server hello")
+	}
+
+	t := transcript(e.PublicKey(), e.Nonce, hello.PublicKey, hello.Nonce)
+	if !ed25519.Verify(serverIdentity, t, hello.Signature) {
+		return nil, errors.New("server signature mismatch")
+	}
+
+	shared, err := e.sharedSecret(hello.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return deriveKeys(shared, t), nil
+}
+
+func (e *Ephemeral) sharedSecret(peerPub []byte) ([]byte, error) {
+	pub, err := ecdh.X25519().NewPublicKey(peerPub)
+	if err != nil {
+		return nil, err
+	}
+	return e.priv.ECDH(pub)
+}
+
+func transcript(clientPub, clientNonce, serverPub, serverNonce []byte) []byte {
+	t := make([]byte, 0, len(transcriptLabel)+len(clientPub)+len(serverPub)+2*NonceSize)
+	t = append(t, transcriptLabel...)
+	t = append(t, clientPub...)
+	t = append(t, clientNonce...)
+	t = append(t, serverPub...)
+	return append(t, serverNonce...)
+}
+
+func deriveKeys(shared, transcript []byte) *SessionKeys {
+	th := sha256.Sum256(transcript)
+	prk := hkdfExtract(th[:], shared)
+	return &SessionKeys{
+		ClientToServer: hkdfExpand(prk, []byte("c2s"), KeySize),
+		ServerToClient: hkdfExpand(prk, []byte("s2c"), KeySize),
+	}
+}
+
+// RFC 5869
+func hkdfExtract(salt, ikm []byte) []byte {
+	h := hmac.New(sha256.New, salt)
+	h.Write(ikm)
+	return h.Sum(nil)
+}
+
+func hkdfExpand(prk, info []byte, l int) []byte {
+	var (
+		out  []byte
+		prev []byte
+	)
+	for i := byte(1); len(out) < l; i++ {
+		h := hmac.New(sha256.New, prk)
+		h.Write(prev)
+		h.Write(info)
+		h.Write([]byte{i})
+		prev = h.Sum(nil)
+		out = append(out, prev...)
+	}
+	return out[:l]
+}
+
+// 加载服务端身份私钥, 文件不存在时生成并同时写出公钥(path.pub)供客户端固定
+func LoadOrCreateIdentity(path string) (ed25519.PrivateKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("invalid identity file " + path)
+		}
+		key, e := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if e != nil {
+			return nil, e
+		}
+		priv, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.New("identity is not an ed25519 key")
+		}
+		return priv, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	if err = ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		return nil, err
+	}
+	pubDer, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	err = ioutil.WriteFile(path+".pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDer}), 0644)
+	return priv, err
+}
+
+// 客户端加载固定的服务端公钥
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid public key file " + path)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not ed25519")
+	}
+	return pub, nil
+}
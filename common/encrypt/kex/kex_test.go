@@ -0,0 +1,41 @@
+package kex
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+)
+
+func TestHandshake(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+
+	client, err := NewEphemeral()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hello, serverKeys, err := ServerHandshake(priv, client.PublicKey(), client.Nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientKeys, err := client.ClientFinish(pub, hello)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(clientKeys.ClientToServer, serverKeys.ClientToServer) ||
+		!bytes.Equal(clientKeys.ServerToClient, serverKeys.ServerToClient) {
+		t.Fatal("session keys mismatch")
+	}
+	if bytes.Equal(clientKeys.ClientToServer, clientKeys.ServerToClient) {
+		t.Fatal("directional keys must differ")
+	}
+
+	// 中间人替换服务端临时公钥
+	mitm, _ := NewEphemeral()
+	hello.PublicKey = mitm.PublicKey()
+	if _, err = client.ClientFinish(pub, hello); err == nil {
+		t.Fatal("forged server hello accepted")
+	}
+}
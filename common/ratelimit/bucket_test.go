@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucket(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := NewBucket(2, 3)
+
+	for i := 0; i < 3; i++ {
+		if !b.Allow(now) {
+			t.Fatalf("burst %d rejected", i)
+		}
+	}
+	if b.Allow(now) {
+		t.Fatal("empty bucket allowed")
+	}
+	if w := b.Wait(now); w != 500*time.Millisecond {
+		t.Fatalf("wait %s", w)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if !b.Allow(now) || b.Allow(now) {
+		t.Fatal("should refill one token in 500ms")
+	}
+
+	// 时间回退时不补充
+	if b.Allow(now.Add(-time.Second)) {
+		t.Fatal("allowed on clock going back")
+	}
+
+	// 补充不超过burst
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if !b.Allow(now) {
+			t.Fatalf("refilled burst %d rejected", i)
+		}
+	}
+	if b.Allow(now) {
+		t.Fatal("refilled beyond burst")
+	}
+}
@@ -2,12 +2,12 @@ package task
 
 import (
 	"cloudcadetest/framework/log"
+	"cloudcadetest/framework/metrics"
 	"cloudcadetest/framework/module"
 	"hash/fnv"
 	"runtime"
 	"strconv"
 	"strings"
-	"sync/atomic"
 )
 
 type taskFuncPair struct {
@@ -31,11 +31,11 @@ type Pool struct {
 	name   string
 }
 
-// 仅用于在压测时计算吞吐量
-// 生产环境中不要读写这两个值
 var (
-	taskProduced = uint32(0)
-	taskConsumed = uint32(0)
+	taskProduced = metrics.NewCounter("cc_task_produced_total", "Tasks added to task pools.")
+	taskConsumed = metrics.NewCounter("cc_task_consumed_total", "Tasks taken out of task pools.")
+	taskDropped  = metrics.NewCounter("cc_task_dropped_total", "Tasks dropped because the pool channel was full.")
+	taskQueueLen = metrics.NewGaugeFuncVec("cc_task_pool_queue_len", "Tasks waiting in a named task pool.", "pool")
 )
 
 func NewTaskPool(sm *module.ServerMod, taskNum, chanNum int) *Pool {
@@ -136,13 +136,9 @@ func (p *Pool) AddTask(f func(), cb func(), poolDecide string) {
 		f:  f,
 		cb: cb,
 	}:
-		atomic.AddUint32(&taskProduced, 1)
-		produced := atomic.LoadUint32(&taskProduced)
-		if produced%1000 == 0 {
-			log.Warn("tp task produced:%d", produced)
-		}
-
+		taskProduced.Inc()
 	default:
+		taskDropped.Inc()
 		log.Error("task is full")
 	}
 }
@@ -168,7 +164,6 @@ func (t *UpdateTask) executeFun(pair *taskFuncPair) {
 func ProcessTask(task *UpdateTask) {
 	if task == nil {
 		panic("task is nil")
-		return
 	}
 
 	for {
@@ -177,12 +172,7 @@ func ProcessTask(task *UpdateTask) {
 			break
 		}
 
-		atomic.AddUint32(&taskConsumed, 1)
-		consumed := atomic.LoadUint32(&taskConsumed)
-		if consumed%1000 == 0 {
-			log.Warn("tp task consumed:%d", consumed)
-		}
-
+		taskConsumed.Inc()
 		task.executeFun(pair)
 	}
 }
@@ -212,6 +202,9 @@ func (p *Pool) Len() int {
 func (p *Pool) SetName(name string) *Pool {
 	if p != nil {
 		p.name = name
+		taskQueueLen.Set(func() float64 {
+			return float64(p.Len())
+		}, name)
 	}
 	return p
 }
@@ -228,7 +221,9 @@ func (p *Pool) AddFixedTask(f, cb func(), idx int) int {
 
 	select {
 	case p.Tasks[idx].t <- &taskFuncPair{f, cb}:
+		taskProduced.Inc()
 	default:
+		taskDropped.Inc()
 		log.Error("task pool[%s]'s sub chan[idx=%d] full.", p.name, idx)
 		idx = -1
 	}
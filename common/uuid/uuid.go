@@ -1,11 +1,15 @@
 package uuid
 
-import "time"
+import (
+	"sync"
+	"time"
+)
 
 // 通过时间戳+(该秒内自增id)
 type UUID struct {
-	high int64 // 24~64位(可表示未来无数年 目前1970到现在的时间戳大概在 16亿左右 40位来表示足够了)
-	low  int32 // 自增id 0~23 位 2^24 1s内的自增id 足够了
+	sync.Mutex       // 连接协程与skeleton都会取号
+	high       int64 // 24~64位(可表示未来无数年 目前1970到现在的时间戳大概在 16亿左右 40位来表示足够了)
+	low        int32 // 自增id 0~23 位 2^24 1s内的自增id 足够了
 }
 
 func (uuid *UUID) reset() {
@@ -18,6 +22,9 @@ func (uuid *UUID) reset() {
 }
 
 func (uuid *UUID) Get() int64 {
+	uuid.Lock()
+	defer uuid.Unlock()
+
 	uuid.reset()
 	uuid.low++
 	return (uuid.high << 24) + int64(uuid.low)
@@ -3,10 +3,72 @@ package filter
 import (
 	"cloudcadetest/common/containers/trie"
 	"cloudcadetest/common/task"
+	"cloudcadetest/framework/metrics"
 	"cloudcadetest/framework/module"
+	"fmt"
 	"strconv"
+	"sync/atomic"
+	"time"
 )
 
+var (
+	filterDuration = metrics.NewHistogramVec("cc_filter_duration_seconds", "Word filter latency; total includes waiting in the task pool.", nil, "stage")
+	filterCheck    = filterDuration.With("check")
+	filterTotal    = filterDuration.With("total")
+)
+
+// 命中敏感词时的处理方式, 由调用方按场景选择
+type Policy int
+
+const (
+	Mask   Policy = iota // 替换为*后照常使用
+	Reject               // 拒绝并告知发送者
+	Flag                 // 静默丢弃并记录, 供管理员审核
+)
+
+var policyNames = [...]string{Mask: "mask", Reject: "reject", Flag: "flag"}
+
+func (p Policy) String() string {
+	if p < 0 || int(p) >= len(policyNames) {
+		return "policy(" + strconv.Itoa(int(p)) + ")"
+	}
+	return policyNames[p]
+}
+
+func ParsePolicy(s string) (Policy, error) {
+	for p, name := range policyNames {
+		if s == name {
+			return Policy(p), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown filter policy %s", s)
+}
+
+type Result struct {
+	Policy  Policy
+	Text    string // Mask时为替换后的文本, 其余为原文
+	Matches []trie.Match
+}
+
+func (r *Result) Dirty() bool {
+	return len(r.Matches) > 0
+}
+
+// 命中的词, 去重后按出现顺序
+func (r *Result) Words() []string {
+	var (
+		words = make([]string, 0, len(r.Matches))
+		seen  = map[string]bool{}
+	)
+	for _, m := range r.Matches {
+		if !seen[m.Word] {
+			seen[m.Word] = true
+			words = append(words, m.Word)
+		}
+	}
+	return words
+}
+
 type IFilterSkeleton interface {
 	GetServerModule() *module.ServerMod
 	GetID() int64
@@ -17,47 +79,86 @@ type Filter struct {
 	srvMod   *module.ServerMod
 	tasks    *task.Pool
 	id       int64
-	trieNode *trie.Trie
+	path     string
+	trieNode atomic.Value // *trie.Trie, 重新加载时整体替换
 }
 
 func New(ifs IFilterSkeleton) *Filter {
 	f := &Filter{
-		id:       ifs.GetID(),
-		trieNode: trie.New(),
+		id:   ifs.GetID(),
+		path: ifs.GetWordListFilePath(),
 	}
 	if ifs.GetServerModule() != nil {
-		f.tasks = task.NewTaskPool(ifs.GetServerModule(), 0, 0)
+		f.tasks = task.NewTaskPool(ifs.GetServerModule(), 0, 0).SetName("filter")
 	}
-	f.trieNode.InsertFile(ifs.GetWordListFilePath())
+	t := trie.New()
+	t.InsertFile(f.path)
+	f.trieNode.Store(t)
 	return f
 }
 
-func (f *Filter) check(content string) string {
-	//log.Release("content:%s, passed:%t", content, !f.trieNode.HasDirty(content))
-	return f.trieNode.Replace(content)
+// 重新读取词表, 失败时保留原词表
+func (f *Filter) Reload() error {
+	t := trie.New()
+	if e := t.LoadFile(f.path); e != nil {
+		return e
+	}
+	f.trieNode.Store(t)
+	return nil
+}
+
+func (f *Filter) check(content string, policy Policy) *Result {
+	defer filterCheck.Since(time.Now())
+	res := &Result{
+		Policy:  policy,
+		Text:    content,
+		Matches: f.trieNode.Load().(*trie.Trie).Match(content),
+	}
+	if policy == Mask && res.Dirty() {
+		chars := []rune(content)
+		for _, m := range res.Matches {
+			for i := m.Start; i < m.End; i++ {
+				chars[i] = '*'
+			}
+		}
+		res.Text = string(chars)
+	}
+	return res
 }
 
+// 替换敏感词, 等同于Mask策略
 func (f *Filter) Check(content string, onFinish func(newStr string)) {
+	f.CheckWith(content, Mask, func(res *Result) {
+		if onFinish != nil {
+			onFinish(res.Text)
+		}
+	})
+}
+
+// 返回命中详情, 按policy生成Text; 有任务池时onFinish在主协程中调用
+func (f *Filter) CheckWith(content string, policy Policy, onFinish func(res *Result)) {
 	var (
-		safeFinish = func(s string) {
+		start      = time.Now()
+		safeFinish = func(res *Result) {
+			filterTotal.Since(start)
 			if onFinish != nil {
-				onFinish(s)
+				onFinish(res)
 			}
 		}
 	)
 
 	if f.tasks != nil {
-		newStr := content
+		res := &Result{Policy: policy, Text: content}
 		f.tasks.AddTask(
 			func() {
-				newStr = f.check(content)
+				res = f.check(content, policy)
 			},
 			func() {
-				safeFinish(newStr)
+				safeFinish(res)
 			},
 			strconv.FormatInt(f.id, 10),
 		)
 	} else {
-		safeFinish(f.check(content))
+		safeFinish(f.check(content, policy))
 	}
 }
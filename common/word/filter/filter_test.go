@@ -0,0 +1,52 @@
+package filter
+
+import (
+	"cloudcadetest/framework/module"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type fileFS string
+
+func (fs fileFS) GetServerModule() *module.ServerMod { return nil }
+func (fs fileFS) GetID() int64                       { return 0 }
+func (fs fileFS) GetWordListFilePath() string        { return string(fs) }
+
+func TestCheckWith(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "filter")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "list.txt")
+	ioutil.WriteFile(path, []byte("shit\tprofanity\n坏蛋\n"), 0644)
+	f := New(fileFS(path))
+
+	const content = "shit, 坏蛋 shit"
+	var res *Result
+	f.CheckWith(content, Mask, func(r *Result) { res = r })
+	if res.Text != "****, ** ****" || len(res.Matches) != 3 || res.Matches[0].Category != "profanity" {
+		t.Fatalf("mask %+v", res)
+	}
+	if !reflect.DeepEqual(res.Words(), []string{"shit", "坏蛋"}) {
+		t.Fatalf("words %v", res.Words())
+	}
+
+	for _, p := range []Policy{Reject, Flag} {
+		f.CheckWith(content, p, func(r *Result) { res = r })
+		if res.Text != content || !res.Dirty() || res.Policy != p {
+			t.Fatalf("%s %+v", p, res)
+		}
+	}
+	f.CheckWith("hello", Reject, func(r *Result) { res = r })
+	if res.Dirty() {
+		t.Fatalf("clean %+v", res)
+	}
+
+	if p, e := ParsePolicy("flag"); e != nil || p != Flag {
+		t.Fatal(p, e)
+	}
+	if _, e := ParsePolicy("drop"); e == nil {
+		t.Fatal("unknown policy")
+	}
+}
@@ -24,22 +24,31 @@ type Frequency struct {
 	sortTasks     chan *sortTask
 	produced      int32
 	consumed      int32
+	now           func() time.Time
 }
 
 func New() *Frequency {
+	return NewWithClock(time.Now)
+}
+
+// 分桶与查询的时间戳都取自now, 测试时可传入固定的时钟
+func NewWithClock(now func() time.Time) *Frequency {
 	f := &Frequency{
 		frqBySec:  list.New(),
 		wordChan:  make(chan string, 3500),
 		sortTasks: make(chan *sortTask, 10000),
+		now:       now,
 	}
-	f.frqBySec.PushBack(newSecWords())
+	f.frqBySec.PushBack(f.newSecWords())
 	go f.update()
 
 	return f
 }
 
-func newSecWords() *wordsbysec.Words {
-	return wordsbysec.New(1000)
+func (f *Frequency) newSecWords() *wordsbysec.Words {
+	ws := wordsbysec.New(1000)
+	ws.TS = f.now().Unix()
+	return ws
 }
 
 func (f *Frequency) update() {
@@ -50,21 +59,19 @@ func (f *Frequency) update() {
 			f.onTimePassed()
 
 		case word := <-f.wordChan:
-			// use tail as the node for the current second
 			//fmt.Printf("%d consume word:%s\n", time.Now().UnixNano(), word)
 			//atomic.AddInt32(&f.consumed, 1)
-			tail := f.frqBySec.Back()
-			if tail != nil {
-				ws, ok := tail.Value.(*wordsbysec.Words)
-				if !ok {
-					goto END
-				}
-				ws.Add(word)
-			} else {
+			if !f.addWord(word) {
 				goto END
 			}
 
 		case task := <-f.sortTasks:
+			// 查询前加入的词先计入, 否则select可能先取到查询
+			for n := len(f.wordChan); n > 0; n-- {
+				if !f.addWord(<-f.wordChan) {
+					goto END
+				}
+			}
 			f.processTask(task)
 		}
 	}
@@ -73,12 +80,26 @@ END:
 	log.Error("frequency handler quits")
 }
 
+// use tail as the node for the current second
+func (f *Frequency) addWord(word string) bool {
+	tail := f.frqBySec.Back()
+	if tail == nil {
+		return false
+	}
+	ws, ok := tail.Value.(*wordsbysec.Words)
+	if !ok {
+		return false
+	}
+	ws.Add(word)
+	return true
+}
+
 func (f *Frequency) onTimePassed() {
 	if f.frqBySec.Len() == 60 {
 		h := f.frqBySec.Front()
 		f.frqBySec.Remove(h)
 	}
-	f.frqBySec.PushBack(newSecWords())
+	f.frqBySec.PushBack(f.newSecWords())
 }
 
 func (f *Frequency) Add(sentence string) {
@@ -109,7 +130,7 @@ func (f *Frequency) GetFrequencyByTime(lastNSeconds int, cb func(meta *wordmeta.
 	select {
 	case f.sortTasks <- &sortTask{
 		lastNSeconds: lastNSeconds,
-		ts:           time.Now().Unix(),
+		ts:           f.now().Unix(),
 		cb:           cb,
 	}:
 	default:
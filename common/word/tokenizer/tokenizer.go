@@ -0,0 +1,88 @@
+package tokenizer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 分词, 用于全文检索
+// 连续的字母数字(转为小写)为一个词, 占一个位置; 中日韩文字没有分隔, 每个字占一个位置
+// 建索引时每个字及其与下一个字组成的二元词都作为词, 查询时只用二元词, 单字时用单字
+// 同一段文字两种切分的位置一致, 查询词的位置差可用于短语匹配
+
+const maxWordLen = 64 // 超长的词截断, 避免索引被刷大
+
+type Token struct {
+	Term string
+	Pos  int
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+func isWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// 建索引用的切分
+func Tokenize(text string) []Token {
+	return split(text, true)
+}
+
+// 查询用的切分
+func Query(text string) []Token {
+	return split(text, false)
+}
+
+func split(text string, index bool) []Token {
+	var (
+		tokens []Token
+		pos    int
+		word   strings.Builder
+		n      int // word中的字数
+		cjk    []rune
+	)
+
+	flushWord := func() {
+		if word.Len() == 0 {
+			return
+		}
+		tokens = append(tokens, Token{Term: word.String(), Pos: pos})
+		pos++
+		word.Reset()
+		n = 0
+	}
+	flushCJK := func() {
+		for i, r := range cjk {
+			if index || len(cjk) == 1 {
+				tokens = append(tokens, Token{Term: string(r), Pos: pos + i})
+			}
+			if i+1 < len(cjk) {
+				tokens = append(tokens, Token{Term: string(cjk[i : i+2]), Pos: pos + i})
+			}
+		}
+		pos += len(cjk)
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case isWord(r):
+			flushCJK()
+			if n < maxWordLen {
+				word.WriteRune(unicode.ToLower(r))
+				n++
+			}
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return tokens
+}
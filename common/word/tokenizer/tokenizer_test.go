@@ -0,0 +1,28 @@
+package tokenizer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		text  string
+		index []Token
+		query []Token
+	}{
+		{"Hello, World 42!", []Token{{"hello", 0}, {"world", 1}, {"42", 2}}, []Token{{"hello", 0}, {"world", 1}, {"42", 2}}},
+		{"你好吗", []Token{{"你", 0}, {"你好", 0}, {"好", 1}, {"好吗", 1}, {"吗", 2}}, []Token{{"你好", 0}, {"好吗", 1}}},
+		{"学Go语言", []Token{{"学", 0}, {"go", 1}, {"语", 2}, {"语言", 2}, {"言", 3}}, []Token{{"学", 0}, {"go", 1}, {"语言", 2}}},
+		{"好 ok", []Token{{"好", 0}, {"ok", 1}}, []Token{{"好", 0}, {"ok", 1}}},
+		{"...", nil, nil},
+	}
+	for _, c := range cases {
+		if got := Tokenize(c.text); !reflect.DeepEqual(got, c.index) {
+			t.Errorf("Tokenize(%q) = %v, want %v", c.text, got, c.index)
+		}
+		if got := Query(c.text); !reflect.DeepEqual(got, c.query) {
+			t.Errorf("Query(%q) = %v, want %v", c.text, got, c.query)
+		}
+	}
+}
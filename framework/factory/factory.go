@@ -5,17 +5,25 @@ import (
 	"cloudcadetest/framework/log"
 	"cloudcadetest/framework/module"
 	"cloudcadetest/modconf"
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 )
 
+const (
+	defaultDrainTimeout = 30 * time.Second
+	drainKillDelay      = 5 * time.Second // 旧进程超过排空时间仍未退出时再等待的时间
+)
+
 type CServer struct {
-	cfg       *modconf.ServerConf
-	Logger    *log.Logger
-	stopped   bool
-	ServerMod *module.ServerMod
+	cfg        *modconf.ServerConf
+	Logger     *log.Logger
+	stopped    bool
+	restarting bool
+	ServerMod  *module.ServerMod
 }
 
 func New(sc *modconf.ServerConf) *CServer {
@@ -70,27 +78,81 @@ func (s *CServer) Run(mods []module.IModule) {
 	}
 
 	for i := 0; i < len(mods); i++ {
-		module.Run(mods[i])
+		module.Init(mods[i])
 	}
+	module.Start()
+
+	// 平滑重启时先接受新连接再通知旧进程排空, 模块等HandedOver后再加载旧进程保存的状态
+	go func() {
+		takeOver(s.drainTimeout() + drainKillDelay)
+		close(handedOver)
+	}()
 
 	log.Release("cc-server starting up")
 
 	// 捕获信号
 	c := make(chan os.Signal, 1)
-	signal.Notify(c, []os.Signal{os.Kill}...)
+	sigs := []os.Signal{syscall.SIGTERM, os.Interrupt}
+	if platform.RestartSignal != nil {
+		sigs = append(sigs, platform.RestartSignal)
+	}
+	signal.Notify(c, sigs...)
 
 	for !s.stopped {
 		select {
 		case sig := <-c:
 			switch sig {
-			case os.Kill:
-				log.Release("cc-server killed")
+			case syscall.SIGTERM, os.Interrupt:
+				log.Release("cc-server received %v", sig)
 				goto END
+			case platform.RestartSignal:
+				// 新进程开始接受连接后会发送SIGTERM, 本进程排空并保存状态后新进程再加载
+				if s.restarting {
+					log.Warn("cc-server restart already in progress")
+					break
+				}
+				if err = s.restart(); err != nil {
+					log.Error("cc-server restart failed:%s", err.Error())
+					break
+				}
+				s.restarting = true
 			}
 		}
 	}
 
 END:
-	module.Destroy()
+	s.shutdown(c)
+	releaseHandoff()
 	log.Release("cc-server closing down")
 }
+
+// 先排空连接再销毁模块, 整个流程不超过DrainTimeout; 期间再次收到信号则立即退出
+func (s *CServer) shutdown(sig chan os.Signal) {
+	timeout := s.drainTimeout()
+
+	// 为模块销毁预留1/5的时间
+	drainCtx, cancel := context.WithTimeout(context.Background(), timeout*4/5)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		module.Shutdown(drainCtx)
+		module.Destroy()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Error("cc-server shutdown timed out after %v", timeout)
+	case <-sig:
+		log.Warn("cc-server forced to exit")
+	}
+}
+
+func (s *CServer) drainTimeout() time.Duration {
+	if timeout := time.Duration(s.cfg.DrainTimeout) * time.Second; timeout > 0 {
+		return timeout
+	}
+	return defaultDrainTimeout
+}
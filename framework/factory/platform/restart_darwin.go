@@ -0,0 +1,14 @@
+package platform
+
+import (
+	"os"
+	"syscall"
+)
+
+// 平滑重启信号
+var RestartSignal os.Signal = syscall.SIGUSR2
+
+// 通知旧进程退出
+func Terminate(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
@@ -0,0 +1,13 @@
+package platform
+
+import (
+	"errors"
+	"os"
+)
+
+// windows不支持平滑重启
+var RestartSignal os.Signal
+
+func Terminate(pid int) error {
+	return errors.New("graceful restart not supported on windows")
+}
@@ -0,0 +1,140 @@
+package factory
+
+import (
+	"cloudcadetest/framework/factory/platform"
+	"cloudcadetest/framework/log"
+	"cloudcadetest/framework/network"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 新进程启动后通过这两个变量找到旧进程, 通知其退出并等待其排空
+const (
+	envParentPID  = "CC_PARENT_PID"
+	envParentPipe = "CC_PARENT_PIPE" // 管道读端的fd, 旧进程排空并保存状态后写端关闭
+)
+
+// 交给新进程的管道写端, 保存状态后关闭; 进程退出时也会由系统关闭
+var handoff *os.File
+
+var handedOver = make(chan struct{})
+
+// 旧进程排空并保存状态后关闭, 不是由平滑重启拉起时启动后即关闭; 模块需在此之后加载持久状态
+func HandedOver() <-chan struct{} {
+	return handedOver
+}
+
+// 平滑重启: 只将监听socket交给新进程, 新进程开始接受连接后通知本进程排空, 待本进程保存状态后再加载
+func (s *CServer) restart() error {
+	cmd, err := spawn(os.Args[1:])
+	if err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// 以args启动新进程, 传入当前所有监听socket与排空完成的通知管道
+func spawn(args []string) (*exec.Cmd, error) {
+	addrs, files, err := network.ListenerFiles()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, r)
+
+	exe, err := os.Executable()
+	if err != nil {
+		w.Close()
+		return nil, err
+	}
+
+	env := make([]string, 0, len(os.Environ())+3)
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, network.EnvInheritListeners+"=") || strings.HasPrefix(kv, envParentPID+"=") ||
+			strings.HasPrefix(kv, envParentPipe+"=") {
+			continue
+		}
+		env = append(env, kv)
+	}
+	env = append(env, network.EnvInheritListeners+"="+strings.Join(addrs, ","),
+		envParentPID+"="+strconv.Itoa(os.Getpid()),
+		envParentPipe+"="+strconv.Itoa(3+len(addrs)))
+
+	cmd := exec.Command(exe, args...)
+	cmd.Env = env
+	cmd.ExtraFiles = files
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err = cmd.Start(); err != nil {
+		w.Close()
+		return nil, err
+	}
+
+	releaseHandoff()
+	handoff = w
+	log.Release("cc-server started new process pid:%d with listeners %v", cmd.Process.Pid, addrs)
+	return cmd, nil
+}
+
+// 排空并保存状态后调用, 新进程在此之后开始加载状态
+func releaseHandoff() {
+	if handoff == nil {
+		return
+	}
+	if e := handoff.Close(); e != nil {
+		log.Error("close handoff pipe failed:%s", e.Error())
+	}
+	handoff = nil
+}
+
+// 由重启拉起的进程开始接受连接后通知旧进程退出, 并等待其排空完成; 超时后强制结束旧进程
+func takeOver(timeout time.Duration) {
+	pid, err := strconv.Atoi(os.Getenv(envParentPID))
+	if err != nil || pid <= 0 {
+		return
+	}
+	fd, err := strconv.Atoi(os.Getenv(envParentPipe))
+	if err != nil || fd <= 0 {
+		log.Error("no handoff pipe from parent %d", pid)
+		return
+	}
+	pipe := os.NewFile(uintptr(fd), "handoff")
+	defer pipe.Close()
+
+	if err = platform.Terminate(pid); err != nil {
+		log.Error("notify parent %d failed:%s", pid, err.Error())
+		return
+	}
+	log.Release("cc-server waiting for parent %d to drain", pid)
+
+	done := make(chan struct{})
+	go func() {
+		io.Copy(ioutil.Discard, pipe)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Error("parent %d not drained after %v, kill it", pid, timeout)
+		if p, e := os.FindProcess(pid); e == nil {
+			p.Kill()
+		}
+		<-done
+	}
+	log.Release("cc-server took over from parent %d", pid)
+}
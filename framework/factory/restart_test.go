@@ -0,0 +1,150 @@
+package factory
+
+import (
+	"bufio"
+	"cloudcadetest/framework/network"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+)
+
+const (
+	envTestState = "CC_TEST_HANDOFF_STATE"
+	envTestAddr  = "CC_TEST_HANDOFF_ADDR"
+)
+
+// 由TestHandoff拉起, 扮演重启后的新进程: 先从继承的监听接受连接, 等旧进程排空后再加载状态
+func TestHandoffChild(t *testing.T) {
+	state := os.Getenv(envTestState)
+	if state == "" {
+		t.Skip("run by TestHandoff")
+	}
+
+	ln, err := network.Listen(os.Getenv(envTestAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handed := make(chan struct{})
+	go func() {
+		takeOver(10 * time.Second)
+		close(handed)
+	}()
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("accepted\n"))
+
+	<-handed
+	data, err := ioutil.ReadFile(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Write(append(data, '\n'))
+}
+
+// 旧进程排空期间仍在处理消息, 期间的新连接由新进程立即接受, 新进程应加载排空后保存的状态
+func TestHandoff(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("graceful restart not supported on windows")
+	}
+	dir, _ := ioutil.TempDir("", "handoff")
+	defer os.RemoveAll(dir)
+	state := filepath.Join(dir, "state")
+
+	probe, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := probe.Addr().String()
+	probe.Close()
+	ln, err := network.Listen(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 重启前已连接的客户端, 旧进程记录收到的消息数
+	client, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	served, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		received int
+		lines    = bufio.NewScanner(served)
+	)
+	send := func(n int) {
+		for i := 0; i < n; i++ {
+			client.Write([]byte("msg\n"))
+			if !lines.Scan() {
+				t.Fatal("connection to old process lost")
+			}
+			received++
+		}
+	}
+	send(3)
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGTERM)
+	defer signal.Stop(sig)
+	os.Setenv(envTestState, state)
+	os.Setenv(envTestAddr, addr)
+	defer os.Unsetenv(envTestState)
+	defer os.Unsetenv(envTestAddr)
+
+	cmd, err := spawn([]string{"-test.run=^TestHandoffChild$"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-sig:
+	case <-time.After(10 * time.Second):
+		t.Fatal("new process did not ask to take over")
+	}
+
+	// 排空: 旧进程停止接受新连接, 已有连接上的消息照常处理; 新连接立即由新进程接受
+	network.CloseListener(addr, ln)
+	late, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal("new connection during drain refused:", err)
+	}
+	defer late.Close()
+	lateLines := bufio.NewReader(late)
+	late.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if got, err := lateLines.ReadString('\n'); err != nil || got != "accepted\n" {
+		t.Fatalf("new process did not serve during drain: %q %v", got, err)
+	}
+	send(5)
+	late.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	if _, err = lateLines.ReadByte(); err == nil {
+		t.Fatal("new process loaded state before old process drained")
+	}
+
+	ioutil.WriteFile(state, []byte(strconv.Itoa(received)), 0644)
+	served.Close()
+	releaseHandoff()
+
+	late.SetReadDeadline(time.Now().Add(10 * time.Second))
+	got, err := lateLines.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "8\n" {
+		t.Fatalf("new process loaded %q, want 8", got)
+	}
+	if err = cmd.Wait(); err != nil {
+		t.Fatal("new process failed:", err)
+	}
+}
@@ -1,6 +1,7 @@
 package log
 
 import (
+	"cloudcadetest/framework/metrics"
 	"errors"
 	"fmt"
 	"io"
@@ -48,7 +49,7 @@ type Logger struct {
 }
 
 func redirectError(fmat string, args ...interface{}) {
-	msg := fmt.Sprintf(fmat, args)
+	msg := fmt.Sprintf(fmat, args...)
 	if _, e := fmt.Fprintf(os.Stderr, msg); e != nil {
 		fmt.Printf("error occured when redirecting err[%s]: %s\n", msg, e.Error())
 	}
@@ -178,6 +179,7 @@ func (logger *Logger) doPrintf(level int, printLevel string, format string, a ..
 	select {
 	case logger.ch <- format:
 	default:
+		logDropped.Inc()
 		redirectError("fatal error: logger.chNum is full\n")
 	}
 	if level == FatalLevel {
@@ -211,6 +213,14 @@ func (logger *Logger) Fatal(format string, a ...interface{}) {
 
 var gLogger, _ = New("release", "", "", 100000, 50)
 
+var logDropped = metrics.NewCounter("cc_log_dropped_total", "Log lines dropped because the log channel was full.")
+
+func init() {
+	metrics.NewGaugeFunc("cc_log_backlog", "Log lines waiting to be written.", func() float64 {
+		return float64(GetChanNum())
+	})
+}
+
 func Export(logger *Logger) {
 	if logger != nil {
 		if gLogger != nil {
@@ -224,6 +234,10 @@ func GetChanNum() int {
 	return gLogger.GetChanNum()
 }
 
+func IsValidLevel(strLevel string) bool {
+	return getLogLevelInteger(strLevel) != IllegalLevel
+}
+
 func SetLogLevel(strLevel string) {
 	if gLogger != nil {
 		gLogger.SetLoglevel(strLevel)
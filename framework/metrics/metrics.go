@@ -0,0 +1,433 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 以Prometheus文本格式导出的指标, 同名指标按标签值区分多条序列
+
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+)
+
+// 默认的耗时分布(秒)
+var DefBuckets = []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1, 5}
+
+type series interface {
+	write(w *bufio.Writer, name, labels string)
+}
+
+type family struct {
+	name    string
+	help    string
+	typ     string
+	labels  []string
+	buckets []float64
+
+	mutex  sync.RWMutex
+	series map[string]series
+	values map[string][]string
+}
+
+// 按标签值取序列, 不存在时创建
+func (f *family) get(values []string, create func() series) series {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+
+	f.mutex.RLock()
+	s, ok := f.series[key]
+	f.mutex.RUnlock()
+	if ok {
+		return s
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if s, ok = f.series[key]; !ok {
+		s = create()
+		f.series[key] = s
+		f.values[key] = append([]string(nil), values...)
+	}
+	return s
+}
+
+func (f *family) set(values []string, s series) {
+	key := strings.Join(values, "\xff")
+	f.mutex.Lock()
+	f.series[key] = s
+	f.values[key] = append([]string(nil), values...)
+	f.mutex.Unlock()
+}
+
+func (f *family) delete(values []string) {
+	key := strings.Join(values, "\xff")
+	f.mutex.Lock()
+	delete(f.series, key)
+	delete(f.values, key)
+	f.mutex.Unlock()
+}
+
+func (f *family) write(w *bufio.Writer) {
+	f.mutex.RLock()
+	keys := make([]string, 0, len(f.series))
+	for k := range f.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	ss := make([]series, len(keys))
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		ss[i] = f.series[k]
+		labels[i] = formatLabels(f.labels, f.values[k])
+	}
+	f.mutex.RUnlock()
+
+	if len(ss) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.typ)
+	for i, s := range ss {
+		s.write(w, f.name, labels[i])
+	}
+}
+
+type Registry struct {
+	mutex    sync.RWMutex
+	families map[string]*family
+}
+
+func NewRegistry() *Registry {
+	return &Registry{families: map[string]*family{}}
+}
+
+var Default = NewRegistry()
+
+// 同名指标重复注册时返回已有的, 类型或标签不一致则panic
+func (r *Registry) register(name, help, typ string, buckets []float64, labels []string) *family {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if f, ok := r.families[name]; ok {
+		if f.typ != typ || strings.Join(f.labels, ",") != strings.Join(labels, ",") {
+			panic("metrics: conflicting registration of " + name)
+		}
+		return f
+	}
+
+	f := &family{
+		name:    name,
+		help:    help,
+		typ:     typ,
+		labels:  append([]string(nil), labels...),
+		buckets: buckets,
+		series:  map[string]series{},
+		values:  map[string][]string{},
+	}
+	r.families[name] = f
+	return f
+}
+
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mutex.RLock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fs := make([]*family, len(names))
+	for i, name := range names {
+		fs[i] = r.families[name]
+	}
+	r.mutex.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	for _, f := range fs {
+		f.write(bw)
+	}
+	return bw.Flush()
+}
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.WriteText(w)
+	})
+}
+
+//--========================== counter ==========================--
+
+type Counter struct {
+	v uint64
+}
+
+func (c *Counter) Inc() {
+	atomic.AddUint64(&c.v, 1)
+}
+
+func (c *Counter) Add(n uint64) {
+	atomic.AddUint64(&c.v, n)
+}
+
+func (c *Counter) Value() uint64 {
+	return atomic.LoadUint64(&c.v)
+}
+
+func (c *Counter) write(w *bufio.Writer, name, labels string) {
+	fmt.Fprintf(w, "%s%s %d\n", name, labels, c.Value())
+}
+
+type CounterVec struct {
+	f *family
+}
+
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{f: r.register(name, help, typeCounter, nil, labels)}
+}
+
+func (v *CounterVec) With(values ...string) *Counter {
+	return v.f.get(values, func() series { return &Counter{} }).(*Counter)
+}
+
+//--========================== gauge ==========================--
+
+type Gauge struct {
+	bits uint64
+}
+
+func (g *Gauge) Set(v float64) {
+	atomic.StoreUint64(&g.bits, math.Float64bits(v))
+}
+
+func (g *Gauge) Add(delta float64) {
+	for {
+		old := atomic.LoadUint64(&g.bits)
+		n := math.Float64bits(math.Float64frombits(old) + delta)
+		if atomic.CompareAndSwapUint64(&g.bits, old, n) {
+			return
+		}
+	}
+}
+
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+func (g *Gauge) Dec() {
+	g.Add(-1)
+}
+
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.bits))
+}
+
+func (g *Gauge) write(w *bufio.Writer, name, labels string) {
+	fmt.Fprintf(w, "%s%s %s\n", name, labels, formatFloat(g.Value()))
+}
+
+type GaugeVec struct {
+	f *family
+}
+
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{f: r.register(name, help, typeGauge, nil, labels)}
+}
+
+func (v *GaugeVec) With(values ...string) *Gauge {
+	return v.f.get(values, func() series { return &Gauge{} }).(*Gauge)
+}
+
+// 导出时才取值的gauge, fn需保证并发安全
+type gaugeFunc func() float64
+
+func (g gaugeFunc) write(w *bufio.Writer, name, labels string) {
+	fmt.Fprintf(w, "%s%s %s\n", name, labels, formatFloat(g()))
+}
+
+type GaugeFuncVec struct {
+	f *family
+}
+
+func (r *Registry) NewGaugeFuncVec(name, help string, labels ...string) *GaugeFuncVec {
+	return &GaugeFuncVec{f: r.register(name, help, typeGauge, nil, labels)}
+}
+
+// 设置或替换一组标签对应的取值函数
+func (v *GaugeFuncVec) Set(fn func() float64, values ...string) {
+	if len(values) != len(v.f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", v.f.name, len(v.f.labels), len(values)))
+	}
+	v.f.set(values, gaugeFunc(fn))
+}
+
+func (v *GaugeFuncVec) Delete(values ...string) {
+	v.f.delete(values)
+}
+
+//--========================== histogram ==========================--
+
+type Histogram struct {
+	upper   []float64
+	counts  []uint64 // 各桶单独计数, 导出时累加
+	count   uint64
+	sumBits uint64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		upper:  buckets,
+		counts: make([]uint64, len(buckets)),
+	}
+}
+
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.upper, v)
+	if i < len(h.counts) {
+		atomic.AddUint64(&h.counts[i], 1)
+	}
+	atomic.AddUint64(&h.count, 1)
+	for {
+		old := atomic.LoadUint64(&h.sumBits)
+		n := math.Float64bits(math.Float64frombits(old) + v)
+		if atomic.CompareAndSwapUint64(&h.sumBits, old, n) {
+			return
+		}
+	}
+}
+
+// 记录从start开始的耗时(秒)
+func (h *Histogram) Since(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+func (h *Histogram) write(w *bufio.Writer, name, labels string) {
+	var cumulative uint64
+	for i, upper := range h.upper {
+		cumulative += atomic.LoadUint64(&h.counts[i])
+		fmt.Fprintf(w, "%s_bucket%s %d\n", name, appendLabel(labels, "le", formatFloat(upper)), cumulative)
+	}
+	count := atomic.LoadUint64(&h.count)
+	fmt.Fprintf(w, "%s_bucket%s %d\n", name, appendLabel(labels, "le", "+Inf"), count)
+	fmt.Fprintf(w, "%s_sum%s %s\n", name, labels, formatFloat(math.Float64frombits(atomic.LoadUint64(&h.sumBits))))
+	fmt.Fprintf(w, "%s_count%s %d\n", name, labels, count)
+}
+
+type HistogramVec struct {
+	f *family
+}
+
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	if !sort.Float64sAreSorted(buckets) {
+		panic("metrics: buckets of " + name + " not sorted")
+	}
+	return &HistogramVec{f: r.register(name, help, typeHistogram, buckets, labels)}
+}
+
+func (v *HistogramVec) With(values ...string) *Histogram {
+	return v.f.get(values, func() series { return newHistogram(v.f.buckets) }).(*Histogram)
+}
+
+//--========================== default registry ==========================--
+
+func NewCounter(name, help string) *Counter {
+	return Default.NewCounterVec(name, help).With()
+}
+
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return Default.NewCounterVec(name, help, labels...)
+}
+
+func NewGauge(name, help string) *Gauge {
+	return Default.NewGaugeVec(name, help).With()
+}
+
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return Default.NewGaugeVec(name, help, labels...)
+}
+
+func NewGaugeFunc(name, help string, fn func() float64) {
+	Default.NewGaugeFuncVec(name, help).Set(fn)
+}
+
+func NewGaugeFuncVec(name, help string, labels ...string) *GaugeFuncVec {
+	return Default.NewGaugeFuncVec(name, help, labels...)
+}
+
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	return Default.NewHistogramVec(name, help, buckets).With()
+}
+
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return Default.NewHistogramVec(name, help, buckets, labels...)
+}
+
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+//--========================== format ==========================--
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, n := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(n)
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(values[i]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func appendLabel(labels, name, value string) string {
+	l := name + `="` + labelEscaper.Replace(value) + `"`
+	if labels == "" {
+		return "{" + l + "}"
+	}
+	return labels[:len(labels)-1] + "," + l + "}"
+}
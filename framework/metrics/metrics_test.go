@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("test_requests_total", "Requests.", "code")
+	c.With("200").Add(3)
+	c.With("500").Inc()
+	r.NewGaugeVec("test_depth", "Queue depth.").With().Set(2.5)
+	r.NewGaugeFuncVec("test_len", "Length.", "pool").Set(func() float64 { return 7 }, `a"b`)
+	h := r.NewHistogramVec("test_seconds", "Latency.", []float64{0.1, 1})
+	h.With().Observe(0.05)
+	h.With().Observe(0.5)
+	h.With().Observe(3)
+
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# HELP test_depth Queue depth.
+# TYPE test_depth gauge
+test_depth 2.5
+# HELP test_len Length.
+# TYPE test_len gauge
+test_len{pool="a\"b"} 7
+# HELP test_requests_total Requests.
+# TYPE test_requests_total counter
+test_requests_total{code="200"} 3
+test_requests_total{code="500"} 1
+# HELP test_seconds Latency.
+# TYPE test_seconds histogram
+test_seconds_bucket{le="0.1"} 1
+test_seconds_bucket{le="1"} 2
+test_seconds_bucket{le="+Inf"} 3
+test_seconds_sum 3.55
+test_seconds_count 3
+`
+	if b.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	r := NewRegistry()
+	a := r.NewCounterVec("test_total", "Test.", "x")
+	b := r.NewCounterVec("test_total", "Test.", "x")
+	a.With("1").Inc()
+	if b.With("1").Value() != 1 {
+		t.Fatal("same name should share the family")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("conflicting registration should panic")
+		}
+	}()
+	r.NewGaugeVec("test_total", "Test.", "x")
+}
+
+func TestGaugeFuncDelete(t *testing.T) {
+	r := NewRegistry()
+	g := r.NewGaugeFuncVec("test_len", "Length.", "pool")
+	g.Set(func() float64 { return 1 }, "a")
+	g.Delete("a")
+
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != 0 {
+		t.Fatalf("empty family should not be written, got %q", b.String())
+	}
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("test_total", "Test.").With().Inc()
+
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("content type %q", ct)
+	}
+	if !strings.Contains(w.Body.String(), "test_total 1\n") {
+		t.Fatalf("body %q", w.Body.String())
+	}
+}
@@ -2,6 +2,7 @@ package module
 
 import (
 	"cloudcadetest/framework/log"
+	"context"
 	"runtime"
 	"sync"
 )
@@ -12,10 +13,16 @@ type IModule interface {
 	Run(closeSig chan bool)
 }
 
+// 可选, 进程退出前按注册顺序调用, 需在ctx结束前返回
+type IShutdown interface {
+	OnShutdown(ctx context.Context)
+}
+
 type module struct {
 	mi       IModule
 	closeSig chan bool
 	wg       sync.WaitGroup
+	started  bool
 }
 
 var mods []*module
@@ -37,12 +44,57 @@ func Destroy() {
 	}
 }
 
+func Shutdown(ctx context.Context) {
+	for _, m := range mods {
+		s, ok := m.mi.(IShutdown)
+		if !ok {
+			continue
+		}
+
+		shutdown(ctx, s)
+		if ctx.Err() != nil {
+			log.Warn("module shutdown deadline exceeded")
+			return
+		}
+	}
+}
+
+func shutdown(ctx context.Context, s IShutdown) {
+	defer func() {
+		if r := recover(); r != nil {
+			buf := make([]byte, 4096)
+			l := runtime.Stack(buf, false)
+			log.Error("%v: %s", r, buf[:l])
+		}
+	}()
+
+	s.OnShutdown(ctx)
+}
+
 func Run(mod IModule) {
 	m := register(mod)
 	mod.OnInit()
+	m.start()
+}
+
+// 只注册并初始化, 由Start统一运行; 保证所有模块初始化完成后才有模块开始接受连接
+func Init(mod IModule) {
+	register(mod).mi.OnInit()
+}
+
+// 按注册顺序运行尚未运行的模块
+func Start() {
+	for _, m := range mods {
+		if !m.started {
+			m.start()
+		}
+	}
+}
 
+func (m *module) start() {
+	m.started = true
+	m.wg.Add(1)
 	go func() {
-		m.wg.Add(1)
 		m.mi.Run(m.closeSig)
 		m.wg.Done()
 	}()
@@ -96,12 +96,16 @@ func (sm *ServerMod) runFunc(info interface{}, f func()) {
 	}
 
 	timeout := 1000
-	ctx, _ := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
 	ch := make(chan int, 3)
 
 	go func() {
 		select {
 		case <-ctx.Done():
+			if ctx.Err() != context.DeadlineExceeded {
+				return
+			}
 			if info == nil {
 				info = GetFunctionName(f)
 			}
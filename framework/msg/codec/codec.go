@@ -0,0 +1,268 @@
+package codec
+
+import (
+	"bytes"
+	"cloudcadetest/common/compress"
+	"cloudcadetest/pb"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"sync/atomic"
+)
+
+// 客户端与服务端共用的消息编解码
+// 帧格式见frame.go, 包体依次经过压缩、加密
+
+// 会话加密钩子, aes.Session实现了该接口
+type Cipher interface {
+	Seal(aad, plain []byte) ([]byte, error)
+	Open(aad, data []byte) ([]byte, error)
+}
+
+// 按消息号创建对应的包体
+func NewBody(id pb.CSMsgID) proto.Message {
+	switch {
+	case id < pb.CSMsgID_RSP_BEGIN:
+		return &pb.CSReqBody{}
+	case id < pb.CSMsgID_NTF_BEGIN:
+		return &pb.CSRspBody{}
+	default:
+		return &pb.CSNtfBody{}
+	}
+}
+
+// 消息头中影响解析的字段作为附加认证数据, 防止被篡改
+// 旧客户端的压缩标志为1, 与zlib的编号一致
+func HeadAAD(id pb.CSMsgID, codec compress.ID) []byte {
+	aad := make([]byte, 5)
+	binary.BigEndian.PutUint32(aad, uint32(id))
+	aad[4] = byte(codec)
+	return aad
+}
+
+// 消息头中的压缩算法, 旧客户端只有压缩标志, 对应zlib
+func headCodec(h *pb.CSHead) compress.ID {
+	if h.Codec != pb.COMPRESS_CODEC_COMPRESS_NONE {
+		return compress.ID(h.Codec)
+	}
+	if h.IsCompressed {
+		return compress.Zlib
+	}
+	return compress.None
+}
+
+// 解出的一帧, Body已解密解压
+type Frame struct {
+	Version FrameVersion
+	MsgID   pb.CSMsgID
+	Codec   compress.ID
+	Seq     int64 // 下行序号, 0表示不参与确认
+	Body    []byte
+}
+
+// 流式解码器, 每个连接一个, 只能在读协程中使用
+type Decoder struct {
+	MaxMsgLen    int
+	AcceptLegacy bool
+	// 返回该消息使用的会话加密, nil表示明文; 返回错误则断开连接
+	Cipher func(id pb.CSMsgID) (Cipher, error)
+
+	buf     bytes.Buffer
+	version FrameVersion
+}
+
+func NewDecoder(maxMsgLen int, acceptLegacy bool) *Decoder {
+	return &Decoder{
+		MaxMsgLen:    maxMsgLen,
+		AcceptLegacy: acceptLegacy,
+	}
+}
+
+// 追加从网络读到的数据
+func (d *Decoder) Write(data []byte) (int, error) {
+	return d.buf.Write(data)
+}
+
+// 对端最近一帧使用的格式, 可在其他协程读取
+func (d *Decoder) Version() FrameVersion {
+	return d.version.Load()
+}
+
+// 取出下一帧, 数据不足时返回nil
+func (d *Decoder) Next() (*Frame, error) {
+	buf := d.buf.Bytes()
+	ver, prefix, hlen, err := ParseFrameHeader(buf, d.AcceptLegacy)
+	if err == ErrFrameIncomplete {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if hlen > d.MaxMsgLen {
+		return nil, fmt.Errorf("message too long %d", hlen)
+	} else if hlen <= 0 {
+		return nil, fmt.Errorf("message too short %d", hlen)
+	}
+
+	if len(buf) < prefix+hlen {
+		return nil, nil
+	}
+
+	h := &pb.CSHead{}
+	if err = proto.Unmarshal(buf[prefix:prefix+hlen], h); err != nil {
+		return nil, errors.New("Unmarshal head error:" + err.Error() + fmt.Sprintf(" headLen:%v", hlen))
+	}
+
+	if int(h.BodyLen) > d.MaxMsgLen {
+		return nil, fmt.Errorf("message too long %v", h.BodyLen)
+	} else if h.BodyLen < 0 {
+		return nil, fmt.Errorf("message too short %v", h.BodyLen)
+	}
+
+	pktLen := prefix + hlen + int(h.BodyLen)
+	if len(buf) < pktLen {
+		return nil, nil
+	}
+
+	data := d.buf.Next(pktLen)
+	var body []byte
+	if h.BodyLen > 0 {
+		body = make([]byte, h.BodyLen)
+		copy(body, data[prefix+hlen:])
+	}
+
+	if d.version.Load() != ver {
+		d.version.Store(ver)
+	}
+
+	codec := headCodec(h)
+	if d.Cipher != nil {
+		c, e := d.Cipher(h.MsgID)
+		if e != nil {
+			return nil, e
+		}
+		// 空包体同样经过认证
+		if c != nil {
+			if body, err = c.Open(HeadAAD(h.MsgID, codec), body); err != nil {
+				return nil, errors.New("Decrypt fail:" + err.Error())
+			}
+		}
+	}
+
+	if codec != compress.None && len(body) > 0 {
+		c := compress.Get(codec)
+		if c == nil {
+			return nil, fmt.Errorf("unknown compressor %d", codec)
+		}
+		if body, err = c.Decompress(body); err != nil {
+			return nil, errors.New("Decompress fail:" + err.Error())
+		}
+	}
+
+	return &Frame{Version: ver, MsgID: h.MsgID, Codec: codec, Seq: h.Seq, Body: body}, nil
+}
+
+// 编码器, 可在多个连接间共享; 压缩算法由各连接协商, 阈值按消息类型配置
+type Encoder struct {
+	minCompressSize int32        //默认压缩阈值, 0表示不压缩
+	msgCompressSize atomic.Value //map[pb.CSMsgID]int32, 按消息类型覆盖默认阈值
+}
+
+func NewEncoder(minCompressSize int32) *Encoder {
+	e := &Encoder{}
+	e.SetMinCompressSize(minCompressSize)
+	e.msgCompressSize.Store(map[pb.CSMsgID]int32{})
+	return e
+}
+
+func (e *Encoder) SetMinCompressSize(minCompressSize int32) {
+	atomic.StoreInt32(&e.minCompressSize, minCompressSize)
+}
+
+func (e *Encoder) GetMinCompressSize() int32 {
+	return atomic.LoadInt32(&e.minCompressSize)
+}
+
+// 单独设置某类消息的压缩阈值, 0表示该消息不压缩
+func (e *Encoder) SetMsgCompressSize(id pb.CSMsgID, minCompressSize int32) {
+	old := e.msgCompressSize.Load().(map[pb.CSMsgID]int32)
+	m := make(map[pb.CSMsgID]int32, len(old)+1)
+	for k, v := range old {
+		m[k] = v
+	}
+	m[id] = minCompressSize
+	e.msgCompressSize.Store(m)
+}
+
+func (e *Encoder) CompressSize(id pb.CSMsgID) int32 {
+	if size, ok := e.msgCompressSize.Load().(map[pb.CSMsgID]int32)[id]; ok {
+		return size
+	}
+	return e.GetMinCompressSize()
+}
+
+// 超过阈值且压缩成功、结果更小时才使用压缩结果, 返回实际使用的算法
+func (e *Encoder) Compress(id pb.CSMsgID, codec compress.ID, body []byte) ([]byte, compress.ID) {
+	minSize := e.CompressSize(id)
+	c := compress.Get(codec)
+	if c == nil || minSize == 0 || int32(len(body)) < minSize {
+		return body, compress.None
+	}
+
+	zipData, err := c.Compress(body)
+	if err != nil || len(zipData) >= len(body) {
+		return body, compress.None
+	}
+	return zipData, codec
+}
+
+// 对已压缩的包体加密并组帧, c为nil时不加密, seq为0表示不带下行序号
+// 使用加密时调用方需持有会话锁直到写入完成, 保证序号与写入顺序一致
+func (e *Encoder) Encode(ver FrameVersion, id pb.CSMsgID, seq int64, body []byte, codec compress.ID, c Cipher) ([]byte, error) {
+	var err error
+	if c != nil {
+		if body, err = c.Seal(HeadAAD(id, codec), body); err != nil {
+			return nil, errors.New("Encrypt fail:" + err.Error())
+		}
+	}
+
+	// 旧客户端只认识压缩标志, 仅在使用zlib时不写算法编号
+	h := &pb.CSHead{
+		MsgID:        id,
+		BodyLen:      int32(len(body)),
+		IsCompressed: codec != compress.None,
+		Seq:          seq,
+	}
+	if codec != compress.None && codec != compress.Zlib {
+		h.Codec = pb.COMPRESS_CODEC(codec)
+	}
+
+	headData, err := proto.Marshal(h)
+	if err != nil {
+		return nil, errors.New("Marshal head error:" + err.Error())
+	}
+
+	data := make([]byte, 0, maxFramePrefix+len(headData)+len(body))
+	if data, err = AppendFrameHeader(data, ver, len(headData)); err != nil {
+		return nil, err
+	}
+	data = append(data, headData...)
+	return append(data, body...), nil
+}
+
+// 序列化、压缩、加密并组帧
+func (e *Encoder) EncodeMsg(ver FrameVersion, id pb.CSMsgID, msg proto.Message, codec compress.ID, c Cipher) ([]byte, error) {
+	var (
+		body []byte
+		err  error
+	)
+	if msg != nil {
+		if body, err = proto.Marshal(msg); err != nil {
+			return nil, fmt.Errorf("Marshal id:%s error:%v", id, err)
+		}
+	}
+
+	body, codec = e.Compress(id, codec, body)
+	return e.Encode(ver, id, 0, body, codec, c)
+}
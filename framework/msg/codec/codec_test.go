@@ -0,0 +1,179 @@
+package codec
+
+import (
+	"cloudcadetest/common/compress"
+	"cloudcadetest/common/encrypt/aes"
+	"cloudcadetest/pb"
+	"crypto/rand"
+	"github.com/golang/protobuf/proto"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func sampleBody(id pb.CSMsgID, content string) proto.Message {
+	switch body := NewBody(id).(type) {
+	case *pb.CSReqBody:
+		body.Seq = int64(id)
+		body.RoomChat = &pb.CSReqRoomChat{Content: content}
+		return body
+	case *pb.CSRspBody:
+		body.Seq = int64(id)
+		body.ErrMsg = content
+		return body
+	case *pb.CSNtfBody:
+		body.RoomChat = &pb.CSNtfRoomChat{Username: id.String(), Content: content}
+		return body
+	}
+	return nil
+}
+
+func sessionPair(t *testing.T) (*aes.Session, *aes.Session) {
+	c2s, s2c := make([]byte, 32), make([]byte, 32)
+	rand.Read(c2s)
+	rand.Read(s2c)
+	client, err := aes.NewSession(c2s, s2c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := aes.NewSession(s2c, c2s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server
+}
+
+func allMsgIDs() []pb.CSMsgID {
+	ids := make([]pb.CSMsgID, 0, len(pb.CSMsgID_name))
+	for id := range pb.CSMsgID_name {
+		ids = append(ids, pb.CSMsgID(id))
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// 一端编码的所有消息, 另一端按字节流逐段喂入后都能原样解出
+func TestCodec_RoundTripAllMsgIDs(t *testing.T) {
+	type testCase struct {
+		name    string
+		ver     FrameVersion
+		encrypt bool
+		codec   compress.ID
+		content string
+	}
+	cases := []testCase{
+		{"legacy", FrameLegacy, false, compress.Zlib, "hello"},
+		{"v1", FrameV1, false, compress.Zlib, "hello"},
+		{"v1_encrypted", FrameV1, true, compress.Zlib, "hello"},
+	}
+	for _, id := range compress.IDs() {
+		name := compress.Name(id)
+		big := strings.Repeat("chat ", 400)
+		cases = append(cases,
+			testCase{"legacy_" + name, FrameLegacy, false, id, big},
+			testCase{"v1_" + name, FrameV1, false, id, big},
+			testCase{"v1_encrypted_" + name, FrameV1, true, id, big})
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clientSess, serverSess := sessionPair(t)
+			enc := NewEncoder(1024)
+			clientDec := NewDecoder(10000, true)
+			serverDec := NewDecoder(10000, true)
+			if tc.encrypt {
+				clientDec.Cipher = func(pb.CSMsgID) (Cipher, error) { return clientSess, nil }
+				serverDec.Cipher = func(pb.CSMsgID) (Cipher, error) { return serverSess, nil }
+			}
+
+			for _, id := range allMsgIDs() {
+				// 请求由客户端发往服务端, 其余方向相反
+				sender, dec := Cipher(serverSess), clientDec
+				if id < pb.CSMsgID_RSP_BEGIN {
+					sender, dec = clientSess, serverDec
+				}
+				if !tc.encrypt {
+					sender = nil
+				}
+
+				msg := sampleBody(id, tc.content)
+				data, err := enc.EncodeMsg(tc.ver, id, msg, tc.codec, sender)
+				if err != nil {
+					t.Fatalf("encode %s:%v", id, err)
+				}
+
+				var f *Frame
+				for i := 0; i < len(data); i += 7 {
+					end := i + 7
+					if end > len(data) {
+						end = len(data)
+					}
+					dec.Write(data[i:end])
+					if f, err = dec.Next(); err != nil {
+						t.Fatalf("decode %s:%v", id, err)
+					}
+					if f != nil && end != len(data) {
+						t.Fatalf("%s decoded before frame complete", id)
+					}
+				}
+				if f == nil {
+					t.Fatalf("%s not decoded", id)
+				}
+				if f.MsgID != id || f.Version != tc.ver || dec.Version() != tc.ver {
+					t.Fatalf("%s got id:%s ver:%d", id, f.MsgID, f.Version)
+				}
+				if want := tc.codec; len(tc.content) < 1024 {
+					if f.Codec != compress.None {
+						t.Fatalf("%s should not be compressed", id)
+					}
+				} else if f.Codec != want {
+					t.Fatalf("%s codec:%s want:%s", id, compress.Name(f.Codec), compress.Name(want))
+				}
+
+				got := NewBody(f.MsgID)
+				if err = proto.Unmarshal(f.Body, got); err != nil {
+					t.Fatalf("unmarshal %s:%v", id, err)
+				}
+				if !proto.Equal(msg, got) {
+					t.Fatalf("%s mismatch\nwant:%v\ngot:%v", id, msg, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDecoder_Tampered(t *testing.T) {
+	clientSess, serverSess := sessionPair(t)
+	enc := NewEncoder(0)
+	data, err := enc.EncodeMsg(FrameV1, pb.CSMsgID_REQ_ROOM_CHAT, sampleBody(pb.CSMsgID_REQ_ROOM_CHAT, "hi"), compress.None, clientSess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 1
+
+	dec := NewDecoder(10000, true)
+	dec.Cipher = func(pb.CSMsgID) (Cipher, error) { return serverSess, nil }
+	dec.Write(data)
+	if _, err = dec.Next(); err == nil {
+		t.Fatal("tampered frame should be rejected")
+	}
+}
+
+// 按消息类型设置的阈值优先于默认阈值
+func TestEncoder_MsgCompressSize(t *testing.T) {
+	enc := NewEncoder(1024)
+	enc.SetMsgCompressSize(pb.CSMsgID_NTF_HISTROY_MSG, 16)
+	enc.SetMsgCompressSize(pb.CSMsgID_NTF_ROOM_CHAT, 0)
+
+	body := []byte(strings.Repeat("chat ", 20))
+	if _, c := enc.Compress(pb.CSMsgID_NTF_HISTROY_MSG, compress.Zlib, body); c != compress.Zlib {
+		t.Fatal("history should be compressed")
+	}
+	if _, c := enc.Compress(pb.CSMsgID_RSP_LOGIN, compress.Zlib, body); c != compress.None {
+		t.Fatal("body below default threshold should not be compressed")
+	}
+	big := []byte(strings.Repeat("chat ", 400))
+	if _, c := enc.Compress(pb.CSMsgID_NTF_ROOM_CHAT, compress.Zlib, big); c != compress.None {
+		t.Fatal("room chat compression disabled")
+	}
+}
@@ -0,0 +1,86 @@
+package codec
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// 帧格式版本
+// 旧格式: | headLen(1) | head | body |, 头长度必须大于0
+// V1:    | magic(0) | version(1) | headLen(uvarint) | head | body |
+// 首字节为0即可与旧格式区分
+type FrameVersion uint32
+
+const (
+	FrameLegacy FrameVersion = 0
+	FrameV1     FrameVersion = 1
+
+	FrameMagic = 0x00
+
+	legacyHeadLenSize = 1
+	maxFramePrefix    = 2 + binary.MaxVarintLen32
+)
+
+var ErrFrameIncomplete = errors.New("frame header incomplete")
+
+// 连接上协商出的版本, 读协程写入, 写协程读取
+func (v *FrameVersion) Load() FrameVersion {
+	return FrameVersion(atomic.LoadUint32((*uint32)(v)))
+}
+
+func (v *FrameVersion) Store(ver FrameVersion) {
+	atomic.StoreUint32((*uint32)(v), uint32(ver))
+}
+
+// 解析帧前缀, 返回版本、前缀长度与头长度; 数据不足时返回ErrFrameIncomplete
+func ParseFrameHeader(buf []byte, acceptLegacy bool) (FrameVersion, int, int, error) {
+	if len(buf) < 1 {
+		return 0, 0, 0, ErrFrameIncomplete
+	}
+
+	if buf[0] != FrameMagic {
+		if !acceptLegacy {
+			return 0, 0, 0, errors.New("legacy frame not accepted")
+		}
+		return FrameLegacy, legacyHeadLenSize, int(buf[0]), nil
+	}
+
+	if len(buf) < 2 {
+		return 0, 0, 0, ErrFrameIncomplete
+	}
+	ver := FrameVersion(buf[1])
+	if ver != FrameV1 {
+		return 0, 0, 0, fmt.Errorf("unsupported frame version %d", ver)
+	}
+
+	hlen, n := binary.Uvarint(buf[2:])
+	if n == 0 {
+		if len(buf) >= maxFramePrefix {
+			return 0, 0, 0, errors.New("invalid head length")
+		}
+		return 0, 0, 0, ErrFrameIncomplete
+	}
+	if n < 0 || hlen > 1<<31-1 {
+		return 0, 0, 0, errors.New("head length overflow")
+	}
+
+	return ver, 2 + n, int(hlen), nil
+}
+
+// 按版本写入帧前缀, 旧格式头长度超过255时报错而不是截断
+func AppendFrameHeader(dst []byte, ver FrameVersion, headLen int) ([]byte, error) {
+	switch ver {
+	case FrameLegacy:
+		if headLen <= 0 || headLen > 0xFF {
+			return nil, fmt.Errorf("head length %d not representable in legacy frame", headLen)
+		}
+		return append(dst, byte(headLen)), nil
+	case FrameV1:
+		dst = append(dst, FrameMagic, byte(FrameV1))
+		var l [binary.MaxVarintLen32]byte
+		return append(dst, l[:binary.PutUvarint(l[:], uint64(headLen))]...), nil
+	}
+	return nil, fmt.Errorf("unsupported frame version %d", ver)
+}
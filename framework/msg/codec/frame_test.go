@@ -0,0 +1,46 @@
+package codec
+
+import (
+	"testing"
+)
+
+func TestFrameHeader_RoundTrip(t *testing.T) {
+	for _, ver := range []FrameVersion{FrameLegacy, FrameV1} {
+		for _, l := range []int{1, 127, 128, 255, 300, 1 << 20} {
+			buf, err := AppendFrameHeader(nil, ver, l)
+			if ver == FrameLegacy && l > 255 {
+				if err == nil {
+					t.Fatalf("legacy head length %d should be rejected", l)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			v, prefix, hlen, err := ParseFrameHeader(buf, true)
+			if err != nil || v != ver || prefix != len(buf) || hlen != l {
+				t.Fatalf("ver:%d len:%d got ver:%d prefix:%d hlen:%d err:%v", ver, l, v, prefix, hlen, err)
+			}
+
+			// 前缀不完整时需等待更多数据
+			for i := 0; i < len(buf); i++ {
+				if _, _, _, err = ParseFrameHeader(buf[:i], true); err != ErrFrameIncomplete {
+					t.Fatalf("ver:%d len:%d partial %d got err:%v", ver, l, i, err)
+				}
+			}
+		}
+	}
+}
+
+func TestFrameHeader_Reject(t *testing.T) {
+	if _, _, _, err := ParseFrameHeader([]byte{10}, false); err == nil {
+		t.Fatal("legacy frame should be rejected")
+	}
+	if _, _, _, err := ParseFrameHeader([]byte{FrameMagic, 9, 1}, true); err == nil {
+		t.Fatal("unknown version should be rejected")
+	}
+	if _, _, _, err := ParseFrameHeader([]byte{FrameMagic, byte(FrameV1), 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, true); err == nil {
+		t.Fatal("oversized varint should be rejected")
+	}
+}
@@ -0,0 +1,71 @@
+package codec
+
+// 接收端的下行序号窗口, 记录已连续收到的最大序号并过滤重发的重复消息
+// 每个连接一个, 第一条带序号的消息确定起点, 只能在读协程中使用
+type SeqWindow struct {
+	ack     int64
+	started bool
+	ahead   map[int64]struct{} // 前面有缺口时先收到的序号
+}
+
+// 已连续收到的最大序号, 作为确认发给对端
+func (w *SeqWindow) Ack() int64 {
+	return w.ack
+}
+
+// 有缺口时先收到的消息数
+func (w *SeqWindow) Ahead() int {
+	return len(w.ahead)
+}
+
+// 返回false表示已收到过, 应丢弃; seq为0的消息不参与确认
+func (w *SeqWindow) Accept(seq int64) bool {
+	if seq == 0 {
+		return true
+	}
+	if !w.started {
+		w.started = true
+		w.ack = seq - 1
+	}
+	if seq <= w.ack {
+		return false
+	}
+	if _, ok := w.ahead[seq]; ok {
+		return false
+	}
+
+	if seq != w.ack+1 {
+		if w.ahead == nil {
+			w.ahead = map[int64]struct{}{}
+		}
+		w.ahead[seq] = struct{}{}
+		return true
+	}
+	w.ack = seq
+	w.advance()
+	return true
+}
+
+// 对端通知next之前的消息不会再重发, 跳过缺口
+func (w *SeqWindow) Skip(next int64) {
+	w.started = true
+	if next-1 > w.ack {
+		w.ack = next - 1
+		for seq := range w.ahead {
+			if seq <= w.ack {
+				delete(w.ahead, seq)
+			}
+		}
+	}
+	w.advance()
+}
+
+func (w *SeqWindow) advance() {
+	for {
+		if _, ok := w.ahead[w.ack+1]; !ok {
+			return
+		}
+		delete(w.ahead, w.ack+1)
+		w.ack++
+	}
+}
@@ -0,0 +1,47 @@
+package codec
+
+import (
+	"testing"
+)
+
+// 乱序、重复与跳过缺口后的确认序号
+func TestSeqWindow(t *testing.T) {
+	var w SeqWindow
+	steps := []struct {
+		seq    int64
+		accept bool
+		ack    int64
+	}{
+		{0, true, 0}, // 不带序号
+		{5, true, 5}, // 第一条确定起点
+		{6, true, 6},
+		{8, true, 6},
+		{9, true, 6},
+		{8, false, 6},
+		{6, false, 6},
+		{7, true, 9},
+		{9, false, 9},
+		{10, true, 10},
+	}
+	for i, s := range steps {
+		if got := w.Accept(s.seq); got != s.accept || w.Ack() != s.ack {
+			t.Fatalf("step %d seq:%d got accept:%v ack:%d, want %v %d", i, s.seq, got, w.Ack(), s.accept, s.ack)
+		}
+	}
+
+	w.Accept(13)
+	w.Accept(15)
+	w.Skip(13)
+	if w.Ack() != 13 || w.Ahead() != 1 {
+		t.Fatalf("after skip ack:%d ahead:%d", w.Ack(), w.Ahead())
+	}
+	if !w.Accept(14) || w.Ack() != 15 || w.Ahead() != 0 {
+		t.Fatalf("gap not filled, ack:%d ahead:%d", w.Ack(), w.Ahead())
+	}
+
+	// 跳过的起点不能回退
+	w.Skip(3)
+	if w.Ack() != 15 {
+		t.Fatalf("skip backwards moved ack to %d", w.Ack())
+	}
+}
@@ -1,31 +1,41 @@
 package cs
 
 import (
-	"bytes"
-	"cloudcadetest/common/compress/zlib"
+	"cloudcadetest/common/compress"
 	"cloudcadetest/common/encrypt/aes"
 	"cloudcadetest/framework/agent"
 	"cloudcadetest/framework/log"
 	"cloudcadetest/framework/module"
+	"cloudcadetest/framework/msg/codec"
 	"cloudcadetest/framework/network"
 	"cloudcadetest/framework/network/protobuf"
 	"cloudcadetest/pb"
-	"encoding/binary"
 	"errors"
 	"fmt"
 	"sync/atomic"
 	"time"
 )
 
-const HeadLenSize = 1
+// 默认的压缩算法偏好, 字典对短聊天消息效果最好
+var DefaultCompressPreference = []compress.ID{compress.FlateDict, compress.Flate, compress.Zlib, compress.Gzip}
+
+// 一个连接上协商出的编码参数
+type Link interface {
+	GetConn() network.IConn
+	GetSession() *aes.Session
+	GetFrameVersion() codec.FrameVersion
+	GetCompressor() compress.ID
+	GetOutbox() *Outbox // 为nil时下行消息不带序号
+}
 
 type Processor struct {
 	*protobuf.Processor
-	littleEndian    bool
-	minMsgLen       int32
-	maxMsgLen       int32
-	minCompressSize int32 //压缩阈值
-	encrypt         bool  //是否加密
+	littleEndian bool
+	maxMsgLen    int32
+	encoder      *codec.Encoder
+	encrypt      bool         //是否加密
+	acceptLegacy int32        //是否接受旧格式帧, 迁移期间开启
+	preference   atomic.Value //[]compress.ID
 }
 
 func New(
@@ -36,29 +46,78 @@ func New(
 	p := &Processor{
 		Processor:    protobuf.NewProcessor(),
 		littleEndian: littleEndian,
-		minMsgLen:    0,
 		maxMsgLen:    maxMsgLen,
+		encoder:      codec.NewEncoder(minCompressSize),
 		encrypt:      encrypt,
 	}
 
-	p.SetMinCompressSize(minCompressSize)
+	p.SetAcceptLegacyFrame(true)
+	p.SetCompressPreference(DefaultCompressPreference)
 
-	time.Since(time.Now()).Nanoseconds()
 	p.Processor.SetRouter(srvMod.RPCServer)
 
 	return p
 }
 
 func (p *Processor) SetMinCompressSize(minCompressSize int32) {
-	atomic.StoreInt32(&p.minCompressSize, minCompressSize)
+	p.encoder.SetMinCompressSize(minCompressSize)
 }
 
 func (p *Processor) GetMinCompressSize() int32 {
-	return atomic.LoadInt32(&p.minCompressSize)
+	return p.encoder.GetMinCompressSize()
+}
+
+func (p *Processor) SetMsgCompressSize(id pb.CSMsgID, minCompressSize int32) {
+	p.encoder.SetMsgCompressSize(id, minCompressSize)
+}
+
+func (p *Processor) SetCompressPreference(pref []compress.ID) {
+	p.preference.Store(append([]compress.ID(nil), pref...))
+}
+
+// 根据客户端登录时上报的算法选出本连接使用的压缩算法
+// 未上报的旧客户端没有实现解压, 不做压缩
+func (p *Processor) SelectCompressor(offered []pb.COMPRESS_CODEC) compress.ID {
+	ids := make([]compress.ID, 0, len(offered))
+	for _, o := range offered {
+		ids = append(ids, compress.ID(o))
+	}
+	return compress.Select(p.preference.Load().([]compress.ID), ids)
+}
+
+func (p *Processor) SetAcceptLegacyFrame(accept bool) {
+	var v int32
+	if accept {
+		v = 1
+	}
+	atomic.StoreInt32(&p.acceptLegacy, v)
 }
 
-func (p *Processor) DealMsgExt(conn network.IConn, agent agent.Agent, key *aes.Key,
-	recvBuffer *bytes.Buffer, onceBuffer []byte, msgHandler func(pb.CSMsgID, []byte) bool) error {
+func (p *Processor) AcceptLegacyFrame() bool {
+	return atomic.LoadInt32(&p.acceptLegacy) == 1
+}
+
+// 为连接创建解码器, sess返回当前的会话密钥
+// 开启加密时, 会话为nil表示尚未完成握手, 此时只接受明文的握手消息
+// 对端需等待握手应答后再发送后续消息
+func (p *Processor) NewDecoder(sess func() *aes.Session) *codec.Decoder {
+	d := codec.NewDecoder(int(p.maxMsgLen), p.AcceptLegacyFrame())
+	if p.encrypt {
+		d.Cipher = func(id pb.CSMsgID) (codec.Cipher, error) {
+			if s := sess(); s != nil {
+				return s, nil
+			}
+			if !IsHandshake(id) {
+				return nil, fmt.Errorf("handshake required before %s", id)
+			}
+			return nil, nil
+		}
+	}
+	return d
+}
+
+func (p *Processor) DealMsgExt(conn network.IConn, agent agent.Agent, dec *codec.Decoder,
+	onceBuffer []byte, msgHandler func(pb.CSMsgID, []byte) bool) error {
 
 	// 从网络层读取数据
 	n, err := conn.Read(onceBuffer)
@@ -67,77 +126,23 @@ func (p *Processor) DealMsgExt(conn network.IConn, agent agent.Agent, key *aes.K
 	}
 
 	// 将数据串起来,方便处理粘包
-	_, err = recvBuffer.Write(onceBuffer[:n])
+	_, err = dec.Write(onceBuffer[:n])
 	if err != nil {
 		return err
 	}
 
 	// 处理消息包(处理粘包 一次最大处理16个包)
 	for i := 0; i < 16; i++ {
-		rlen := recvBuffer.Len()
-		if rlen < HeadLenSize { // 包不够长度
-			break
-		}
-
-		buf := recvBuffer.Bytes()
-		hlen := int32(buf[0])
-		if hlen > p.maxMsgLen {
-			return fmt.Errorf("message too long %d", hlen)
-		} else if hlen <= p.minMsgLen {
-			return fmt.Errorf("message too short %d", hlen)
-		}
-
-		if rlen < int(hlen)+HeadLenSize {
-			break
-		}
-
-		//parse head
-		h := &pb.CSHead{}
-		if err = p.Processor.Unmarshal(buf[HeadLenSize:hlen+HeadLenSize], h); err != nil {
-			return errors.New("Unmarshal head error:" + err.Error() + fmt.Sprintf(" headLen:%v", hlen))
-		}
-
-		if h.BodyLen > p.maxMsgLen {
-			return fmt.Errorf("message too long %v", h.BodyLen)
-		} else if h.BodyLen < p.minMsgLen {
-			return fmt.Errorf("message too short %v", h.BodyLen)
+		f, e := dec.Next()
+		if e != nil {
+			return e
 		}
-
-		pktLen := int(hlen) + HeadLenSize + int(h.BodyLen)
-		if rlen < pktLen {
+		if f == nil { // 包不够长度
 			break
 		}
 
-		data := recvBuffer.Next(pktLen)
-		var bodyBuf []byte
-		if h.BodyLen > 0 {
-			bodyBuf = make([]byte, h.BodyLen, h.BodyLen)
-			if copy(bodyBuf, data[int(hlen)+HeadLenSize:pktLen]) != int(h.BodyLen) { // 拷贝出错了
-				log.Release("copy err:%v", h)
-				return errors.New("copy err")
-			}
-
-			if p.encrypt {
-				//需要解密
-				if key == nil {
-					return errors.New("no Encrypt key")
-				}
-
-				if bodyBuf, err = aes.Decrypt(bodyBuf, key.K); err != nil {
-					return errors.New("Decrypt fail:" + err.Error())
-				}
-			}
-
-			if h.IsCompressed {
-				//需要解压缩
-				if bodyBuf, err = zlib.Compress(bodyBuf); err != nil {
-					return errors.New("DoZlibUnCompress fail:" + err.Error())
-				}
-			}
-		}
-
 		if msgHandler != nil {
-			if !msgHandler(h.MsgID, bodyBuf) {
+			if !msgHandler(f.MsgID, f.Body) {
 				return errors.New("msg handler err")
 			}
 		}
@@ -145,157 +150,136 @@ func (p *Processor) DealMsgExt(conn network.IConn, agent agent.Agent, key *aes.K
 	return nil
 }
 
-func (p *Processor) GetCompressData(bodyData []byte) ([]byte, bool) {
-	compress := false
-	bodyLen := int32(len(bodyData))
-
-	if bodyLen > 0 {
-		minCompressSize := p.GetMinCompressSize()
-		if minCompressSize != 0 && bodyLen >= minCompressSize {
-			//需要进行压缩
-			now := time.Now()
-			zipData, err := zlib.Compress(bodyData)
-			dt := time.Since(now)
-			if dt > 5*time.Millisecond {
-				// 统计压缩时间，对于超时的记录下基础信息
-				log.Warn("zlib.Compress timeout: %d(len), %d(dt)", len(bodyData), dt/1e6)
-			}
-			zipLen := int32(len(zipData))
-			if err == nil && zipLen < bodyLen {
-				bodyData = zipData
-				compress = true
-			}
-		}
+func (p *Processor) GetCompressData(id pb.CSMsgID, c compress.ID, bodyData []byte) ([]byte, compress.ID) {
+	now := time.Now()
+	bodyLen := len(bodyData)
+	bodyData, c = p.encoder.Compress(id, c, bodyData)
+	dt := time.Since(now)
+	if dt > 5*time.Millisecond {
+		// 统计压缩时间，对于超时的记录下基础信息
+		log.Warn("%s compress timeout: %d(len), %d(dt)", compress.Name(c), bodyLen, dt/1e6)
 	}
 
-	return bodyData, compress
+	return bodyData, c
 }
 
-func (p *Processor) encryptMsg(id pb.CSMsgID, bodyData []byte, key *aes.Key) ([]byte, error) {
-	if p.encrypt && len(bodyData) > 0 {
-		if key == nil {
-			return nil, errors.New("no Encrypt key")
-		}
-		now := time.Now()
-		cryptData, err := aes.Encrypt(bodyData, key.K)
-		dt := time.Since(now)
-		if dt > 5*time.Millisecond {
-			log.Release("aes.Encrypt timeout %v %v %v", id, len(bodyData), dt)
-		}
+// 握手消息始终以明文传输
+func IsHandshake(id pb.CSMsgID) bool {
+	return id == pb.CSMsgID_REQ_HANDSHAKE || id == pb.CSMsgID_RSP_HANDSHAKE
+}
 
-		if err != nil {
-			return nil, errors.New("Encrypt fail:" + err.Error())
+func (p *Processor) cipher(id pb.CSMsgID, sess *aes.Session) (codec.Cipher, error) {
+	if !p.encrypt {
+		return nil, nil
+	}
+	if sess == nil {
+		if IsHandshake(id) {
+			return nil, nil
 		}
-
-		bodyData = cryptData
+		return nil, errors.New("no session key")
 	}
-
-	return bodyData, nil
+	return sess, nil
 }
 
-func (p *Processor) doWriteData(conn network.IConn, id pb.CSMsgID, encryptedData []byte, compress bool) error {
-	bodyLen := int32(len(encryptedData))
-
-	h := &pb.CSHead{
-		MsgID:        id,
-		BodyLen:      bodyLen,
-		IsCompressed: compress,
-	}
-
-	headData, err := p.Processor.Marshal([]interface{}{int32(id), h})
-	if err != nil {
-		return errors.New("Marshal head error:" + err.Error())
+// 加密与写入在会话锁内完成, 保证帧序号与写入顺序一致
+// 分配下行序号后写入失败的消息留在重传缓冲中, 收到确认时重发
+func (p *Processor) Write2Socket(l Link, id pb.CSMsgID, byteMsg []byte, codecID compress.ID) error {
+	sess := l.GetSession()
+	c, er := p.cipher(id, sess)
+	if er != nil {
+		return er
 	}
-	headLen := int32(len(headData))
-
-	data := make([]byte, HeadLenSize+headLen+bodyLen)
-	// write headlen
-	switch HeadLenSize {
-	case 1:
-		data[0] = byte(headLen)
-	case 2:
-		if p.littleEndian {
-			binary.LittleEndian.PutUint16(data, uint16(headLen))
-		} else {
-			binary.BigEndian.PutUint16(data, uint16(headLen))
-		}
-	case 4:
-		if p.littleEndian {
-			binary.LittleEndian.PutUint32(data, uint32(headLen))
-		} else {
-			binary.BigEndian.PutUint32(data, uint32(headLen))
-		}
+	if c != nil {
+		sess.Lock()
+		defer sess.Unlock()
 	}
 
-	copy(data[HeadLenSize:], headData)
-	if bodyLen != 0 {
-		copy(data[HeadLenSize+headLen:], encryptedData)
+	ob := l.GetOutbox()
+	if ob == nil || IsHandshake(id) {
+		return p.write(l, id, 0, byteMsg, codecID, c)
 	}
 
-	return conn.Write(data)
+	ob.Lock()
+	defer ob.Unlock()
+	f := ob.push(id, byteMsg, codecID)
+	if er = p.write(l, id, f.seq, byteMsg, codecID, c); er != nil {
+		f.unsent = true
+	}
+	return er
 }
 
-func (p *Processor) CompressMsg(id pb.CSMsgID, message interface{}) ([]byte, bool, error) {
-	body, er := p.marshalMsg(id, message)
+// 丢弃对端已确认的下行消息并重发其后的消息, all为true时重发全部(断线恢复), 否则只重发写入失败的
+// 已从缓冲中丢弃的消息无法重发, 先通知对端重新同步
+func (p *Processor) Resend(l Link, ack int64, all bool) error {
+	ob := l.GetOutbox()
+	if ob == nil {
+		return nil
+	}
+
+	sess := l.GetSession()
+	c, er := p.cipher(pb.CSMsgID_NTF_RESYNC, sess)
 	if er != nil {
-		return nil, false, er
+		return er
+	}
+	if c != nil {
+		sess.Lock()
+		defer sess.Unlock()
 	}
 
-	var isCompressed bool
-	body, isCompressed = p.GetCompressData(body)
+	ob.Lock()
+	defer ob.Unlock()
+	frames, missed, next := ob.pending(ack, all)
+	if missed > 0 {
+		data, er := p.MarshalMsg(pb.CSMsgID_NTF_RESYNC, &pb.CSNtfBody{Resync: &pb.CSNtfResync{
+			Missed:  missed,
+			NextSeq: next,
+		}})
+		if er != nil {
+			return er
+		}
+		if er = p.write(l, pb.CSMsgID_NTF_RESYNC, 0, data, compress.None, c); er != nil {
+			return er
+		}
+	}
 
-	return body, isCompressed, nil
+	for _, f := range frames {
+		if er = p.write(l, f.id, f.seq, f.body, f.codec, c); er != nil {
+			f.unsent = true
+			return er
+		}
+		f.unsent = false
+	}
+	return nil
 }
 
-func (p *Processor) Write2Socket(conn network.IConn, id pb.CSMsgID, byteMsg []byte, isCompressed bool, key *aes.Key) error {
-	data, er := p.encryptMsg(id, byteMsg, key)
+func (p *Processor) write(l Link, id pb.CSMsgID, seq int64, byteMsg []byte, codecID compress.ID, c codec.Cipher) error {
+	data, er := p.encoder.Encode(l.GetFrameVersion(), id, seq, byteMsg, codecID, c)
 	if er != nil {
 		return er
 	}
 
-	return p.doWriteData(conn, id, data, isCompressed)
+	// 未进入写队列的帧没有发出, 收回其加密序号, 重发时使用同一序号
+	if er = l.GetConn().Write(data); er != nil && c != nil {
+		l.GetSession().Unseal()
+	}
+	return er
 }
 
-func (p *Processor) doWriteBodyData(conn network.IConn, id pb.CSMsgID, bodyData []byte, key *aes.Key) error {
-
-	var isCompressed bool
-	if len(bodyData) > 0 {
-		bodyData, isCompressed = p.GetCompressData(bodyData)
-
-		if p.encrypt && len(bodyData) > 0 {
-			//需要加密
-			if key == nil {
-				return errors.New("no Encrypt key")
-			}
-			now := time.Now()
-			cryptData, err := aes.Encrypt(bodyData, key.K)
-			dt := time.Since(now)
-			if dt > 5*time.Millisecond {
-				log.Release("aes.Encrypt timeout %s %d %s", id, len(bodyData), dt)
-			}
-
-			if err != nil {
-				return errors.New("Encrypt fail:" + err.Error())
-			}
-
-			bodyData = cryptData
-		}
+func (p *Processor) WriteMsg(l Link, id pb.CSMsgID, msg interface{}) error {
+	bodyData, err := p.MarshalMsg(id, msg)
+	if err != nil {
+		return err
 	}
 
-	return p.doWriteData(conn, id, bodyData, isCompressed)
-}
-
-func (p *Processor) WriteMsg(conn network.IConn, id pb.CSMsgID, msg interface{}, key *aes.Key) error {
-	copyKey := *key
-	er := p.doWriteMsg(conn, id, msg, &copyKey)
-	if er != nil {
-		return er
+	c := compress.None
+	if len(bodyData) > 0 {
+		bodyData, c = p.GetCompressData(id, l.GetCompressor(), bodyData)
 	}
 
-	return nil
+	return p.Write2Socket(l, id, bodyData, c)
 }
 
-func (p *Processor) marshalMsg(id pb.CSMsgID, msg interface{}) ([]byte, error) {
+func (p *Processor) MarshalMsg(id pb.CSMsgID, msg interface{}) ([]byte, error) {
 	if msg == nil {
 		return nil, nil
 	}
@@ -313,14 +297,6 @@ func (p *Processor) marshalMsg(id pb.CSMsgID, msg interface{}) ([]byte, error) {
 	return bodyData, nil
 }
 
-func (p *Processor) doWriteMsg(conn network.IConn, id pb.CSMsgID, msg interface{}, key *aes.Key) error {
-	bodyData, err := p.marshalMsg(id, msg)
-	if err != nil {
-		return err
-	}
-	return p.doWriteBodyData(conn, id, bodyData, key)
-}
-
 func (p *Processor) NeedEncrypt() bool {
 	return p.encrypt
 }
@@ -0,0 +1,98 @@
+package cs
+
+import (
+	"cloudcadetest/common/compress"
+	"cloudcadetest/pb"
+	"sync"
+)
+
+// 缓存的下行消息, 保存压缩后、加密前的包体, 重发时按当前会话重新加密
+type outFrame struct {
+	seq    int64
+	id     pb.CSMsgID
+	body   []byte
+	codec  compress.ID
+	unsent bool // 写入连接失败, 等待重发
+}
+
+// 下行消息的序号分配与重传缓冲, 每个玩家一个, 断线恢复时随会话转移
+// 超过容量时丢弃最早的消息, 其中未发出的计入丢失, 下次重发时通知客户端重新同步
+type Outbox struct {
+	sync.Mutex
+	size    int
+	seq     int64 // 最近分配的序号
+	frames  []*outFrame
+	lost    int64 // 未发出就被丢弃的消息数
+	lostSeq int64 // 其中最大的序号
+}
+
+func NewOutbox(size int) *Outbox {
+	if size <= 0 {
+		size = 1
+	}
+	return &Outbox{size: size}
+}
+
+// 最近分配的序号
+func (o *Outbox) Seq() int64 {
+	o.Lock()
+	defer o.Unlock()
+	return o.seq
+}
+
+// 分配序号并缓存, 调用方需持有锁
+func (o *Outbox) push(id pb.CSMsgID, body []byte, codec compress.ID) *outFrame {
+	if len(o.frames) >= o.size {
+		if head := o.frames[0]; head.unsent {
+			o.lost++
+			o.lostSeq = head.seq
+		}
+		o.frames[0] = nil
+		o.frames = o.frames[1:]
+	}
+
+	o.seq++
+	f := &outFrame{seq: o.seq, id: id, body: body, codec: codec}
+	o.frames = append(o.frames, f)
+	return f
+}
+
+// 丢弃已确认的消息, 返回需要重发的消息、缺失的数量与此后第一条可重发的序号
+// all为true时重发确认之后的全部消息(断线恢复), 否则只重发写入失败的
+// 调用方需持有锁
+func (o *Outbox) pending(ack int64, all bool) ([]*outFrame, int64, int64) {
+	i := 0
+	for i < len(o.frames) && o.frames[i].seq <= ack {
+		o.frames[i] = nil
+		i++
+	}
+	o.frames = o.frames[i:]
+
+	next := o.seq + 1
+	if len(o.frames) > 0 {
+		next = o.frames[0].seq
+	}
+
+	var (
+		frames []*outFrame
+		missed int64
+	)
+	if all {
+		frames = append(frames, o.frames...)
+		if next-ack-1 > 0 && ack < o.seq {
+			missed = next - ack - 1
+		}
+	} else {
+		for _, f := range o.frames {
+			if f.unsent {
+				frames = append(frames, f)
+			}
+		}
+		if o.lostSeq > ack {
+			missed = o.lost
+		}
+	}
+	o.lost, o.lostSeq = 0, 0
+
+	return frames, missed, next
+}
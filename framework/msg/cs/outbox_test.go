@@ -0,0 +1,189 @@
+package cs
+
+import (
+	"cloudcadetest/common/compress"
+	"cloudcadetest/common/encrypt/aes"
+	"cloudcadetest/framework/msg/codec"
+	"cloudcadetest/framework/network"
+	"cloudcadetest/framework/network/protobuf"
+	"cloudcadetest/pb"
+	"errors"
+	"github.com/golang/protobuf/proto"
+	"net"
+	"testing"
+)
+
+// 写入的帧交给解码器, full为true时模拟写队列已满
+type fakeConn struct {
+	dec  *codec.Decoder
+	full bool
+}
+
+func (c *fakeConn) Read([]byte) (int, error) { return 0, errors.New("not implemented") }
+func (c *fakeConn) ReadFull([]byte) error    { return errors.New("not implemented") }
+func (c *fakeConn) LocalAddr() net.Addr      { return nil }
+func (c *fakeConn) RemoteAddr() net.Addr     { return nil }
+func (c *fakeConn) Close()                   {}
+func (c *fakeConn) Destroy()                 {}
+func (c *fakeConn) WriteTask()               {}
+func (c *fakeConn) WriteQueueLen() int       { return 0 }
+
+func (c *fakeConn) Write(b []byte) error {
+	if c.full {
+		return errors.New("write channel full")
+	}
+	_, e := c.dec.Write(b)
+	return e
+}
+
+type fakeLink struct {
+	conn   *fakeConn
+	outbox *Outbox
+	sess   *aes.Session
+}
+
+func (l *fakeLink) GetConn() network.IConn              { return l.conn }
+func (l *fakeLink) GetSession() *aes.Session            { return l.sess }
+func (l *fakeLink) GetFrameVersion() codec.FrameVersion { return codec.FrameV1 }
+func (l *fakeLink) GetCompressor() compress.ID          { return compress.None }
+func (l *fakeLink) GetOutbox() *Outbox                  { return l.outbox }
+
+func newTestLink(size int) *fakeLink {
+	return &fakeLink{
+		conn:   &fakeConn{dec: codec.NewDecoder(10000, true)},
+		outbox: NewOutbox(size),
+	}
+}
+
+// 取出已写入的帧, 返回序号; 重新同步通知返回其NextSeq的相反数
+func drain(t *testing.T, l *fakeLink) []int64 {
+	var seqs []int64
+	for {
+		f, e := l.conn.dec.Next()
+		if e != nil {
+			t.Fatal(e)
+		}
+		if f == nil {
+			return seqs
+		}
+		if f.MsgID == pb.CSMsgID_NTF_RESYNC {
+			ntf := &pb.CSNtfBody{}
+			if e = proto.Unmarshal(f.Body, ntf); e != nil {
+				t.Fatal(e)
+			}
+			seqs = append(seqs, -ntf.Resync.NextSeq)
+			continue
+		}
+		seqs = append(seqs, f.Seq)
+	}
+}
+
+func equal(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestProcessor_Resend(t *testing.T) {
+	p := &Processor{Processor: protobuf.NewProcessor(), encoder: codec.NewEncoder(0)}
+	msg := &pb.CSNtfBody{RoomChat: &pb.CSNtfRoomChat{Content: "hi"}}
+	send := func(l *fakeLink, n int) {
+		for i := 0; i < n; i++ {
+			p.WriteMsg(l, pb.CSMsgID_NTF_ROOM_CHAT, msg)
+		}
+	}
+
+	// 写入失败的消息在确认时按序重发
+	l := newTestLink(8)
+	send(l, 2)
+	l.conn.full = true
+	send(l, 2)
+	l.conn.full = false
+	send(l, 1)
+	if got := drain(t, l); !equal(got, []int64{1, 2, 5}) {
+		t.Fatalf("sent %v", got)
+	}
+	if e := p.Resend(l, 2, false); e != nil {
+		t.Fatal(e)
+	}
+	if got := drain(t, l); !equal(got, []int64{3, 4}) {
+		t.Fatalf("resent %v", got)
+	}
+	if e := p.Resend(l, 5, false); e != nil {
+		t.Fatal(e)
+	}
+	if got := drain(t, l); len(got) != 0 {
+		t.Fatalf("nothing to resend, got %v", got)
+	}
+
+	// 未发出就被挤出缓冲时先通知重新同步
+	l = newTestLink(3)
+	l.conn.full = true
+	send(l, 2)
+	l.conn.full = false
+	send(l, 3)
+	drain(t, l)
+	if e := p.Resend(l, 0, false); e != nil {
+		t.Fatal(e)
+	}
+	if got := drain(t, l); !equal(got, []int64{-3}) {
+		t.Fatalf("resync %v", got)
+	}
+
+	// 断线恢复时重发确认之后的全部消息
+	l = newTestLink(4)
+	send(l, 6)
+	drain(t, l)
+	if e := p.Resend(l, 1, true); e != nil {
+		t.Fatal(e)
+	}
+	if got := drain(t, l); !equal(got, []int64{-3, 3, 4, 5, 6}) {
+		t.Fatalf("resume after eviction %v", got)
+	}
+	if e := p.Resend(l, 4, true); e != nil {
+		t.Fatal(e)
+	}
+	if got := drain(t, l); !equal(got, []int64{5, 6}) {
+		t.Fatalf("resume resent %v", got)
+	}
+}
+
+// 加密时写队列满的帧收回序号, 队列空出后重发的帧与之后的帧都能解密
+func TestProcessor_ResendEncrypted(t *testing.T) {
+	p := &Processor{Processor: protobuf.NewProcessor(), encoder: codec.NewEncoder(0), encrypt: true}
+	k1, k2 := make([]byte, 32), make([]byte, 32)
+	k2[0] = 1
+	server, _ := aes.NewSession(k1, k2)
+	client, _ := aes.NewSession(k2, k1)
+
+	l := newTestLink(8)
+	l.sess = server
+	l.conn.dec.Cipher = func(pb.CSMsgID) (codec.Cipher, error) { return client, nil }
+	msg := &pb.CSNtfBody{RoomChat: &pb.CSNtfRoomChat{Content: "hi"}}
+	send := func(n int) {
+		for i := 0; i < n; i++ {
+			p.WriteMsg(l, pb.CSMsgID_NTF_ROOM_CHAT, msg)
+		}
+	}
+
+	send(1)
+	l.conn.full = true
+	send(2)
+	l.conn.full = false
+	if got := drain(t, l); !equal(got, []int64{1}) {
+		t.Fatalf("sent %v", got)
+	}
+	if e := p.Resend(l, 1, false); e != nil {
+		t.Fatal(e)
+	}
+	send(1)
+	if got := drain(t, l); !equal(got, []int64{2, 3, 4}) {
+		t.Fatalf("resent %v", got)
+	}
+}
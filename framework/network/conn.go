@@ -12,4 +12,5 @@ type IConn interface {
 	Destroy()
 
 	WriteTask()
+	WriteQueueLen() int // 尚未写入socket的消息数
 }
@@ -0,0 +1,137 @@
+package network
+
+import (
+	"cloudcadetest/framework/log"
+	"errors"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 平滑重启时由父进程传入的监听地址, 第i个地址对应fd 3+i
+const (
+	EnvInheritListeners = "CC_INHERIT_LISTENERS"
+	inheritFDBase       = 3
+)
+
+var (
+	inheritOnce  sync.Once
+	inheritedFDs map[string]int
+
+	listenersMutex sync.Mutex
+	listeners      = map[string]net.Listener{} // 当前进程持有的监听, 按地址索引
+)
+
+func inheritedFD(addr string) (int, bool) {
+	inheritOnce.Do(func() {
+		inheritedFDs = map[string]int{}
+		env := os.Getenv(EnvInheritListeners)
+		if env == "" {
+			return
+		}
+		for i, a := range strings.Split(env, ",") {
+			inheritedFDs[a] = inheritFDBase + i
+		}
+	})
+
+	fd, ok := inheritedFDs[addr]
+	return fd, ok
+}
+
+// 优先使用继承的监听socket, 否则重新监听
+func listen(addr string) (net.Listener, error) {
+	if fd, ok := inheritedFD(addr); ok {
+		f := os.NewFile(uintptr(fd), addr)
+		ln, err := net.FileListener(f)
+		if e := f.Close(); e != nil {
+			log.Warn("close inherited fd %d failed:%s", fd, e.Error())
+		}
+		if err == nil {
+			log.Release("inherit listener[%s] from fd %d", addr, fd)
+			registerListener(addr, ln)
+			return ln, nil
+		}
+		log.Warn("inherit listener[%s] failed:%s, listen again", addr, err.Error())
+	}
+
+	var (
+		ln  net.Listener
+		err error
+	)
+	for i := 0; ; i++ {
+		ln, err = net.Listen("tcp4", addr)
+		if err == nil {
+			break
+		}
+		if i >= RepeatCnt {
+			return nil, err
+		}
+		time.Sleep(time.Second)
+	}
+
+	registerListener(addr, ln)
+	return ln, nil
+}
+
+func registerListener(addr string, ln net.Listener) {
+	listenersMutex.Lock()
+	listeners[addr] = ln
+	listenersMutex.Unlock()
+}
+
+func unregisterListener(addr string) {
+	listenersMutex.Lock()
+	delete(listeners, addr)
+	listenersMutex.Unlock()
+}
+
+// 导出当前所有监听socket的副本, 用于交给新进程; 调用方负责关闭返回的文件
+func ListenerFiles() ([]string, []*os.File, error) {
+	listenersMutex.Lock()
+	defer listenersMutex.Unlock()
+
+	addrs := make([]string, 0, len(listeners))
+	for addr := range listeners {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	files := make([]*os.File, 0, len(addrs))
+	for _, addr := range addrs {
+		tl, ok := listeners[addr].(*net.TCPListener)
+		if !ok {
+			closeFiles(files)
+			return nil, nil, errors.New("not a tcp listener: " + addr)
+		}
+		f, err := tl.File()
+		if err != nil {
+			closeFiles(files)
+			return nil, nil, err
+		}
+		files = append(files, f)
+	}
+
+	return addrs, files, nil
+}
+
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		if e := f.Close(); e != nil {
+			log.Warn("close file %s failed:%s", f.Name(), e.Error())
+		}
+	}
+}
+
+// 其他模块的监听(如管理端口)同样在平滑重启时交给新进程
+func Listen(addr string) (net.Listener, error) {
+	return listen(addr)
+}
+
+// 关闭Listen得到的监听, 之后不再交给新进程
+func CloseListener(addr string, ln net.Listener) error {
+	unregisterListener(addr)
+	return ln.Close()
+}
@@ -0,0 +1,86 @@
+package network
+
+import (
+	"sync"
+	"time"
+)
+
+// 拒绝连接的原因, 用作指标的标签
+const (
+	rejectBanned  = "banned"
+	rejectRate    = "rate_limit"
+	rejectMaxConn = "max_conn"
+)
+
+const defaultMaxConnNum = 100
+
+// 接受连接时的限制: 被封禁的IP、每秒新连接数与总连接数
+// 多个服务器传入同一个AcceptLimiter时共用计数, 如网关的TCP与WS
+type AcceptLimiter struct {
+	funcAllowIP    func(ip string) bool // 为nil时不检查
+	funcMaxConnNum func() int
+	perSecond      int32 // 为0时不限
+
+	mutex  sync.Mutex
+	second int64 // 当前统计的秒
+	count  int32 // 本秒内已接受的连接数
+	conns  int   // 已登记的连接数
+}
+
+func NewAcceptLimiter(funcAllowIP func(ip string) bool, funcMaxConnNum func() int, perSecond int32) *AcceptLimiter {
+	return &AcceptLimiter{
+		funcAllowIP:    funcAllowIP,
+		funcMaxConnNum: funcMaxConnNum,
+		perSecond:      perSecond,
+	}
+}
+
+// 在登记连接前检查, 返回拒绝的原因, 可以接受时为空
+func (l *AcceptLimiter) admit(addr string, now time.Time) string {
+	if l.funcAllowIP != nil && !l.funcAllowIP(hostOf(addr)) {
+		return rejectBanned
+	}
+	if l.perSecond <= 0 {
+		return ""
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if sec := now.Unix(); sec != l.second {
+		l.second, l.count = sec, 0
+	}
+	if l.count >= l.perSecond {
+		return rejectRate
+	}
+	l.count++
+	return ""
+}
+
+// 登记一个连接, 已满时返回false; 登记成功的连接关闭后需调用release
+func (l *AcceptLimiter) acquire() bool {
+	max := l.maxConnNum()
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.conns >= max {
+		return false
+	}
+	l.conns++
+	return true
+}
+
+func (l *AcceptLimiter) release() {
+	l.mutex.Lock()
+	l.conns--
+	l.mutex.Unlock()
+}
+
+func (l *AcceptLimiter) maxConnNum() int {
+	max := 0
+	if l.funcMaxConnNum != nil {
+		max = l.funcMaxConnNum()
+	}
+	if max == 0 {
+		max = defaultMaxConnNum
+	}
+	return max
+}
@@ -0,0 +1,97 @@
+package network
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAcceptLimiter(t *testing.T) {
+	l := NewAcceptLimiter(func(ip string) bool { return ip != "10.0.0.1" }, func() int { return 3 }, 2)
+	now := time.Unix(1000, 0)
+	if r := l.admit("10.0.0.1:1234", now); r != rejectBanned {
+		t.Fatalf("banned ip:%q", r)
+	}
+	for i, want := range []string{"", "", rejectRate, rejectRate} {
+		if r := l.admit("10.0.0.2:1234", now.Add(time.Duration(i)*time.Millisecond)); r != want {
+			t.Fatalf("conn %d:%q want %q", i, r, want)
+		}
+	}
+	if r := l.admit("10.0.0.2:1234", now.Add(time.Second)); r != "" {
+		t.Fatalf("next second:%q", r)
+	}
+	for i := 0; i < 3; i++ {
+		if !l.acquire() {
+			t.Fatalf("conn %d rejected", i)
+		}
+	}
+	if l.acquire() {
+		t.Fatal("max conn should be 3")
+	}
+	l.release()
+	if !l.acquire() {
+		t.Fatal("released conn should free a slot")
+	}
+	if NewAcceptLimiter(nil, nil, 0).maxConnNum() != defaultMaxConnNum {
+		t.Fatal("default max conn")
+	}
+}
+
+func (l *AcceptLimiter) connNum() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.conns
+}
+
+// TCP占满共用的连接数后WS连接被拒绝, TCP连接断开后WS可以连接
+func TestAcceptLimiter_Shared(t *testing.T) {
+	l := NewAcceptLimiter(nil, func() int { return 2 }, 0)
+	server := &TCPServer{Addr: "127.0.0.1:0", NewAgent: newTLSEchoAgent, Limiter: l}
+	server.Start()
+	defer server.Close()
+	ws := httptest.NewServer(&WSHandler{
+		limiter:         l,
+		pendingWriteNum: 10,
+		maxMsgLen:       1024,
+		newAgent:        newEchoAgent,
+		conns:           make(WSConnSet),
+	})
+	defer ws.Close()
+
+	waitConns := func(n int) {
+		for i := 0; l.connNum() != n; i++ {
+			if i >= 200 {
+				t.Fatalf("conns %d, want %d", l.connNum(), n)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	var tcpConns []net.Conn
+	for i := 0; i < 2; i++ {
+		c, err := net.Dial("tcp", server.ln.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+		tcpConns = append(tcpConns, c)
+	}
+	waitConns(2)
+
+	req, _ := http.NewRequest(http.MethodGet, ws.URL, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	if rsp, err := http.DefaultClient.Do(req); err == nil {
+		rsp.Body.Close()
+		t.Fatalf("ws connection accepted beyond the shared cap, status %d", rsp.StatusCode)
+	}
+	waitConns(2)
+
+	tcpConns[0].Close()
+	waitConns(1)
+	conn, _ := dialWS(t, ws.URL)
+	conn.Close()
+}
@@ -0,0 +1,17 @@
+package network
+
+import "cloudcadetest/framework/metrics"
+
+var (
+	tcpAccepted = metrics.NewCounter("cc_tcp_accepted_total", "TCP connections accepted by TCPServer.")
+	tcpRejected = metrics.NewCounterVec("cc_tcp_rejected_total", "TCP connections closed right after accept.", "reason")
+	wsRejected  = metrics.NewCounterVec("cc_ws_rejected_total", "WebSocket upgrade requests refused by WSServer.", "reason")
+
+	tcpWriteQueueDepth = metrics.NewGauge("cc_tcp_write_queue_depth", "Messages queued in TCPConn write channels, summed over all connections.")
+	tcpWriteDropped    = metrics.NewCounterVec("cc_tcp_write_dropped_total", "Messages dropped by TCPConn.Write.", "reason")
+)
+
+var (
+	tcpDroppedFull   = tcpWriteDropped.With("full")
+	tcpDroppedClosed = tcpWriteDropped.With("closed")
+)
@@ -55,12 +55,15 @@ func (tcpConn *TCPConn) Write(b []byte) error {
 	}
 
 	if tcpConn.isClosed {
+		tcpDroppedClosed.Inc()
 		return errors.New("closed write channel")
 	}
 
 	select {
 	case tcpConn.writeChan <- b:
+		tcpWriteQueueDepth.Inc()
 	default:
+		tcpDroppedFull.Inc()
 		return errors.New("full write channel")
 	}
 
@@ -84,8 +87,13 @@ func (tcpConn *TCPConn) RemoteAddr() net.Addr {
 	return tcpConn.conn.RemoteAddr()
 }
 
+func (tcpConn *TCPConn) WriteQueueLen() int {
+	return len(tcpConn.writeChan)
+}
+
 func (tcpConn *TCPConn) WriteTask() {
 	for b := range tcpConn.writeChan {
+		tcpWriteQueueDepth.Dec()
 		_, err := tcpConn.conn.Write(b)
 		if err != nil {
 			log.Warn("tcpconn write fail[%s]", err.Error())
@@ -3,6 +3,7 @@ package network
 import (
 	"cloudcadetest/framework/agent"
 	"cloudcadetest/framework/log"
+	"crypto/tls"
 	"net"
 	"sync"
 	"time"
@@ -17,6 +18,14 @@ type TCPClient struct {
 	closeFlag       bool
 	ReconnectFlag   bool //能否断线重连
 	DisconnectCB    func(string)
+
+	// TLS, TLSConfig优先, 否则由以下配置生成; UseTLS为false且TLSConfig为空时为明文
+	UseTLS     bool
+	TLSConfig  *tls.Config
+	CertFile   string // 双向认证时出示的客户端证书
+	KeyFile    string
+	CAFile     string // 为空则使用系统根证书
+	ServerName string
 	sync.Mutex
 }
 
@@ -40,6 +49,13 @@ func (client *TCPClient) init() {
 	if client.conn != nil {
 		log.Fatal("client is running")
 	}
+	if client.TLSConfig == nil && client.UseTLS {
+		cfg, err := NewClientTLSConfig(client.CertFile, client.KeyFile, client.CAFile, client.ServerName)
+		if err != nil {
+			log.Fatal("invalid tls config: %v", err)
+		}
+		client.TLSConfig = cfg
+	}
 
 	client.closeFlag = false
 	client.ReconnectFlag = true
@@ -80,14 +96,19 @@ func (client *TCPClient) connect() bool {
 	client.conn = c
 	client.Unlock()
 
+	start := time.Now()
 	conn := newTCPConn(client.conn, client.PendingWriteNum)
 	client.NewAgent(conn)
 
 	client.Lock()
-	defer client.Unlock()
-
 	client.conn = nil
+	closed := client.closeFlag
+	client.Unlock()
 
+	// 连接后很快被断开(如被服务端拒绝)时等待后再重连, 避免空转
+	if !closed && time.Since(start) < client.ConnectInterval {
+		time.Sleep(client.ConnectInterval)
+	}
 	return true
 }
 
@@ -100,7 +121,17 @@ func (client *TCPClient) dial() net.Conn {
 			tcpConn.SetKeepAlive(true)
 			tcpConn.SetKeepAlivePeriod(time.Second * 5)
 		}
-		return conn
+		if client.TLSConfig == nil {
+			return conn
+		}
+
+		tlsConn := tls.Client(conn, client.TLSConfig)
+		tlsConn.SetDeadline(time.Now().Add(tlsHandshakeTimeoutSecs * time.Second))
+		if err = tlsConn.Handshake(); err == nil {
+			tlsConn.SetDeadline(time.Time{})
+			return tlsConn
+		}
+		conn.Close()
 	}
 
 	log.Warn("connect to %v error: %v", client.Addr, err)
@@ -3,10 +3,10 @@ package network
 import (
 	"cloudcadetest/framework/agent"
 	"cloudcadetest/framework/log"
+	"crypto/tls"
 	"errors"
 	"net"
 	"sync"
-	"sync/atomic"
 	"time"
 )
 
@@ -15,11 +15,22 @@ type TCPServer struct {
 	FuncMaxConnNum  func() int
 	PendingWriteNum int
 	NewAgent        func(*TCPConn) agent.Agent
+	FuncAllowIP     func(ip string) bool // 为nil时不检查, 返回false则直接断开
 	ln              net.Listener
+	lnOnce          sync.Once
 
-	offChan             chan bool // 是否退出了
-	NumberOfConn        int32     // 本次统计内的连接数
-	ConnNumberPerSecond int32     // 1s允许的连接数
+	// TLS, TLSConfig优先, 否则由证书文件生成; 均为空时为明文
+	TLSConfig    *tls.Config
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string // 不为空则开启双向认证
+
+	TLSHandshakeTimeout time.Duration // 握手超时, 为0时取tlsHandshakeTimeoutSecs
+
+	ConnNumberPerSecond int32 // 1s允许的连接数, 为0不限
+
+	// 与其它服务器共用的连接限制, 为nil时按FuncAllowIP、FuncMaxConnNum与ConnNumberPerSecond创建
+	Limiter *AcceptLimiter
 
 	conns      ConnSet
 	mutexConns sync.Mutex // 不是很优雅，暂时先这样做
@@ -36,34 +47,32 @@ func (server *TCPServer) Start() {
 		return
 	}
 
-	if server.ConnNumberPerSecond > 0 { // 做了连接限制
-		go server.tick()
-	}
-
 	go server.run()
 }
 
 func (server *TCPServer) init() error {
-	i := 0
-	var ln net.Listener
-	var err error
-	for {
-		ln, err = net.Listen("tcp4", server.Addr)
-		if err != nil || ln == nil {
-			if i < RepeatCnt {
-				i++
-				time.Sleep(time.Second)
-				continue
-			}
+	ln, err := listen(server.Addr)
+	if err != nil {
+		return err
+	}
+
+	if server.TLSConfig == nil && server.CertFile != "" {
+		server.TLSConfig, err = NewServerTLSConfig(server.CertFile, server.KeyFile, server.ClientCAFile)
+		if err != nil {
+			closeListener(ln)
 			return err
-		} else {
-			//成功
-			break
 		}
 	}
+	if server.TLSConfig != nil {
+		ln = tls.NewListener(ln, server.TLSConfig)
+		log.Release("listen tcp[%v] with tls", server.Addr)
+	} else {
+		log.Release("listen tcp[%v]", server.Addr)
+	}
 
-	log.Release("listen tcp[%v]", server.Addr)
-
+	if server.TLSHandshakeTimeout <= 0 {
+		server.TLSHandshakeTimeout = tlsHandshakeTimeoutSecs * time.Second
+	}
 	if server.PendingWriteNum <= 0 {
 		server.PendingWriteNum = 100
 	}
@@ -72,27 +81,16 @@ func (server *TCPServer) init() error {
 	}
 
 	server.ln = ln
+	if server.Limiter == nil {
+		server.Limiter = NewAcceptLimiter(server.FuncAllowIP, server.FuncMaxConnNum, server.ConnNumberPerSecond)
+	}
 	server.conns = make(ConnSet)
 	return nil
 }
 
-/*
- * 开协程的目的主要是想借用系统的time来处理 而不愿采用加减时间来判定
- * 开协程只涉及到原子操作，对于开销上可不计
- */
-func (server *TCPServer) tick() {
-	server.wgLn.Add(1)
-	defer server.wgLn.Done()
-
-	server.offChan = make(chan bool)
-	t := time.NewTicker(time.Second)
-	for {
-		select {
-		case <-server.offChan:
-			return
-		case <-t.C:
-			atomic.StoreInt32(&server.NumberOfConn, 0)
-		}
+func closeListener(ln net.Listener) {
+	if e := ln.Close(); e != nil {
+		log.Error("close listener failed:%s", e.Error())
 	}
 }
 
@@ -109,12 +107,7 @@ func closeConn(conn net.Conn) {
 
 func (server *TCPServer) run() {
 	server.wgLn.Add(1)
-	defer func() {
-		if server.ConnNumberPerSecond > 0 {
-			server.offChan <- true
-		}
-		server.wgLn.Done()
-	}()
+	defer server.wgLn.Done()
 
 	var tempDelay time.Duration
 	for {
@@ -136,32 +129,23 @@ func (server *TCPServer) run() {
 			return
 		}
 		tempDelay = 0
+		tcpAccepted.Inc()
 
-		if server.ConnNumberPerSecond > 0 { // 开启了限流
-			num := atomic.AddInt32(&server.NumberOfConn, 1)
-			if num >= server.ConnNumberPerSecond { // 超过每秒允许的连接数 直接断开
-				closeConn(conn)
-				log.Warn("too many connections per second [%s->%s]", num, server.ConnNumberPerSecond)
-				continue
-			}
-		}
-
-		maxConnNum := 0
-		if server.FuncMaxConnNum != nil {
-			maxConnNum = server.FuncMaxConnNum()
-		}
-		if maxConnNum == 0 {
-			maxConnNum = 100
+		if reason := server.Limiter.admit(conn.RemoteAddr().String(), time.Now()); reason != "" {
+			closeConn(conn)
+			tcpRejected.With(reason).Inc()
+			log.Release("reject connection %s:%s", conn.RemoteAddr(), reason)
+			continue
 		}
 
-		server.mutexConns.Lock()
-		if len(server.conns) >= maxConnNum {
-			server.mutexConns.Unlock()
+		if !server.Limiter.acquire() {
 			closeConn(conn)
-			log.Release("too many connections %d", maxConnNum)
+			tcpRejected.With(rejectMaxConn).Inc()
+			log.Release("too many connections %d", server.Limiter.maxConnNum())
 			continue
 		}
 
+		server.mutexConns.Lock()
 		server.conns[conn] = struct{}{}
 		server.mutexConns.Unlock()
 
@@ -172,12 +156,20 @@ func (server *TCPServer) run() {
 	}
 }
 
-func (server *TCPServer) Close() {
-	e := server.ln.Close()
-	if e != nil {
-		log.Error("close server listener failed:%s", e.Error())
-	}
+// 停止接受新连接, 已有连接不受影响
+func (server *TCPServer) StopAccept() {
+	server.lnOnce.Do(func() {
+		unregisterListener(server.Addr)
+		e := server.ln.Close()
+		if e != nil {
+			log.Error("close server listener failed:%s", e.Error())
+		}
+	})
 	server.wgLn.Wait()
+}
+
+func (server *TCPServer) Close() {
+	server.StopAccept()
 
 	server.mutexConns.Lock()
 	for conn := range server.conns {
@@ -188,12 +180,42 @@ func (server *TCPServer) Close() {
 	server.wgConns.Wait()
 }
 
+// 去掉端口, 无法解析时原样返回
+func hostOf(addr string) string {
+	host, _, e := net.SplitHostPort(addr)
+	if e != nil {
+		return addr
+	}
+	return host
+}
+
+// 握手在连接自己的协程中完成, 不出声的客户端超时后断开, 不占用连接数
+func (server *TCPServer) handshake(conn net.Conn) error {
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return nil
+	}
+	if e := tlsConn.SetDeadline(time.Now().Add(server.TLSHandshakeTimeout)); e != nil {
+		return e
+	}
+	if e := tlsConn.Handshake(); e != nil {
+		return e
+	}
+	return tlsConn.SetDeadline(time.Time{})
+}
+
 func (server *TCPServer) newAgent(tcpConn *TCPConn) {
-	server.NewAgent(tcpConn)
+	if e := server.handshake(tcpConn.conn); e != nil {
+		log.Release("tls handshake with %s failed:%s", tcpConn.conn.RemoteAddr(), e.Error())
+		closeConn(tcpConn.conn)
+	} else {
+		server.NewAgent(tcpConn)
+	}
 
 	// cleanup
 	server.mutexConns.Lock()
 	delete(server.conns, tcpConn.conn)
 	server.mutexConns.Unlock()
+	server.Limiter.release()
 	server.wgConns.Done()
 }
@@ -0,0 +1,195 @@
+package network
+
+import (
+	"cloudcadetest/framework/agent"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func writePEM(t *testing.T, path, typ string, b []byte) {
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 签发证书并写入dir/name.crt, dir/name.key; parent为空时自签名
+func issueCert(t *testing.T, dir, name string, parent *testCert, isCA bool) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+
+	signer, signerKey := tpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, filepath.Join(dir, name+".crt"), "CERTIFICATE", der)
+	writePEM(t, filepath.Join(dir, name+".key"), "EC PRIVATE KEY", keyDer)
+
+	return &testCert{cert: cert, key: key}
+}
+
+type tlsEchoAgent struct {
+	conn *TCPConn
+}
+
+func (a *tlsEchoAgent) OnClose(code uint) {}
+
+func (a *tlsEchoAgent) Addr() string {
+	return a.conn.RemoteAddr().String()
+}
+
+func newTLSEchoAgent(conn *TCPConn) agent.Agent {
+	buf := make([]byte, 64)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			break
+		}
+		if _, err = conn.conn.Write(buf[:n]); err != nil {
+			break
+		}
+	}
+	return &tlsEchoAgent{conn: conn}
+}
+
+func TestTCPServer_MutualTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca := issueCert(t, dir, "ca", nil, true)
+	issueCert(t, dir, "server", ca, false)
+	issueCert(t, dir, "client", ca, false)
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	server := &TCPServer{
+		Addr:         "127.0.0.1:0",
+		NewAgent:     newTLSEchoAgent,
+		CertFile:     path("server.crt"),
+		KeyFile:      path("server.key"),
+		ClientCAFile: path("ca.crt"),
+	}
+	server.Start()
+	defer server.ln.Close()
+
+	dial := func(cfg *tls.Config) error {
+		client := &TCPClient{Addr: server.ln.Addr().String(), TLSConfig: cfg}
+		conn := client.dial()
+		if conn == nil {
+			return io.ErrUnexpectedEOF
+		}
+		defer conn.Close()
+
+		if _, err := conn.Write([]byte("ping")); err != nil {
+			return err
+		}
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return err
+		}
+		if string(buf) != "ping" {
+			t.Fatalf("echo mismatch:%s", buf)
+		}
+		return nil
+	}
+
+	cfg, err := NewClientTLSConfig(path("client.crt"), path("client.key"), path("ca.crt"), "localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = dial(cfg); err != nil {
+		t.Fatalf("mutual tls failed:%v", err)
+	}
+
+	// 不出示证书的客户端应被拒绝
+	cfg, err = NewClientTLSConfig("", "", path("ca.crt"), "localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = dial(cfg); err == nil {
+		t.Fatal("client without certificate should be rejected")
+	}
+}
+
+// 连上后不握手的客户端在超时后被断开, 并释放连接数
+func TestTCPServer_TLSHandshakeTimeout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca := issueCert(t, dir, "ca", nil, true)
+	issueCert(t, dir, "server", ca, false)
+
+	server := &TCPServer{
+		Addr:                "127.0.0.1:0",
+		NewAgent:            newTLSEchoAgent,
+		CertFile:            filepath.Join(dir, "server.crt"),
+		KeyFile:             filepath.Join(dir, "server.key"),
+		TLSHandshakeTimeout: 100 * time.Millisecond,
+	}
+	server.Start()
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	if _, err = conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatalf("idle handshake not closed:%v", err)
+	}
+	for i := 0; server.Limiter.connNum() != 0; i++ {
+		if i > 100 {
+			t.Fatalf("conn not released:%d", server.Limiter.connNum())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,294 @@
+package network
+
+import (
+	"bufio"
+	"cloudcadetest/framework/log"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// websocket opcode (RFC 6455)
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// close status code
+const (
+	wsCloseNormal        = 1000
+	wsCloseProtocolError = 1002
+	wsCloseUnsupported   = 1003
+	wsCloseTooBig        = 1009
+)
+
+const wsMaxControlPayload = 125
+
+type WSConnSet map[net.Conn]struct{}
+
+// WSConn 以二进制帧承载CS协议流, 对上层表现为与TCPConn一致的字节流
+type WSConn struct {
+	conn       net.Conn
+	reader     *bufio.Reader
+	writeChan  chan []byte
+	maxMsgLen  uint32
+	isClosed   bool
+	sync.Mutex // 读协程会回写控制帧, 需与Close互斥
+
+	remain    uint32 // 当前帧尚未读取的负载长度
+	maskKey   [4]byte
+	maskPos   int
+	inMessage bool // 是否处于一个分片消息中
+}
+
+func newWSConn(conn net.Conn, reader *bufio.Reader, pendingWriteNum int, maxMsgLen uint32) *WSConn {
+	wsConn := new(WSConn)
+	wsConn.conn = conn
+	wsConn.reader = reader
+	if wsConn.reader == nil {
+		wsConn.reader = bufio.NewReader(conn)
+	}
+	wsConn.writeChan = make(chan []byte, pendingWriteNum)
+	wsConn.maxMsgLen = maxMsgLen
+	return wsConn
+}
+
+func (wsConn *WSConn) Destroy() {
+	e := wsConn.conn.Close()
+	if e != nil {
+		log.Error("close ws conn failed:%s", e.Error())
+	}
+}
+
+func (wsConn *WSConn) Close() {
+	wsConn.Lock()
+	defer wsConn.Unlock()
+
+	if wsConn.isClosed {
+		return
+	}
+	wsConn.doEnqueue(encodeCloseFrame(wsCloseNormal))
+	close(wsConn.writeChan)
+	wsConn.isClosed = true
+}
+
+func (wsConn *WSConn) enqueue(frame []byte) bool {
+	wsConn.Lock()
+	defer wsConn.Unlock()
+
+	if wsConn.isClosed {
+		return false
+	}
+	return wsConn.doEnqueue(frame)
+}
+
+func (wsConn *WSConn) doEnqueue(frame []byte) bool {
+	select {
+	case wsConn.writeChan <- frame:
+		return true
+	default:
+		return false
+	}
+}
+
+// 每次Write对应一个二进制帧
+func (wsConn *WSConn) Write(b []byte) error {
+	if b == nil {
+		return nil
+	}
+
+	wsConn.Lock()
+	defer wsConn.Unlock()
+
+	if wsConn.isClosed {
+		return errors.New("closed write channel")
+	}
+
+	if !wsConn.doEnqueue(encodeFrame(wsOpBinary, b)) {
+		return errors.New("full write channel")
+	}
+
+	return nil
+}
+
+// 读取二进制帧负载, 控制帧在此处理, 对调用方透明
+func (wsConn *WSConn) Read(b []byte) (int, error) {
+	for wsConn.remain == 0 {
+		if err := wsConn.nextFrame(); err != nil {
+			return 0, err
+		}
+	}
+
+	if uint32(len(b)) > wsConn.remain {
+		b = b[:wsConn.remain]
+	}
+	n, err := wsConn.reader.Read(b)
+	if n > 0 {
+		wsConn.unmask(b[:n])
+		wsConn.remain -= uint32(n)
+	}
+	return n, err
+}
+
+func (wsConn *WSConn) ReadFull(b []byte) error {
+	_, err := io.ReadFull(wsConn, b)
+	return err
+}
+
+func (wsConn *WSConn) LocalAddr() net.Addr {
+	return wsConn.conn.LocalAddr()
+}
+
+func (wsConn *WSConn) RemoteAddr() net.Addr {
+	return wsConn.conn.RemoteAddr()
+}
+
+func (wsConn *WSConn) WriteQueueLen() int {
+	return len(wsConn.writeChan)
+}
+
+func (wsConn *WSConn) WriteTask() {
+	for b := range wsConn.writeChan {
+		_, err := wsConn.conn.Write(b)
+		if err != nil {
+			log.Warn("wsconn write fail[%s]", err.Error())
+			continue
+		}
+	}
+
+	wsConn.Destroy()
+}
+
+func (wsConn *WSConn) unmask(b []byte) {
+	for i := range b {
+		b[i] ^= wsConn.maskKey[wsConn.maskPos&3]
+		wsConn.maskPos++
+	}
+}
+
+func (wsConn *WSConn) protocolError(code uint16, format string, args ...interface{}) error {
+	wsConn.enqueue(encodeCloseFrame(code))
+	return fmt.Errorf(format, args...)
+}
+
+// 解析下一个帧头, 数据帧的负载留给Read
+func (wsConn *WSConn) nextFrame() error {
+	var h [2]byte
+	if _, err := io.ReadFull(wsConn.reader, h[:]); err != nil {
+		return err
+	}
+
+	fin := h[0]&0x80 != 0
+	opcode := h[0] & 0x0F
+	if h[0]&0x70 != 0 {
+		return wsConn.protocolError(wsCloseProtocolError, "ws reserved bits set")
+	}
+	if h[1]&0x80 == 0 {
+		return wsConn.protocolError(wsCloseProtocolError, "ws client frame not masked")
+	}
+
+	var length uint64
+	switch l := h[1] & 0x7F; l {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(wsConn.reader, ext[:]); err != nil {
+			return err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(wsConn.reader, ext[:]); err != nil {
+			return err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	default:
+		length = uint64(l)
+	}
+
+	if _, err := io.ReadFull(wsConn.reader, wsConn.maskKey[:]); err != nil {
+		return err
+	}
+	wsConn.maskPos = 0
+
+	switch opcode {
+	case wsOpClose, wsOpPing, wsOpPong:
+		if !fin || length > wsMaxControlPayload {
+			return wsConn.protocolError(wsCloseProtocolError, "ws invalid control frame")
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(wsConn.reader, payload); err != nil {
+			return err
+		}
+		wsConn.unmask(payload)
+		return wsConn.onControl(opcode, payload)
+
+	case wsOpBinary:
+		if wsConn.inMessage {
+			return wsConn.protocolError(wsCloseProtocolError, "ws unexpected new message")
+		}
+	case wsOpContinuation:
+		if !wsConn.inMessage {
+			return wsConn.protocolError(wsCloseProtocolError, "ws unexpected continuation")
+		}
+	case wsOpText:
+		return wsConn.protocolError(wsCloseUnsupported, "ws text frame not supported")
+	default:
+		return wsConn.protocolError(wsCloseProtocolError, "ws unknown opcode %d", opcode)
+	}
+
+	if wsConn.maxMsgLen > 0 && length > uint64(wsConn.maxMsgLen) {
+		return wsConn.protocolError(wsCloseTooBig, "ws frame too long %d", length)
+	}
+
+	wsConn.inMessage = !fin
+	wsConn.remain = uint32(length)
+	return nil
+}
+
+func (wsConn *WSConn) onControl(opcode byte, payload []byte) error {
+	switch opcode {
+	case wsOpPing:
+		wsConn.enqueue(encodeFrame(wsOpPong, payload))
+	case wsOpClose:
+		return io.EOF
+	}
+	return nil
+}
+
+// 服务端发出的帧不带掩码
+func encodeFrame(opcode byte, payload []byte) []byte {
+	var (
+		l     = len(payload)
+		frame []byte
+	)
+
+	switch {
+	case l <= 125:
+		frame = make([]byte, 2, 2+l)
+		frame[1] = byte(l)
+	case l <= 0xFFFF:
+		frame = make([]byte, 4, 4+l)
+		frame[1] = 126
+		binary.BigEndian.PutUint16(frame[2:], uint16(l))
+	default:
+		frame = make([]byte, 10, 10+l)
+		frame[1] = 127
+		binary.BigEndian.PutUint64(frame[2:], uint64(l))
+	}
+	frame[0] = 0x80 | opcode
+
+	return append(frame, payload...)
+}
+
+func encodeCloseFrame(code uint16) []byte {
+	var payload [2]byte
+	binary.BigEndian.PutUint16(payload[:], code)
+	return encodeFrame(wsOpClose, payload[:])
+}
@@ -0,0 +1,208 @@
+package network
+
+import (
+	"bufio"
+	"bytes"
+	"cloudcadetest/framework/agent"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type echoAgent struct {
+	conn IConn
+}
+
+func (a *echoAgent) OnClose(code uint) {}
+
+func (a *echoAgent) Addr() string {
+	return a.conn.RemoteAddr().String()
+}
+
+// 按4字节长度前缀读取数据后原样写回
+func newEchoAgent(conn *WSConn) agent.Agent {
+	a := &echoAgent{conn: conn}
+	go conn.WriteTask()
+	for {
+		var l [4]byte
+		if err := conn.ReadFull(l[:]); err != nil {
+			break
+		}
+		body := make([]byte, binary.BigEndian.Uint32(l[:]))
+		if err := conn.ReadFull(body); err != nil {
+			break
+		}
+		if err := conn.Write(append(l[:], body...)); err != nil {
+			break
+		}
+	}
+	conn.Close()
+	return a
+}
+
+func newTestWSServer() *httptest.Server {
+	return httptest.NewServer(&WSHandler{
+		limiter:         NewAcceptLimiter(nil, nil, 0),
+		pendingWriteNum: 10,
+		maxMsgLen:       1024,
+		newAgent:        newEchoAgent,
+		conns:           make(WSConnSet),
+	})
+}
+
+func dialWS(t *testing.T, url string) (net.Conn, *bufio.Reader) {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(url, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\nSec-WebSocket-Version: 13\r\n\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := bufio.NewReader(conn)
+	rsp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake status %d", rsp.StatusCode)
+	}
+	if accept := rsp.Header.Get("Sec-WebSocket-Accept"); accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("invalid accept key %s", accept)
+	}
+	return conn, r
+}
+
+func writeClientFrame(t *testing.T, conn net.Conn, fin bool, opcode byte, payload []byte) {
+	var b0 byte = opcode
+	if fin {
+		b0 |= 0x80
+	}
+	frame := []byte{b0}
+	switch l := len(payload); {
+	case l <= 125:
+		frame = append(frame, 0x80|byte(l))
+	default:
+		frame = append(frame, 0x80|126, byte(l>>8), byte(l))
+	}
+	mask := []byte{1, 2, 3, 4}
+	frame = append(frame, mask...)
+	for i, c := range payload {
+		frame = append(frame, c^mask[i&3])
+	}
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readServerFrame(t *testing.T, r *bufio.Reader) (byte, []byte) {
+	var h [2]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		t.Fatal(err)
+	}
+	if h[1]&0x80 != 0 {
+		t.Fatal("server frame must not be masked")
+	}
+	l := int(h[1] & 0x7F)
+	if l == 126 {
+		var ext [2]byte
+		io.ReadFull(r, ext[:])
+		l = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, l)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		t.Fatal(err)
+	}
+	return h[0] & 0x0F, payload
+}
+
+func TestWSConn_Echo(t *testing.T) {
+	s := newTestWSServer()
+	defer s.Close()
+
+	conn, r := dialWS(t, s.URL)
+	defer conn.Close()
+
+	msg := append([]byte{0, 0, 1, 44}, bytes.Repeat([]byte("x"), 300)...)
+
+	// 一条消息拆成多个分片, 中间插入ping
+	writeClientFrame(t, conn, false, wsOpBinary, msg[:3])
+	writeClientFrame(t, conn, true, wsOpPing, []byte("hi"))
+	writeClientFrame(t, conn, false, wsOpContinuation, msg[3:200])
+	writeClientFrame(t, conn, true, wsOpContinuation, msg[200:])
+
+	op, payload := readServerFrame(t, r)
+	if op != wsOpPong || string(payload) != "hi" {
+		t.Fatalf("expect pong, got op:%d payload:%s", op, payload)
+	}
+
+	op, payload = readServerFrame(t, r)
+	if op != wsOpBinary || !bytes.Equal(payload, msg) {
+		t.Fatalf("echo mismatch op:%d len:%d", op, len(payload))
+	}
+}
+
+func TestWSConn_RejectText(t *testing.T) {
+	s := newTestWSServer()
+	defer s.Close()
+
+	conn, r := dialWS(t, s.URL)
+	defer conn.Close()
+
+	writeClientFrame(t, conn, true, wsOpText, []byte("hello"))
+
+	op, payload := readServerFrame(t, r)
+	if op != wsOpClose || binary.BigEndian.Uint16(payload) != wsCloseUnsupported {
+		t.Fatalf("expect close 1003, got op:%d payload:%v", op, payload)
+	}
+}
+
+func TestWSHandler_BadHandshake(t *testing.T) {
+	s := newTestWSServer()
+	defer s.Close()
+
+	rsp, err := http.Get(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expect 400, got %d", rsp.StatusCode)
+	}
+}
+
+// WS与TCP共用接受连接的限制, 在升级前拒绝
+func TestWSHandler_Limit(t *testing.T) {
+	s := httptest.NewServer(&WSHandler{
+		limiter:         NewAcceptLimiter(func(ip string) bool { return ip != "127.0.0.1" }, nil, 0),
+		pendingWriteNum: 10,
+		maxMsgLen:       1024,
+		newAgent:        newEchoAgent,
+		conns:           make(WSConnSet),
+	})
+	defer s.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, s.URL, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	rsp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expect 403, got %d", rsp.StatusCode)
+	}
+}
@@ -0,0 +1,248 @@
+package network
+
+import (
+	"cloudcadetest/framework/agent"
+	"cloudcadetest/framework/log"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+type WSServer struct {
+	Addr            string
+	FuncMaxConnNum  func() int
+	PendingWriteNum int
+	MaxMsgLen       uint32
+	HTTPTimeout     time.Duration
+	NewAgent        func(*WSConn) agent.Agent
+	FuncAllowIP     func(ip string) bool // 同TCPServer, 在升级前检查
+	ln              net.Listener
+	lnOnce          sync.Once
+	handler         *WSHandler
+
+	// wss, 规则同TCPServer
+	TLSConfig    *tls.Config
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+
+	ConnNumberPerSecond int32 // 同TCPServer
+
+	// 同TCPServer, 与TCPServer传入同一个时共用计数
+	Limiter *AcceptLimiter
+}
+
+type WSHandler struct {
+	limiter         *AcceptLimiter
+	pendingWriteNum int
+	maxMsgLen       uint32
+	newAgent        func(*WSConn) agent.Agent
+	conns           WSConnSet
+	mutexConns      sync.Mutex
+	wg              sync.WaitGroup
+}
+
+func (handler *WSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Upgrade Required", http.StatusUpgradeRequired)
+		return
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if k, e := base64.StdEncoding.DecodeString(key); e != nil || len(k) != 16 {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	switch reason := handler.limiter.admit(r.RemoteAddr, time.Now()); reason {
+	case "":
+	case rejectBanned:
+		wsRejected.With(reason).Inc()
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		log.Release("reject banned ws connection %s", r.RemoteAddr)
+		return
+	default:
+		wsRejected.With(reason).Inc()
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+		log.Release("reject ws connection %s:%s", r.RemoteAddr, reason)
+		return
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	conn, brw, err := hj.Hijack()
+	if err != nil {
+		log.Warn("ws hijack failed:%s", err.Error())
+		return
+	}
+
+	handler.mutexConns.Lock()
+	if handler.conns == nil {
+		handler.mutexConns.Unlock()
+		closeConn(conn)
+		return
+	}
+	if !handler.limiter.acquire() {
+		handler.mutexConns.Unlock()
+		closeConn(conn)
+		wsRejected.With(rejectMaxConn).Inc()
+		log.Release("too many ws connections %d", handler.limiter.maxConnNum())
+		return
+	}
+	handler.conns[conn] = struct{}{}
+	handler.wg.Add(1)
+	handler.mutexConns.Unlock()
+	defer handler.wg.Done()
+
+	// 握手应答
+	_ = conn.SetDeadline(time.Time{})
+	_, err = conn.Write([]byte("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + wsAcceptKey(key) + "\r\n\r\n"))
+	if err != nil {
+		log.Warn("ws handshake failed:%s", err.Error())
+		handler.removeConn(conn)
+		closeConn(conn)
+		return
+	}
+
+	wsConn := newWSConn(conn, brw.Reader, handler.pendingWriteNum, handler.maxMsgLen)
+	handler.newAgent(wsConn)
+
+	// cleanup
+	handler.removeConn(conn)
+}
+
+// 登记的连接结束时调用
+func (handler *WSHandler) removeConn(conn net.Conn) {
+	handler.mutexConns.Lock()
+	delete(handler.conns, conn)
+	handler.mutexConns.Unlock()
+	handler.limiter.release()
+}
+
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h[http.CanonicalHeaderKey(name)] {
+		for _, s := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(s), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func wsAcceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + wsAcceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func (server *WSServer) Start() {
+	err := server.init()
+	if err != nil {
+		log.Fatal("wsserver init fail:%s", err.Error())
+		return
+	}
+
+	httpServer := &http.Server{
+		Addr:           server.Addr,
+		Handler:        server.handler,
+		ReadTimeout:    server.HTTPTimeout,
+		WriteTimeout:   server.HTTPTimeout,
+		MaxHeaderBytes: 1024,
+	}
+
+	go httpServer.Serve(server.ln)
+}
+
+func (server *WSServer) init() error {
+	ln, err := listen(server.Addr)
+	if err != nil {
+		return err
+	}
+
+	if server.TLSConfig == nil && server.CertFile != "" {
+		server.TLSConfig, err = NewServerTLSConfig(server.CertFile, server.KeyFile, server.ClientCAFile)
+		if err != nil {
+			closeListener(ln)
+			return err
+		}
+	}
+	if server.TLSConfig != nil {
+		ln = tls.NewListener(ln, server.TLSConfig)
+		log.Release("listen wss[%v]", server.Addr)
+	} else {
+		log.Release("listen ws[%v]", server.Addr)
+	}
+
+	if server.PendingWriteNum <= 0 {
+		server.PendingWriteNum = 100
+	}
+	if server.MaxMsgLen <= 0 {
+		server.MaxMsgLen = 4096
+	}
+	if server.HTTPTimeout <= 0 {
+		server.HTTPTimeout = 10 * time.Second
+	}
+	if server.NewAgent == nil {
+		return errors.New("NewAgent must not be nil")
+	}
+
+	if server.Limiter == nil {
+		server.Limiter = NewAcceptLimiter(server.FuncAllowIP, server.FuncMaxConnNum, server.ConnNumberPerSecond)
+	}
+
+	server.ln = ln
+	server.handler = &WSHandler{
+		limiter:         server.Limiter,
+		pendingWriteNum: server.PendingWriteNum,
+		maxMsgLen:       server.MaxMsgLen,
+		newAgent:        server.NewAgent,
+		conns:           make(WSConnSet),
+	}
+	return nil
+}
+
+// 停止接受新连接, 已有连接不受影响
+func (server *WSServer) StopAccept() {
+	server.lnOnce.Do(func() {
+		unregisterListener(server.Addr)
+		e := server.ln.Close()
+		if e != nil {
+			log.Error("close ws listener failed:%s", e.Error())
+		}
+	})
+}
+
+func (server *WSServer) Close() {
+	server.StopAccept()
+
+	server.handler.mutexConns.Lock()
+	for conn := range server.handler.conns {
+		closeConn(conn)
+	}
+	server.handler.conns = nil
+	server.handler.mutexConns.Unlock()
+
+	server.handler.wg.Wait()
+}
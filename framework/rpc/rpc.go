@@ -2,9 +2,17 @@ package rpc
 
 import (
 	"cloudcadetest/framework/log"
+	"cloudcadetest/framework/metrics"
 	"errors"
 	"fmt"
 	"runtime"
+	"time"
+)
+
+var (
+	rpcChanCallLen  = metrics.NewGaugeFuncVec("cc_rpc_chan_call_len", "Calls waiting in rpc.Server ChanCall.", "server")
+	rpcCallDropped  = metrics.NewCounterVec("cc_rpc_chan_call_dropped_total", "Calls dropped because ChanCall was full.", "server")
+	rpcCallDuration = metrics.NewHistogramVec("cc_rpc_call_duration_seconds", "Time spent executing a call in rpc.Server.", nil, "server", "id")
 )
 
 // one server per goroutine (goroutine not safe)
@@ -18,6 +26,9 @@ type Server struct {
 	// func(args []interface{}) []interface{}
 	functions          map[interface{}]interface{}
 	ChanCall           chan *CallInfo
+
+	name    string                             // 为空时不导出指标
+	latency map[interface{}]*metrics.Histogram // 只在执行协程中访问
 }
 
 type CallInfo struct {
@@ -59,6 +70,25 @@ func NewServer(l int) *Server {
 	return s
 }
 
+// 设置名称并导出该Server的指标
+func (s *Server) SetName(name string) *Server {
+	s.name = name
+	s.latency = make(map[interface{}]*metrics.Histogram)
+	rpcChanCallLen.Set(func() float64 {
+		return float64(len(s.ChanCall))
+	}, name)
+	return s
+}
+
+func (s *Server) observe(id interface{}, start time.Time) {
+	h, ok := s.latency[id]
+	if !ok {
+		h = rpcCallDuration.With(s.name, fmt.Sprint(id))
+		s.latency[id] = h
+	}
+	h.Since(start)
+}
+
 // call Register before calling Open and Go
 func (s *Server) Register(id interface{}, f interface{}) {
 	switch f.(type) {
@@ -104,6 +134,10 @@ func doRecover(r interface{}) {
 }
 
 func (s *Server) Exec(ci *CallInfo) (err error) {
+	if s.name != "" {
+		defer s.observe(ci.id, time.Now())
+	}
+
 	defer func() {
 		if r := recover(); r != nil {
 			doRecover(r)
@@ -144,6 +178,9 @@ func (s *Server) AddChanCall(callInfo *CallInfo) {
 	case s.ChanCall <- callInfo:
 		log.Debug("callinfo:%v", callInfo.id)
 	default:
+		if s.name != "" {
+			rpcCallDropped.With(s.name).Inc()
+		}
 		log.Error("RPC ChanCall is full")
 	}
 }
@@ -8,4 +8,5 @@ type ServerConf struct {
 	LogChanNum   int    `json:"log_chan_num"`
 	RollSize     uint32 `json:"roll_size"` // MB
 	EnableStdOut bool   `json:"enable_std_out"`
+	DrainTimeout int    `json:"drain_timeout"` // 秒, 收到退出信号后整个关闭流程的时限
 }
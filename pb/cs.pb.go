@@ -23,18 +23,48 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 type ERROR_CODE int32
 
 const (
-	ERROR_CODE_SUCCESS ERROR_CODE = 0
-	ERROR_CODE_FAILED  ERROR_CODE = 1
+	ERROR_CODE_SUCCESS          ERROR_CODE = 0
+	ERROR_CODE_FAILED           ERROR_CODE = 1
+	ERROR_CODE_RATE_LIMITED     ERROR_CODE = 2
+	ERROR_CODE_BANNED           ERROR_CODE = 3
+	ERROR_CODE_NO_PERMISSION    ERROR_CODE = 4
+	ERROR_CODE_INVALID_NAME     ERROR_CODE = 5
+	ERROR_CODE_NAME_TAKEN       ERROR_CODE = 6
+	ERROR_CODE_AUTH_REQUIRED    ERROR_CODE = 7
+	ERROR_CODE_AUTH_FAILED      ERROR_CODE = 8
+	ERROR_CODE_TOKEN_EXPIRED    ERROR_CODE = 9
+	ERROR_CODE_ACCOUNT_LOCKED   ERROR_CODE = 10
+	ERROR_CODE_CONTENT_REJECTED ERROR_CODE = 11
 )
 
 var ERROR_CODE_name = map[int32]string{
-	0: "SUCCESS",
-	1: "FAILED",
+	0:  "SUCCESS",
+	1:  "FAILED",
+	2:  "RATE_LIMITED",
+	3:  "BANNED",
+	4:  "NO_PERMISSION",
+	5:  "INVALID_NAME",
+	6:  "NAME_TAKEN",
+	7:  "AUTH_REQUIRED",
+	8:  "AUTH_FAILED",
+	9:  "TOKEN_EXPIRED",
+	10: "ACCOUNT_LOCKED",
+	11: "CONTENT_REJECTED",
 }
 
 var ERROR_CODE_value = map[string]int32{
-	"SUCCESS": 0,
-	"FAILED":  1,
+	"SUCCESS":          0,
+	"FAILED":           1,
+	"RATE_LIMITED":     2,
+	"BANNED":           3,
+	"NO_PERMISSION":    4,
+	"INVALID_NAME":     5,
+	"NAME_TAKEN":       6,
+	"AUTH_REQUIRED":    7,
+	"AUTH_FAILED":      8,
+	"TOKEN_EXPIRED":    9,
+	"ACCOUNT_LOCKED":   10,
+	"CONTENT_REJECTED": 11,
 }
 
 func (x ERROR_CODE) String() string {
@@ -45,31 +75,165 @@ func (ERROR_CODE) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_af7bf51985781725, []int{0}
 }
 
+type KICK_REASON int32
+
+const (
+	KICK_REASON_KICK_UNKNOWN         KICK_REASON = 0
+	KICK_REASON_KICK_SERVER_SHUTDOWN KICK_REASON = 1
+	KICK_REASON_KICK_BY_ADMIN        KICK_REASON = 2
+	KICK_REASON_KICK_IDLE            KICK_REASON = 3
+	KICK_REASON_KICK_FLOOD           KICK_REASON = 4
+	KICK_REASON_KICK_BANNED          KICK_REASON = 5
+)
+
+var KICK_REASON_name = map[int32]string{
+	0: "KICK_UNKNOWN",
+	1: "KICK_SERVER_SHUTDOWN",
+	2: "KICK_BY_ADMIN",
+	3: "KICK_IDLE",
+	4: "KICK_FLOOD",
+	5: "KICK_BANNED",
+}
+
+var KICK_REASON_value = map[string]int32{
+	"KICK_UNKNOWN":         0,
+	"KICK_SERVER_SHUTDOWN": 1,
+	"KICK_BY_ADMIN":        2,
+	"KICK_IDLE":            3,
+	"KICK_FLOOD":           4,
+	"KICK_BANNED":          5,
+}
+
+func (x KICK_REASON) String() string {
+	return proto.EnumName(KICK_REASON_name, int32(x))
+}
+
+func (KICK_REASON) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{1}
+}
+
+// 房间内的角色, 由低到高, 只能管理角色低于自己的成员
+type ROOM_ROLE int32
+
+const (
+	ROOM_ROLE_ROLE_MEMBER    ROOM_ROLE = 0
+	ROOM_ROLE_ROLE_MODERATOR ROOM_ROLE = 1
+	ROOM_ROLE_ROLE_OWNER     ROOM_ROLE = 2
+	ROOM_ROLE_ROLE_ADMIN     ROOM_ROLE = 3
+)
+
+var ROOM_ROLE_name = map[int32]string{
+	0: "ROLE_MEMBER",
+	1: "ROLE_MODERATOR",
+	2: "ROLE_OWNER",
+	3: "ROLE_ADMIN",
+}
+
+var ROOM_ROLE_value = map[string]int32{
+	"ROLE_MEMBER":    0,
+	"ROLE_MODERATOR": 1,
+	"ROLE_OWNER":     2,
+	"ROLE_ADMIN":     3,
+}
+
+func (x ROOM_ROLE) String() string {
+	return proto.EnumName(ROOM_ROLE_name, int32(x))
+}
+
+func (ROOM_ROLE) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{2}
+}
+
+// 与common/compress中的ID一致
+type COMPRESS_CODEC int32
+
+const (
+	COMPRESS_CODEC_COMPRESS_NONE       COMPRESS_CODEC = 0
+	COMPRESS_CODEC_COMPRESS_ZLIB       COMPRESS_CODEC = 1
+	COMPRESS_CODEC_COMPRESS_FLATE      COMPRESS_CODEC = 2
+	COMPRESS_CODEC_COMPRESS_GZIP       COMPRESS_CODEC = 3
+	COMPRESS_CODEC_COMPRESS_FLATE_DICT COMPRESS_CODEC = 4
+)
+
+var COMPRESS_CODEC_name = map[int32]string{
+	0: "COMPRESS_NONE",
+	1: "COMPRESS_ZLIB",
+	2: "COMPRESS_FLATE",
+	3: "COMPRESS_GZIP",
+	4: "COMPRESS_FLATE_DICT",
+}
+
+var COMPRESS_CODEC_value = map[string]int32{
+	"COMPRESS_NONE":       0,
+	"COMPRESS_ZLIB":       1,
+	"COMPRESS_FLATE":      2,
+	"COMPRESS_GZIP":       3,
+	"COMPRESS_FLATE_DICT": 4,
+}
+
+func (x COMPRESS_CODEC) String() string {
+	return proto.EnumName(COMPRESS_CODEC_name, int32(x))
+}
+
+func (COMPRESS_CODEC) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{3}
+}
+
 type CSMsgID int32
 
 const (
-	CSMsgID_REQ_BEGIN              CSMsgID = 0
-	CSMsgID_REQ_LOGIN              CSMsgID = 1
-	CSMsgID_REQ_HEARTBEAT          CSMsgID = 2
-	CSMsgID_REQ_SET_USERNAME       CSMsgID = 3
-	CSMsgID_REQ_ROOM_CHAT          CSMsgID = 4
-	CSMsgID_REQ_ROOM_LIST          CSMsgID = 5
-	CSMsgID_REQ_JOIN_ROOM          CSMsgID = 6
-	CSMsgID_REQ_CHAT               CSMsgID = 7
-	CSMsgID_RSP_BEGIN              CSMsgID = 100
-	CSMsgID_RSP_LOGIN              CSMsgID = 101
-	CSMsgID_RSP_HEARTBEAT          CSMsgID = 102
-	CSMsgID_RSP_SET_USERNAME       CSMsgID = 103
-	CSMsgID_RSP_ROOM_CHAT          CSMsgID = 104
-	CSMsgID_RSP_ROOM_LIST          CSMsgID = 105
-	CSMsgID_RSP_JOIN_ROOM          CSMsgID = 106
-	CSMsgID_RSP_CHAT               CSMsgID = 107
-	CSMsgID_NTF_BEGIN              CSMsgID = 200
-	CSMsgID_NTF_ROOM_MEMBER_ONLINE CSMsgID = 201
-	CSMsgID_NTF_ROOM_CHAT          CSMsgID = 202
-	CSMsgID_NTF_ROOM_CLOSED        CSMsgID = 203
-	CSMsgID_NTF_HISTROY_MSG        CSMsgID = 204
-	CSMsgID_NTF_CHAT               CSMsgID = 205
+	CSMsgID_REQ_BEGIN               CSMsgID = 0
+	CSMsgID_REQ_LOGIN               CSMsgID = 1
+	CSMsgID_REQ_HEARTBEAT           CSMsgID = 2
+	CSMsgID_REQ_SET_USERNAME        CSMsgID = 3
+	CSMsgID_REQ_ROOM_CHAT           CSMsgID = 4
+	CSMsgID_REQ_ROOM_LIST           CSMsgID = 5
+	CSMsgID_REQ_JOIN_ROOM           CSMsgID = 6
+	CSMsgID_REQ_CHAT                CSMsgID = 7
+	CSMsgID_REQ_HANDSHAKE           CSMsgID = 8
+	CSMsgID_REQ_LEAVE_ROOM          CSMsgID = 9
+	CSMsgID_REQ_ROOM_MEMBERS        CSMsgID = 10
+	CSMsgID_REQ_ROOM_HISTORY        CSMsgID = 11
+	CSMsgID_REQ_SEARCH_HISTORY      CSMsgID = 12
+	CSMsgID_REQ_INBOX_READ          CSMsgID = 13
+	CSMsgID_REQ_SET_ROLE            CSMsgID = 14
+	CSMsgID_REQ_ROOM_MUTE           CSMsgID = 15
+	CSMsgID_REQ_ROOM_KICK           CSMsgID = 16
+	CSMsgID_REQ_BAN                 CSMsgID = 17
+	CSMsgID_RSP_BEGIN               CSMsgID = 100
+	CSMsgID_RSP_LOGIN               CSMsgID = 101
+	CSMsgID_RSP_HEARTBEAT           CSMsgID = 102
+	CSMsgID_RSP_SET_USERNAME        CSMsgID = 103
+	CSMsgID_RSP_ROOM_CHAT           CSMsgID = 104
+	CSMsgID_RSP_ROOM_LIST           CSMsgID = 105
+	CSMsgID_RSP_JOIN_ROOM           CSMsgID = 106
+	CSMsgID_RSP_CHAT                CSMsgID = 107
+	CSMsgID_RSP_HANDSHAKE           CSMsgID = 108
+	CSMsgID_RSP_LEAVE_ROOM          CSMsgID = 109
+	CSMsgID_RSP_ROOM_MEMBERS        CSMsgID = 110
+	CSMsgID_RSP_ROOM_HISTORY        CSMsgID = 111
+	CSMsgID_RSP_SEARCH_HISTORY      CSMsgID = 112
+	CSMsgID_RSP_INBOX_READ          CSMsgID = 113
+	CSMsgID_RSP_SET_ROLE            CSMsgID = 114
+	CSMsgID_RSP_ROOM_MUTE           CSMsgID = 115
+	CSMsgID_RSP_ROOM_KICK           CSMsgID = 116
+	CSMsgID_RSP_BAN                 CSMsgID = 117
+	CSMsgID_NTF_BEGIN               CSMsgID = 200
+	CSMsgID_NTF_ROOM_MEMBER_ONLINE  CSMsgID = 201
+	CSMsgID_NTF_ROOM_CHAT           CSMsgID = 202
+	CSMsgID_NTF_ROOM_CLOSED         CSMsgID = 203
+	CSMsgID_NTF_HISTROY_MSG         CSMsgID = 204
+	CSMsgID_NTF_CHAT                CSMsgID = 205
+	CSMsgID_NTF_KICK                CSMsgID = 206
+	CSMsgID_NTF_ROOM_MEMBER_LEAVE   CSMsgID = 207
+	CSMsgID_NTF_ROOM_MEMBER_OFFLINE CSMsgID = 208
+	CSMsgID_NTF_ROOM_PRESENCE       CSMsgID = 209
+	CSMsgID_NTF_RESYNC              CSMsgID = 210
+	CSMsgID_NTF_INBOX               CSMsgID = 211
+	CSMsgID_NTF_ROOM_ROLE           CSMsgID = 212
+	CSMsgID_NTF_ROOM_MUTE           CSMsgID = 213
+	CSMsgID_NTF_ROOM_KICKED         CSMsgID = 214
+	CSMsgID_NTF_RENAME              CSMsgID = 215
 )
 
 var CSMsgID_name = map[int32]string{
@@ -81,6 +245,16 @@ var CSMsgID_name = map[int32]string{
 	5:   "REQ_ROOM_LIST",
 	6:   "REQ_JOIN_ROOM",
 	7:   "REQ_CHAT",
+	8:   "REQ_HANDSHAKE",
+	9:   "REQ_LEAVE_ROOM",
+	10:  "REQ_ROOM_MEMBERS",
+	11:  "REQ_ROOM_HISTORY",
+	12:  "REQ_SEARCH_HISTORY",
+	13:  "REQ_INBOX_READ",
+	14:  "REQ_SET_ROLE",
+	15:  "REQ_ROOM_MUTE",
+	16:  "REQ_ROOM_KICK",
+	17:  "REQ_BAN",
 	100: "RSP_BEGIN",
 	101: "RSP_LOGIN",
 	102: "RSP_HEARTBEAT",
@@ -89,37 +263,87 @@ var CSMsgID_name = map[int32]string{
 	105: "RSP_ROOM_LIST",
 	106: "RSP_JOIN_ROOM",
 	107: "RSP_CHAT",
+	108: "RSP_HANDSHAKE",
+	109: "RSP_LEAVE_ROOM",
+	110: "RSP_ROOM_MEMBERS",
+	111: "RSP_ROOM_HISTORY",
+	112: "RSP_SEARCH_HISTORY",
+	113: "RSP_INBOX_READ",
+	114: "RSP_SET_ROLE",
+	115: "RSP_ROOM_MUTE",
+	116: "RSP_ROOM_KICK",
+	117: "RSP_BAN",
 	200: "NTF_BEGIN",
 	201: "NTF_ROOM_MEMBER_ONLINE",
 	202: "NTF_ROOM_CHAT",
 	203: "NTF_ROOM_CLOSED",
 	204: "NTF_HISTROY_MSG",
 	205: "NTF_CHAT",
+	206: "NTF_KICK",
+	207: "NTF_ROOM_MEMBER_LEAVE",
+	208: "NTF_ROOM_MEMBER_OFFLINE",
+	209: "NTF_ROOM_PRESENCE",
+	210: "NTF_RESYNC",
+	211: "NTF_INBOX",
+	212: "NTF_ROOM_ROLE",
+	213: "NTF_ROOM_MUTE",
+	214: "NTF_ROOM_KICKED",
+	215: "NTF_RENAME",
 }
 
 var CSMsgID_value = map[string]int32{
-	"REQ_BEGIN":              0,
-	"REQ_LOGIN":              1,
-	"REQ_HEARTBEAT":          2,
-	"REQ_SET_USERNAME":       3,
-	"REQ_ROOM_CHAT":          4,
-	"REQ_ROOM_LIST":          5,
-	"REQ_JOIN_ROOM":          6,
-	"REQ_CHAT":               7,
-	"RSP_BEGIN":              100,
-	"RSP_LOGIN":              101,
-	"RSP_HEARTBEAT":          102,
-	"RSP_SET_USERNAME":       103,
-	"RSP_ROOM_CHAT":          104,
-	"RSP_ROOM_LIST":          105,
-	"RSP_JOIN_ROOM":          106,
-	"RSP_CHAT":               107,
-	"NTF_BEGIN":              200,
-	"NTF_ROOM_MEMBER_ONLINE": 201,
-	"NTF_ROOM_CHAT":          202,
-	"NTF_ROOM_CLOSED":        203,
-	"NTF_HISTROY_MSG":        204,
-	"NTF_CHAT":               205,
+	"REQ_BEGIN":               0,
+	"REQ_LOGIN":               1,
+	"REQ_HEARTBEAT":           2,
+	"REQ_SET_USERNAME":        3,
+	"REQ_ROOM_CHAT":           4,
+	"REQ_ROOM_LIST":           5,
+	"REQ_JOIN_ROOM":           6,
+	"REQ_CHAT":                7,
+	"REQ_HANDSHAKE":           8,
+	"REQ_LEAVE_ROOM":          9,
+	"REQ_ROOM_MEMBERS":        10,
+	"REQ_ROOM_HISTORY":        11,
+	"REQ_SEARCH_HISTORY":      12,
+	"REQ_INBOX_READ":          13,
+	"REQ_SET_ROLE":            14,
+	"REQ_ROOM_MUTE":           15,
+	"REQ_ROOM_KICK":           16,
+	"REQ_BAN":                 17,
+	"RSP_BEGIN":               100,
+	"RSP_LOGIN":               101,
+	"RSP_HEARTBEAT":           102,
+	"RSP_SET_USERNAME":        103,
+	"RSP_ROOM_CHAT":           104,
+	"RSP_ROOM_LIST":           105,
+	"RSP_JOIN_ROOM":           106,
+	"RSP_CHAT":                107,
+	"RSP_HANDSHAKE":           108,
+	"RSP_LEAVE_ROOM":          109,
+	"RSP_ROOM_MEMBERS":        110,
+	"RSP_ROOM_HISTORY":        111,
+	"RSP_SEARCH_HISTORY":      112,
+	"RSP_INBOX_READ":          113,
+	"RSP_SET_ROLE":            114,
+	"RSP_ROOM_MUTE":           115,
+	"RSP_ROOM_KICK":           116,
+	"RSP_BAN":                 117,
+	"NTF_BEGIN":               200,
+	"NTF_ROOM_MEMBER_ONLINE":  201,
+	"NTF_ROOM_CHAT":           202,
+	"NTF_ROOM_CLOSED":         203,
+	"NTF_HISTROY_MSG":         204,
+	"NTF_CHAT":                205,
+	"NTF_KICK":                206,
+	"NTF_ROOM_MEMBER_LEAVE":   207,
+	"NTF_ROOM_MEMBER_OFFLINE": 208,
+	"NTF_ROOM_PRESENCE":       209,
+	"NTF_RESYNC":              210,
+	"NTF_INBOX":               211,
+	"NTF_ROOM_ROLE":           212,
+	"NTF_ROOM_MUTE":           213,
+	"NTF_ROOM_KICKED":         214,
+	"NTF_RENAME":              215,
 }
 
 func (x CSMsgID) String() string {
@@ -127,16 +351,18 @@ func (x CSMsgID) String() string {
 }
 
 func (CSMsgID) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_af7bf51985781725, []int{1}
+	return fileDescriptor_af7bf51985781725, []int{4}
 }
 
 type CSHead struct {
-	MsgID                CSMsgID  `protobuf:"varint,1,opt,name=MsgID,proto3,enum=pb.CSMsgID" json:"MsgID,omitempty"`
-	BodyLen              int32    `protobuf:"varint,2,opt,name=BodyLen,proto3" json:"BodyLen,omitempty"`
-	IsCompressed         bool     `protobuf:"varint,3,opt,name=IsCompressed,proto3" json:"IsCompressed,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	MsgID                CSMsgID        `protobuf:"varint,1,opt,name=MsgID,proto3,enum=pb.CSMsgID" json:"MsgID,omitempty"`
+	BodyLen              int32          `protobuf:"varint,2,opt,name=BodyLen,proto3" json:"BodyLen,omitempty"`
+	IsCompressed         bool           `protobuf:"varint,3,opt,name=IsCompressed,proto3" json:"IsCompressed,omitempty"`
+	Codec                COMPRESS_CODEC `protobuf:"varint,4,opt,name=Codec,proto3,enum=pb.COMPRESS_CODEC" json:"Codec,omitempty"`
+	Seq                  int64          `protobuf:"varint,5,opt,name=Seq,proto3" json:"Seq,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *CSHead) Reset()         { *m = CSHead{} }
@@ -185,18 +411,42 @@ func (m *CSHead) GetIsCompressed() bool {
 	return false
 }
 
+func (m *CSHead) GetCodec() COMPRESS_CODEC {
+	if m != nil {
+		return m.Codec
+	}
+	return COMPRESS_CODEC_COMPRESS_NONE
+}
+
+func (m *CSHead) GetSeq() int64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
 type CSReqBody struct {
-	Seq                  int64             `protobuf:"varint,1,opt,name=Seq,proto3" json:"Seq,omitempty"`
-	Login                *CSReqLogin       `protobuf:"bytes,2,opt,name=Login,proto3" json:"Login,omitempty"`
-	Heartbeat            *CSReqHeartbeat   `protobuf:"bytes,3,opt,name=Heartbeat,proto3" json:"Heartbeat,omitempty"`
-	SetUsername          *CSReqSetUsername `protobuf:"bytes,4,opt,name=SetUsername,proto3" json:"SetUsername,omitempty"`
-	RoomChat             *CSReqRoomChat    `protobuf:"bytes,5,opt,name=RoomChat,proto3" json:"RoomChat,omitempty"`
-	RoomList             *CSReqRoomList    `protobuf:"bytes,6,opt,name=RoomList,proto3" json:"RoomList,omitempty"`
-	JoinRoom             *CSReqJoinRoom    `protobuf:"bytes,7,opt,name=JoinRoom,proto3" json:"JoinRoom,omitempty"`
-	Chat                 *CSReqChat        `protobuf:"bytes,8,opt,name=Chat,proto3" json:"Chat,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	Seq                  int64               `protobuf:"varint,1,opt,name=Seq,proto3" json:"Seq,omitempty"`
+	Login                *CSReqLogin         `protobuf:"bytes,2,opt,name=Login,proto3" json:"Login,omitempty"`
+	Heartbeat            *CSReqHeartbeat     `protobuf:"bytes,3,opt,name=Heartbeat,proto3" json:"Heartbeat,omitempty"`
+	SetUsername          *CSReqSetUsername   `protobuf:"bytes,4,opt,name=SetUsername,proto3" json:"SetUsername,omitempty"`
+	RoomChat             *CSReqRoomChat      `protobuf:"bytes,5,opt,name=RoomChat,proto3" json:"RoomChat,omitempty"`
+	RoomList             *CSReqRoomList      `protobuf:"bytes,6,opt,name=RoomList,proto3" json:"RoomList,omitempty"`
+	JoinRoom             *CSReqJoinRoom      `protobuf:"bytes,7,opt,name=JoinRoom,proto3" json:"JoinRoom,omitempty"`
+	Chat                 *CSReqChat          `protobuf:"bytes,8,opt,name=Chat,proto3" json:"Chat,omitempty"`
+	Handshake            *CSReqHandshake     `protobuf:"bytes,9,opt,name=Handshake,proto3" json:"Handshake,omitempty"`
+	LeaveRoom            *CSReqLeaveRoom     `protobuf:"bytes,10,opt,name=LeaveRoom,proto3" json:"LeaveRoom,omitempty"`
+	RoomMembers          *CSReqRoomMembers   `protobuf:"bytes,11,opt,name=RoomMembers,proto3" json:"RoomMembers,omitempty"`
+	RoomHistory          *CSReqRoomHistory   `protobuf:"bytes,12,opt,name=RoomHistory,proto3" json:"RoomHistory,omitempty"`
+	SearchHistory        *CSReqSearchHistory `protobuf:"bytes,13,opt,name=SearchHistory,proto3" json:"SearchHistory,omitempty"`
+	InboxRead            *CSReqInboxRead     `protobuf:"bytes,14,opt,name=InboxRead,proto3" json:"InboxRead,omitempty"`
+	SetRole              *CSReqSetRole       `protobuf:"bytes,15,opt,name=SetRole,proto3" json:"SetRole,omitempty"`
+	RoomMute             *CSReqRoomMute      `protobuf:"bytes,16,opt,name=RoomMute,proto3" json:"RoomMute,omitempty"`
+	RoomKick             *CSReqRoomKick      `protobuf:"bytes,17,opt,name=RoomKick,proto3" json:"RoomKick,omitempty"`
+	Ban                  *CSReqBan           `protobuf:"bytes,18,opt,name=Ban,proto3" json:"Ban,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
 func (m *CSReqBody) Reset()         { *m = CSReqBody{} }
@@ -280,20 +530,100 @@ func (m *CSReqBody) GetChat() *CSReqChat {
 	return nil
 }
 
+func (m *CSReqBody) GetHandshake() *CSReqHandshake {
+	if m != nil {
+		return m.Handshake
+	}
+	return nil
+}
+
+func (m *CSReqBody) GetLeaveRoom() *CSReqLeaveRoom {
+	if m != nil {
+		return m.LeaveRoom
+	}
+	return nil
+}
+
+func (m *CSReqBody) GetRoomMembers() *CSReqRoomMembers {
+	if m != nil {
+		return m.RoomMembers
+	}
+	return nil
+}
+
+func (m *CSReqBody) GetRoomHistory() *CSReqRoomHistory {
+	if m != nil {
+		return m.RoomHistory
+	}
+	return nil
+}
+
+func (m *CSReqBody) GetSearchHistory() *CSReqSearchHistory {
+	if m != nil {
+		return m.SearchHistory
+	}
+	return nil
+}
+
+func (m *CSReqBody) GetInboxRead() *CSReqInboxRead {
+	if m != nil {
+		return m.InboxRead
+	}
+	return nil
+}
+
+func (m *CSReqBody) GetSetRole() *CSReqSetRole {
+	if m != nil {
+		return m.SetRole
+	}
+	return nil
+}
+
+func (m *CSReqBody) GetRoomMute() *CSReqRoomMute {
+	if m != nil {
+		return m.RoomMute
+	}
+	return nil
+}
+
+func (m *CSReqBody) GetRoomKick() *CSReqRoomKick {
+	if m != nil {
+		return m.RoomKick
+	}
+	return nil
+}
+
+func (m *CSReqBody) GetBan() *CSReqBan {
+	if m != nil {
+		return m.Ban
+	}
+	return nil
+}
+
 type CSRspBody struct {
-	Seq                  int64             `protobuf:"varint,1,opt,name=Seq,proto3" json:"Seq,omitempty"`
-	ErrCode              ERROR_CODE        `protobuf:"varint,2,opt,name=ErrCode,proto3,enum=pb.ERROR_CODE" json:"ErrCode,omitempty"`
-	ErrMsg               string            `protobuf:"bytes,3,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
-	Login                *CSRspLogin       `protobuf:"bytes,4,opt,name=Login,proto3" json:"Login,omitempty"`
-	Heartbeat            *CSRspHeartbeat   `protobuf:"bytes,5,opt,name=Heartbeat,proto3" json:"Heartbeat,omitempty"`
-	SetUsername          *CSRspSetUsername `protobuf:"bytes,6,opt,name=SetUsername,proto3" json:"SetUsername,omitempty"`
-	RoomChat             *CSRspRoomChat    `protobuf:"bytes,7,opt,name=RoomChat,proto3" json:"RoomChat,omitempty"`
-	RoomList             *CSRspRoomList    `protobuf:"bytes,8,opt,name=RoomList,proto3" json:"RoomList,omitempty"`
-	JoinRoom             *CSRspJoinRoom    `protobuf:"bytes,9,opt,name=JoinRoom,proto3" json:"JoinRoom,omitempty"`
-	Chat                 *CSRspChat        `protobuf:"bytes,10,opt,name=Chat,proto3" json:"Chat,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	Seq                  int64               `protobuf:"varint,1,opt,name=Seq,proto3" json:"Seq,omitempty"`
+	ErrCode              ERROR_CODE          `protobuf:"varint,2,opt,name=ErrCode,proto3,enum=pb.ERROR_CODE" json:"ErrCode,omitempty"`
+	ErrMsg               string              `protobuf:"bytes,3,opt,name=ErrMsg,proto3" json:"ErrMsg,omitempty"`
+	Login                *CSRspLogin         `protobuf:"bytes,4,opt,name=Login,proto3" json:"Login,omitempty"`
+	Heartbeat            *CSRspHeartbeat     `protobuf:"bytes,5,opt,name=Heartbeat,proto3" json:"Heartbeat,omitempty"`
+	SetUsername          *CSRspSetUsername   `protobuf:"bytes,6,opt,name=SetUsername,proto3" json:"SetUsername,omitempty"`
+	RoomChat             *CSRspRoomChat      `protobuf:"bytes,7,opt,name=RoomChat,proto3" json:"RoomChat,omitempty"`
+	RoomList             *CSRspRoomList      `protobuf:"bytes,8,opt,name=RoomList,proto3" json:"RoomList,omitempty"`
+	JoinRoom             *CSRspJoinRoom      `protobuf:"bytes,9,opt,name=JoinRoom,proto3" json:"JoinRoom,omitempty"`
+	Chat                 *CSRspChat          `protobuf:"bytes,10,opt,name=Chat,proto3" json:"Chat,omitempty"`
+	Handshake            *CSRspHandshake     `protobuf:"bytes,11,opt,name=Handshake,proto3" json:"Handshake,omitempty"`
+	LeaveRoom            *CSRspLeaveRoom     `protobuf:"bytes,12,opt,name=LeaveRoom,proto3" json:"LeaveRoom,omitempty"`
+	RoomMembers          *CSRspRoomMembers   `protobuf:"bytes,13,opt,name=RoomMembers,proto3" json:"RoomMembers,omitempty"`
+	RoomHistory          *CSRspRoomHistory   `protobuf:"bytes,14,opt,name=RoomHistory,proto3" json:"RoomHistory,omitempty"`
+	SearchHistory        *CSRspSearchHistory `protobuf:"bytes,15,opt,name=SearchHistory,proto3" json:"SearchHistory,omitempty"`
+	InboxRead            *CSRspInboxRead     `protobuf:"bytes,16,opt,name=InboxRead,proto3" json:"InboxRead,omitempty"`
+	SetRole              *CSRspSetRole       `protobuf:"bytes,17,opt,name=SetRole,proto3" json:"SetRole,omitempty"`
+	RoomMute             *CSRspRoomMute      `protobuf:"bytes,18,opt,name=RoomMute,proto3" json:"RoomMute,omitempty"`
+	RoomKick             *CSRspRoomKick      `protobuf:"bytes,19,opt,name=RoomKick,proto3" json:"RoomKick,omitempty"`
+	Ban                  *CSRspBan           `protobuf:"bytes,20,opt,name=Ban,proto3" json:"Ban,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
 func (m *CSRspBody) Reset()         { *m = CSRspBody{} }
@@ -391,16 +721,95 @@ func (m *CSRspBody) GetChat() *CSRspChat {
 	return nil
 }
 
+func (m *CSRspBody) GetHandshake() *CSRspHandshake {
+	if m != nil {
+		return m.Handshake
+	}
+	return nil
+}
+
+func (m *CSRspBody) GetLeaveRoom() *CSRspLeaveRoom {
+	if m != nil {
+		return m.LeaveRoom
+	}
+	return nil
+}
+
+func (m *CSRspBody) GetRoomMembers() *CSRspRoomMembers {
+	if m != nil {
+		return m.RoomMembers
+	}
+	return nil
+}
+
+func (m *CSRspBody) GetRoomHistory() *CSRspRoomHistory {
+	if m != nil {
+		return m.RoomHistory
+	}
+	return nil
+}
+
+func (m *CSRspBody) GetSearchHistory() *CSRspSearchHistory {
+	if m != nil {
+		return m.SearchHistory
+	}
+	return nil
+}
+
+func (m *CSRspBody) GetInboxRead() *CSRspInboxRead {
+	if m != nil {
+		return m.InboxRead
+	}
+	return nil
+}
+
+func (m *CSRspBody) GetSetRole() *CSRspSetRole {
+	if m != nil {
+		return m.SetRole
+	}
+	return nil
+}
+
+func (m *CSRspBody) GetRoomMute() *CSRspRoomMute {
+	if m != nil {
+		return m.RoomMute
+	}
+	return nil
+}
+
+func (m *CSRspBody) GetRoomKick() *CSRspRoomKick {
+	if m != nil {
+		return m.RoomKick
+	}
+	return nil
+}
+
+func (m *CSRspBody) GetBan() *CSRspBan {
+	if m != nil {
+		return m.Ban
+	}
+	return nil
+}
+
 type CSNtfBody struct {
-	Kick                 *CSNtfKick             `protobuf:"bytes,1,opt,name=Kick,proto3" json:"Kick,omitempty"`
-	RoomMemberOnline     *CSNtfRoomMemberOnline `protobuf:"bytes,2,opt,name=RoomMemberOnline,proto3" json:"RoomMemberOnline,omitempty"`
-	RoomChat             *CSNtfRoomChat         `protobuf:"bytes,3,opt,name=RoomChat,proto3" json:"RoomChat,omitempty"`
-	RoomClosed           *CSNtfRoomClosed       `protobuf:"bytes,4,opt,name=RoomClosed,proto3" json:"RoomClosed,omitempty"`
-	HistoryMsg           *CSNtfHistoryMsg       `protobuf:"bytes,5,opt,name=HistoryMsg,proto3" json:"HistoryMsg,omitempty"`
-	Chat                 *CSNtfChat             `protobuf:"bytes,6,opt,name=Chat,proto3" json:"Chat,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
-	XXX_unrecognized     []byte                 `json:"-"`
-	XXX_sizecache        int32                  `json:"-"`
+	Kick                 *CSNtfKick              `protobuf:"bytes,1,opt,name=Kick,proto3" json:"Kick,omitempty"`
+	RoomMemberOnline     *CSNtfRoomMemberOnline  `protobuf:"bytes,2,opt,name=RoomMemberOnline,proto3" json:"RoomMemberOnline,omitempty"`
+	RoomChat             *CSNtfRoomChat          `protobuf:"bytes,3,opt,name=RoomChat,proto3" json:"RoomChat,omitempty"`
+	RoomClosed           *CSNtfRoomClosed        `protobuf:"bytes,4,opt,name=RoomClosed,proto3" json:"RoomClosed,omitempty"`
+	HistoryMsg           *CSNtfHistoryMsg        `protobuf:"bytes,5,opt,name=HistoryMsg,proto3" json:"HistoryMsg,omitempty"`
+	Chat                 *CSNtfChat              `protobuf:"bytes,6,opt,name=Chat,proto3" json:"Chat,omitempty"`
+	RoomMemberLeave      *CSNtfRoomMemberLeave   `protobuf:"bytes,7,opt,name=RoomMemberLeave,proto3" json:"RoomMemberLeave,omitempty"`
+	RoomMemberOffline    *CSNtfRoomMemberOffline `protobuf:"bytes,8,opt,name=RoomMemberOffline,proto3" json:"RoomMemberOffline,omitempty"`
+	RoomPresence         *CSNtfRoomPresence      `protobuf:"bytes,9,opt,name=RoomPresence,proto3" json:"RoomPresence,omitempty"`
+	Resync               *CSNtfResync            `protobuf:"bytes,10,opt,name=Resync,proto3" json:"Resync,omitempty"`
+	Inbox                *CSNtfInbox             `protobuf:"bytes,11,opt,name=Inbox,proto3" json:"Inbox,omitempty"`
+	RoomRole             *CSNtfRoomRole          `protobuf:"bytes,12,opt,name=RoomRole,proto3" json:"RoomRole,omitempty"`
+	RoomMute             *CSNtfRoomMute          `protobuf:"bytes,13,opt,name=RoomMute,proto3" json:"RoomMute,omitempty"`
+	RoomKicked           *CSNtfRoomKicked        `protobuf:"bytes,14,opt,name=RoomKicked,proto3" json:"RoomKicked,omitempty"`
+	Rename               *CSNtfRename            `protobuf:"bytes,15,opt,name=Rename,proto3" json:"Rename,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
 }
 
 func (m *CSNtfBody) Reset()         { *m = CSNtfBody{} }
@@ -470,11 +879,81 @@ func (m *CSNtfBody) GetChat() *CSNtfChat {
 	return nil
 }
 
+func (m *CSNtfBody) GetRoomMemberLeave() *CSNtfRoomMemberLeave {
+	if m != nil {
+		return m.RoomMemberLeave
+	}
+	return nil
+}
+
+func (m *CSNtfBody) GetRoomMemberOffline() *CSNtfRoomMemberOffline {
+	if m != nil {
+		return m.RoomMemberOffline
+	}
+	return nil
+}
+
+func (m *CSNtfBody) GetRoomPresence() *CSNtfRoomPresence {
+	if m != nil {
+		return m.RoomPresence
+	}
+	return nil
+}
+
+func (m *CSNtfBody) GetResync() *CSNtfResync {
+	if m != nil {
+		return m.Resync
+	}
+	return nil
+}
+
+func (m *CSNtfBody) GetInbox() *CSNtfInbox {
+	if m != nil {
+		return m.Inbox
+	}
+	return nil
+}
+
+func (m *CSNtfBody) GetRoomRole() *CSNtfRoomRole {
+	if m != nil {
+		return m.RoomRole
+	}
+	return nil
+}
+
+func (m *CSNtfBody) GetRoomMute() *CSNtfRoomMute {
+	if m != nil {
+		return m.RoomMute
+	}
+	return nil
+}
+
+func (m *CSNtfBody) GetRoomKicked() *CSNtfRoomKicked {
+	if m != nil {
+		return m.RoomKicked
+	}
+	return nil
+}
+
+func (m *CSNtfBody) GetRename() *CSNtfRename {
+	if m != nil {
+		return m.Rename
+	}
+	return nil
+}
+
 type CSReqLogin struct {
-	Username             string   `protobuf:"bytes,1,opt,name=Username,proto3" json:"Username,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Username             string           `protobuf:"bytes,1,opt,name=Username,proto3" json:"Username,omitempty"`
+	Codecs               []COMPRESS_CODEC `protobuf:"varint,2,rep,packed,name=Codecs,proto3,enum=pb.COMPRESS_CODEC" json:"Codecs,omitempty"`
+	ResumeToken          string           `protobuf:"bytes,3,opt,name=ResumeToken,proto3" json:"ResumeToken,omitempty"`
+	LastSeq              int64            `protobuf:"varint,4,opt,name=LastSeq,proto3" json:"LastSeq,omitempty"`
+	Ack                  int64            `protobuf:"varint,5,opt,name=Ack,proto3" json:"Ack,omitempty"`
+	NameKey              string           `protobuf:"bytes,6,opt,name=NameKey,proto3" json:"NameKey,omitempty"`
+	Password             string           `protobuf:"bytes,7,opt,name=Password,proto3" json:"Password,omitempty"`
+	AuthToken            string           `protobuf:"bytes,8,opt,name=AuthToken,proto3" json:"AuthToken,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
 func (m *CSReqLogin) Reset()         { *m = CSReqLogin{} }
@@ -509,12 +988,65 @@ func (m *CSReqLogin) GetUsername() string {
 	return ""
 }
 
+func (m *CSReqLogin) GetCodecs() []COMPRESS_CODEC {
+	if m != nil {
+		return m.Codecs
+	}
+	return nil
+}
+
+func (m *CSReqLogin) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
+	}
+	return ""
+}
+
+func (m *CSReqLogin) GetLastSeq() int64 {
+	if m != nil {
+		return m.LastSeq
+	}
+	return 0
+}
+
+func (m *CSReqLogin) GetAck() int64 {
+	if m != nil {
+		return m.Ack
+	}
+	return 0
+}
+
+func (m *CSReqLogin) GetNameKey() string {
+	if m != nil {
+		return m.NameKey
+	}
+	return ""
+}
+
+func (m *CSReqLogin) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *CSReqLogin) GetAuthToken() string {
+	if m != nil {
+		return m.AuthToken
+	}
+	return ""
+}
+
 type CSRspLogin struct {
-	RoomID               int64    `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
-	Username             string   `protobuf:"bytes,2,opt,name=Username,proto3" json:"Username,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	RoomID               int64          `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	Username             string         `protobuf:"bytes,2,opt,name=Username,proto3" json:"Username,omitempty"`
+	Codec                COMPRESS_CODEC `protobuf:"varint,3,opt,name=Codec,proto3,enum=pb.COMPRESS_CODEC" json:"Codec,omitempty"`
+	HeartbeatInterval    int32          `protobuf:"varint,4,opt,name=HeartbeatInterval,proto3" json:"HeartbeatInterval,omitempty"`
+	ResumeToken          string         `protobuf:"bytes,5,opt,name=ResumeToken,proto3" json:"ResumeToken,omitempty"`
+	Resumed              bool           `protobuf:"varint,6,opt,name=Resumed,proto3" json:"Resumed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *CSRspLogin) Reset()         { *m = CSRspLogin{} }
@@ -556,13 +1088,43 @@ func (m *CSRspLogin) GetUsername() string {
 	return ""
 }
 
-type CSReqHeartbeat struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *CSRspLogin) GetCodec() COMPRESS_CODEC {
+	if m != nil {
+		return m.Codec
+	}
+	return COMPRESS_CODEC_COMPRESS_NONE
 }
 
-func (m *CSReqHeartbeat) Reset()         { *m = CSReqHeartbeat{} }
+func (m *CSRspLogin) GetHeartbeatInterval() int32 {
+	if m != nil {
+		return m.HeartbeatInterval
+	}
+	return 0
+}
+
+func (m *CSRspLogin) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
+	}
+	return ""
+}
+
+func (m *CSRspLogin) GetResumed() bool {
+	if m != nil {
+		return m.Resumed
+	}
+	return false
+}
+
+type CSReqHeartbeat struct {
+	ClientTime           int64    `protobuf:"varint,1,opt,name=ClientTime,proto3" json:"ClientTime,omitempty"`
+	Ack                  int64    `protobuf:"varint,2,opt,name=Ack,proto3" json:"Ack,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSReqHeartbeat) Reset()         { *m = CSReqHeartbeat{} }
 func (m *CSReqHeartbeat) String() string { return proto.CompactTextString(m) }
 func (*CSReqHeartbeat) ProtoMessage()    {}
 func (*CSReqHeartbeat) Descriptor() ([]byte, []int) {
@@ -587,7 +1149,23 @@ func (m *CSReqHeartbeat) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_CSReqHeartbeat proto.InternalMessageInfo
 
+func (m *CSReqHeartbeat) GetClientTime() int64 {
+	if m != nil {
+		return m.ClientTime
+	}
+	return 0
+}
+
+func (m *CSReqHeartbeat) GetAck() int64 {
+	if m != nil {
+		return m.Ack
+	}
+	return 0
+}
+
 type CSRspHeartbeat struct {
+	ClientTime           int64    `protobuf:"varint,1,opt,name=ClientTime,proto3" json:"ClientTime,omitempty"`
+	ServerTime           int64    `protobuf:"varint,2,opt,name=ServerTime,proto3" json:"ServerTime,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -618,8 +1196,23 @@ func (m *CSRspHeartbeat) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_CSRspHeartbeat proto.InternalMessageInfo
 
+func (m *CSRspHeartbeat) GetClientTime() int64 {
+	if m != nil {
+		return m.ClientTime
+	}
+	return 0
+}
+
+func (m *CSRspHeartbeat) GetServerTime() int64 {
+	if m != nil {
+		return m.ServerTime
+	}
+	return 0
+}
+
 type CSReqSetUsername struct {
 	Username             string   `protobuf:"bytes,1,opt,name=Username,proto3" json:"Username,omitempty"`
+	Reserve              bool     `protobuf:"varint,2,opt,name=Reserve,proto3" json:"Reserve,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -657,7 +1250,16 @@ func (m *CSReqSetUsername) GetUsername() string {
 	return ""
 }
 
+func (m *CSReqSetUsername) GetReserve() bool {
+	if m != nil {
+		return m.Reserve
+	}
+	return false
+}
+
 type CSRspSetUsername struct {
+	Username             string   `protobuf:"bytes,1,opt,name=Username,proto3" json:"Username,omitempty"`
+	NameKey              string   `protobuf:"bytes,2,opt,name=NameKey,proto3" json:"NameKey,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -688,6 +1290,20 @@ func (m *CSRspSetUsername) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_CSRspSetUsername proto.InternalMessageInfo
 
+func (m *CSRspSetUsername) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *CSRspSetUsername) GetNameKey() string {
+	if m != nil {
+		return m.NameKey
+	}
+	return ""
+}
+
 type CSReqRoomChat struct {
 	Content              string   `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -801,6 +1417,7 @@ type RoomInfo struct {
 	CurrentMemberNum     int32    `protobuf:"varint,1,opt,name=CurrentMemberNum,proto3" json:"CurrentMemberNum,omitempty"`
 	TotalMemberNum       int32    `protobuf:"varint,2,opt,name=TotalMemberNum,proto3" json:"TotalMemberNum,omitempty"`
 	RoomID               int64    `protobuf:"varint,3,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	Name                 string   `protobuf:"bytes,4,opt,name=Name,proto3" json:"Name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -852,6 +1469,13 @@ func (m *RoomInfo) GetRoomID() int64 {
 	return 0
 }
 
+func (m *RoomInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
 type CSRspRoomList struct {
 	Rooms                []*RoomInfo `protobuf:"bytes,1,rep,name=Rooms,proto3" json:"Rooms,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
@@ -891,9 +1515,12 @@ func (m *CSRspRoomList) GetRooms() []*RoomInfo {
 	return nil
 }
 
+// CreateNew时新建名为Name的房间并加入, 否则加入RoomID, RoomID为0时自动选择
+// 已在其他房间时先离开原房间
 type CSReqJoinRoom struct {
 	RoomID               int64    `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
 	CreateNew            bool     `protobuf:"varint,2,opt,name=CreateNew,proto3" json:"CreateNew,omitempty"`
+	Name                 string   `protobuf:"bytes,3,opt,name=Name,proto3" json:"Name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -938,7 +1565,16 @@ func (m *CSReqJoinRoom) GetCreateNew() bool {
 	return false
 }
 
+func (m *CSReqJoinRoom) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
 type CSRspJoinRoom struct {
+	RoomID               int64    `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -969,256 +1605,1919 @@ func (m *CSRspJoinRoom) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_CSRspJoinRoom proto.InternalMessageInfo
 
-type CSReqChat struct {
-	Content              string   `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
-	Username             string   `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
-
-func (m *CSReqChat) Reset()         { *m = CSReqChat{} }
-func (m *CSReqChat) String() string { return proto.CompactTextString(m) }
-func (*CSReqChat) ProtoMessage()    {}
-func (*CSReqChat) Descriptor() ([]byte, []int) {
-	return fileDescriptor_af7bf51985781725, []int{17}
-}
-
-func (m *CSReqChat) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CSReqChat.Unmarshal(m, b)
-}
-func (m *CSReqChat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CSReqChat.Marshal(b, m, deterministic)
-}
-func (m *CSReqChat) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CSReqChat.Merge(m, src)
-}
-func (m *CSReqChat) XXX_Size() int {
-	return xxx_messageInfo_CSReqChat.Size(m)
-}
-func (m *CSReqChat) XXX_DiscardUnknown() {
-	xxx_messageInfo_CSReqChat.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_CSReqChat proto.InternalMessageInfo
-
-func (m *CSReqChat) GetContent() string {
+func (m *CSRspJoinRoom) GetRoomID() int64 {
 	if m != nil {
-		return m.Content
+		return m.RoomID
 	}
-	return ""
+	return 0
 }
 
-func (m *CSReqChat) GetUsername() string {
+func (m *CSRspJoinRoom) GetName() string {
 	if m != nil {
-		return m.Username
+		return m.Name
 	}
 	return ""
 }
 
-type CSRspChat struct {
+type CSReqLeaveRoom struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CSRspChat) Reset()         { *m = CSRspChat{} }
-func (m *CSRspChat) String() string { return proto.CompactTextString(m) }
-func (*CSRspChat) ProtoMessage()    {}
-func (*CSRspChat) Descriptor() ([]byte, []int) {
-	return fileDescriptor_af7bf51985781725, []int{18}
+func (m *CSReqLeaveRoom) Reset()         { *m = CSReqLeaveRoom{} }
+func (m *CSReqLeaveRoom) String() string { return proto.CompactTextString(m) }
+func (*CSReqLeaveRoom) ProtoMessage()    {}
+func (*CSReqLeaveRoom) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{17}
 }
 
-func (m *CSRspChat) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CSRspChat.Unmarshal(m, b)
+func (m *CSReqLeaveRoom) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSReqLeaveRoom.Unmarshal(m, b)
 }
-func (m *CSRspChat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CSRspChat.Marshal(b, m, deterministic)
+func (m *CSReqLeaveRoom) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSReqLeaveRoom.Marshal(b, m, deterministic)
 }
-func (m *CSRspChat) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CSRspChat.Merge(m, src)
+func (m *CSReqLeaveRoom) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSReqLeaveRoom.Merge(m, src)
 }
-func (m *CSRspChat) XXX_Size() int {
-	return xxx_messageInfo_CSRspChat.Size(m)
+func (m *CSReqLeaveRoom) XXX_Size() int {
+	return xxx_messageInfo_CSReqLeaveRoom.Size(m)
 }
-func (m *CSRspChat) XXX_DiscardUnknown() {
-	xxx_messageInfo_CSRspChat.DiscardUnknown(m)
+func (m *CSReqLeaveRoom) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSReqLeaveRoom.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CSRspChat proto.InternalMessageInfo
+var xxx_messageInfo_CSReqLeaveRoom proto.InternalMessageInfo
 
-type CSNtfKick struct {
+type CSRspLeaveRoom struct {
+	RoomID               int64    `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CSNtfKick) Reset()         { *m = CSNtfKick{} }
-func (m *CSNtfKick) String() string { return proto.CompactTextString(m) }
-func (*CSNtfKick) ProtoMessage()    {}
-func (*CSNtfKick) Descriptor() ([]byte, []int) {
-	return fileDescriptor_af7bf51985781725, []int{19}
+func (m *CSRspLeaveRoom) Reset()         { *m = CSRspLeaveRoom{} }
+func (m *CSRspLeaveRoom) String() string { return proto.CompactTextString(m) }
+func (*CSRspLeaveRoom) ProtoMessage()    {}
+func (*CSRspLeaveRoom) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{18}
 }
 
-func (m *CSNtfKick) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CSNtfKick.Unmarshal(m, b)
+func (m *CSRspLeaveRoom) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSRspLeaveRoom.Unmarshal(m, b)
 }
-func (m *CSNtfKick) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CSNtfKick.Marshal(b, m, deterministic)
+func (m *CSRspLeaveRoom) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSRspLeaveRoom.Marshal(b, m, deterministic)
 }
-func (m *CSNtfKick) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CSNtfKick.Merge(m, src)
+func (m *CSRspLeaveRoom) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSRspLeaveRoom.Merge(m, src)
 }
-func (m *CSNtfKick) XXX_Size() int {
-	return xxx_messageInfo_CSNtfKick.Size(m)
+func (m *CSRspLeaveRoom) XXX_Size() int {
+	return xxx_messageInfo_CSRspLeaveRoom.Size(m)
 }
-func (m *CSNtfKick) XXX_DiscardUnknown() {
-	xxx_messageInfo_CSNtfKick.DiscardUnknown(m)
+func (m *CSRspLeaveRoom) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSRspLeaveRoom.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CSNtfKick proto.InternalMessageInfo
+var xxx_messageInfo_CSRspLeaveRoom proto.InternalMessageInfo
 
-type CSNtfRoomMemberOnline struct {
-	RoomID               int64    `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
-	Username             string   `protobuf:"bytes,2,opt,name=Username,proto3" json:"Username,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *CSRspLeaveRoom) GetRoomID() int64 {
+	if m != nil {
+		return m.RoomID
+	}
+	return 0
 }
 
-func (m *CSNtfRoomMemberOnline) Reset()         { *m = CSNtfRoomMemberOnline{} }
-func (m *CSNtfRoomMemberOnline) String() string { return proto.CompactTextString(m) }
-func (*CSNtfRoomMemberOnline) ProtoMessage()    {}
-func (*CSNtfRoomMemberOnline) Descriptor() ([]byte, []int) {
-	return fileDescriptor_af7bf51985781725, []int{20}
+type RoomMember struct {
+	Username             string    `protobuf:"bytes,1,opt,name=Username,proto3" json:"Username,omitempty"`
+	JoinTime             int64     `protobuf:"varint,2,opt,name=JoinTime,proto3" json:"JoinTime,omitempty"`
+	Role                 ROOM_ROLE `protobuf:"varint,3,opt,name=Role,proto3,enum=pb.ROOM_ROLE" json:"Role,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
-func (m *CSNtfRoomMemberOnline) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CSNtfRoomMemberOnline.Unmarshal(m, b)
+func (m *RoomMember) Reset()         { *m = RoomMember{} }
+func (m *RoomMember) String() string { return proto.CompactTextString(m) }
+func (*RoomMember) ProtoMessage()    {}
+func (*RoomMember) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{19}
 }
-func (m *CSNtfRoomMemberOnline) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CSNtfRoomMemberOnline.Marshal(b, m, deterministic)
+
+func (m *RoomMember) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoomMember.Unmarshal(m, b)
 }
-func (m *CSNtfRoomMemberOnline) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CSNtfRoomMemberOnline.Merge(m, src)
+func (m *RoomMember) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoomMember.Marshal(b, m, deterministic)
 }
-func (m *CSNtfRoomMemberOnline) XXX_Size() int {
-	return xxx_messageInfo_CSNtfRoomMemberOnline.Size(m)
+func (m *RoomMember) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoomMember.Merge(m, src)
 }
-func (m *CSNtfRoomMemberOnline) XXX_DiscardUnknown() {
-	xxx_messageInfo_CSNtfRoomMemberOnline.DiscardUnknown(m)
+func (m *RoomMember) XXX_Size() int {
+	return xxx_messageInfo_RoomMember.Size(m)
+}
+func (m *RoomMember) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoomMember.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CSNtfRoomMemberOnline proto.InternalMessageInfo
+var xxx_messageInfo_RoomMember proto.InternalMessageInfo
 
-func (m *CSNtfRoomMemberOnline) GetRoomID() int64 {
+func (m *RoomMember) GetUsername() string {
 	if m != nil {
-		return m.RoomID
+		return m.Username
+	}
+	return ""
+}
+
+func (m *RoomMember) GetJoinTime() int64 {
+	if m != nil {
+		return m.JoinTime
 	}
 	return 0
 }
 
-func (m *CSNtfRoomMemberOnline) GetUsername() string {
+func (m *RoomMember) GetRole() ROOM_ROLE {
 	if m != nil {
-		return m.Username
+		return m.Role
 	}
-	return ""
+	return ROOM_ROLE_ROLE_MEMBER
 }
 
-type CSNtfRoomChat struct {
-	Username             string   `protobuf:"bytes,1,opt,name=Username,proto3" json:"Username,omitempty"`
-	Content              string   `protobuf:"bytes,2,opt,name=Content,proto3" json:"Content,omitempty"`
+// 当前所在房间的成员列表
+type CSReqRoomMembers struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CSNtfRoomChat) Reset()         { *m = CSNtfRoomChat{} }
-func (m *CSNtfRoomChat) String() string { return proto.CompactTextString(m) }
-func (*CSNtfRoomChat) ProtoMessage()    {}
-func (*CSNtfRoomChat) Descriptor() ([]byte, []int) {
+func (m *CSReqRoomMembers) Reset()         { *m = CSReqRoomMembers{} }
+func (m *CSReqRoomMembers) String() string { return proto.CompactTextString(m) }
+func (*CSReqRoomMembers) ProtoMessage()    {}
+func (*CSReqRoomMembers) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{20}
+}
+
+func (m *CSReqRoomMembers) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSReqRoomMembers.Unmarshal(m, b)
+}
+func (m *CSReqRoomMembers) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSReqRoomMembers.Marshal(b, m, deterministic)
+}
+func (m *CSReqRoomMembers) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSReqRoomMembers.Merge(m, src)
+}
+func (m *CSReqRoomMembers) XXX_Size() int {
+	return xxx_messageInfo_CSReqRoomMembers.Size(m)
+}
+func (m *CSReqRoomMembers) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSReqRoomMembers.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSReqRoomMembers proto.InternalMessageInfo
+
+type CSRspRoomMembers struct {
+	RoomID               int64         `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	Members              []*RoomMember `protobuf:"bytes,2,rep,name=Members,proto3" json:"Members,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *CSRspRoomMembers) Reset()         { *m = CSRspRoomMembers{} }
+func (m *CSRspRoomMembers) String() string { return proto.CompactTextString(m) }
+func (*CSRspRoomMembers) ProtoMessage()    {}
+func (*CSRspRoomMembers) Descriptor() ([]byte, []int) {
 	return fileDescriptor_af7bf51985781725, []int{21}
 }
 
-func (m *CSNtfRoomChat) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CSNtfRoomChat.Unmarshal(m, b)
+func (m *CSRspRoomMembers) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSRspRoomMembers.Unmarshal(m, b)
 }
-func (m *CSNtfRoomChat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CSNtfRoomChat.Marshal(b, m, deterministic)
+func (m *CSRspRoomMembers) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSRspRoomMembers.Marshal(b, m, deterministic)
 }
-func (m *CSNtfRoomChat) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CSNtfRoomChat.Merge(m, src)
+func (m *CSRspRoomMembers) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSRspRoomMembers.Merge(m, src)
 }
-func (m *CSNtfRoomChat) XXX_Size() int {
-	return xxx_messageInfo_CSNtfRoomChat.Size(m)
+func (m *CSRspRoomMembers) XXX_Size() int {
+	return xxx_messageInfo_CSRspRoomMembers.Size(m)
 }
-func (m *CSNtfRoomChat) XXX_DiscardUnknown() {
-	xxx_messageInfo_CSNtfRoomChat.DiscardUnknown(m)
+func (m *CSRspRoomMembers) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSRspRoomMembers.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CSNtfRoomChat proto.InternalMessageInfo
+var xxx_messageInfo_CSRspRoomMembers proto.InternalMessageInfo
 
-func (m *CSNtfRoomChat) GetUsername() string {
+func (m *CSRspRoomMembers) GetRoomID() int64 {
 	if m != nil {
-		return m.Username
+		return m.RoomID
 	}
-	return ""
+	return 0
 }
 
-func (m *CSNtfRoomChat) GetContent() string {
+func (m *CSRspRoomMembers) GetMembers() []*RoomMember {
 	if m != nil {
-		return m.Content
+		return m.Members
 	}
-	return ""
+	return nil
 }
 
-type CSNtfRoomClosed struct {
+// 分页拉取当前房间的历史消息, BeforeID与AfterID都为0时取最新的一页
+type CSReqRoomHistory struct {
+	BeforeID             int64    `protobuf:"varint,1,opt,name=BeforeID,proto3" json:"BeforeID,omitempty"`
+	AfterID              int64    `protobuf:"varint,2,opt,name=AfterID,proto3" json:"AfterID,omitempty"`
+	Limit                int32    `protobuf:"varint,3,opt,name=Limit,proto3" json:"Limit,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CSNtfRoomClosed) Reset()         { *m = CSNtfRoomClosed{} }
-func (m *CSNtfRoomClosed) String() string { return proto.CompactTextString(m) }
-func (*CSNtfRoomClosed) ProtoMessage()    {}
-func (*CSNtfRoomClosed) Descriptor() ([]byte, []int) {
+func (m *CSReqRoomHistory) Reset()         { *m = CSReqRoomHistory{} }
+func (m *CSReqRoomHistory) String() string { return proto.CompactTextString(m) }
+func (*CSReqRoomHistory) ProtoMessage()    {}
+func (*CSReqRoomHistory) Descriptor() ([]byte, []int) {
 	return fileDescriptor_af7bf51985781725, []int{22}
 }
 
-func (m *CSNtfRoomClosed) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CSNtfRoomClosed.Unmarshal(m, b)
+func (m *CSReqRoomHistory) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSReqRoomHistory.Unmarshal(m, b)
 }
-func (m *CSNtfRoomClosed) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CSNtfRoomClosed.Marshal(b, m, deterministic)
+func (m *CSReqRoomHistory) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSReqRoomHistory.Marshal(b, m, deterministic)
 }
-func (m *CSNtfRoomClosed) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CSNtfRoomClosed.Merge(m, src)
+func (m *CSReqRoomHistory) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSReqRoomHistory.Merge(m, src)
 }
-func (m *CSNtfRoomClosed) XXX_Size() int {
-	return xxx_messageInfo_CSNtfRoomClosed.Size(m)
+func (m *CSReqRoomHistory) XXX_Size() int {
+	return xxx_messageInfo_CSReqRoomHistory.Size(m)
 }
-func (m *CSNtfRoomClosed) XXX_DiscardUnknown() {
-	xxx_messageInfo_CSNtfRoomClosed.DiscardUnknown(m)
+func (m *CSReqRoomHistory) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSReqRoomHistory.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CSNtfRoomClosed proto.InternalMessageInfo
+var xxx_messageInfo_CSReqRoomHistory proto.InternalMessageInfo
 
-type HistoryChat struct {
-	From                 string   `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
-	Dt                   string   `protobuf:"bytes,2,opt,name=dt,proto3" json:"dt,omitempty"`
-	Content              string   `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *CSReqRoomHistory) GetBeforeID() int64 {
+	if m != nil {
+		return m.BeforeID
+	}
+	return 0
 }
 
-func (m *HistoryChat) Reset()         { *m = HistoryChat{} }
-func (m *HistoryChat) String() string { return proto.CompactTextString(m) }
-func (*HistoryChat) ProtoMessage()    {}
-func (*HistoryChat) Descriptor() ([]byte, []int) {
+func (m *CSReqRoomHistory) GetAfterID() int64 {
+	if m != nil {
+		return m.AfterID
+	}
+	return 0
+}
+
+func (m *CSReqRoomHistory) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type CSRspRoomHistory struct {
+	RoomID               int64          `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	History              []*HistoryChat `protobuf:"bytes,2,rep,name=History,proto3" json:"History,omitempty"`
+	More                 bool           `protobuf:"varint,3,opt,name=More,proto3" json:"More,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *CSRspRoomHistory) Reset()         { *m = CSRspRoomHistory{} }
+func (m *CSRspRoomHistory) String() string { return proto.CompactTextString(m) }
+func (*CSRspRoomHistory) ProtoMessage()    {}
+func (*CSRspRoomHistory) Descriptor() ([]byte, []int) {
 	return fileDescriptor_af7bf51985781725, []int{23}
 }
 
+func (m *CSRspRoomHistory) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSRspRoomHistory.Unmarshal(m, b)
+}
+func (m *CSRspRoomHistory) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSRspRoomHistory.Marshal(b, m, deterministic)
+}
+func (m *CSRspRoomHistory) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSRspRoomHistory.Merge(m, src)
+}
+func (m *CSRspRoomHistory) XXX_Size() int {
+	return xxx_messageInfo_CSRspRoomHistory.Size(m)
+}
+func (m *CSRspRoomHistory) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSRspRoomHistory.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSRspRoomHistory proto.InternalMessageInfo
+
+func (m *CSRspRoomHistory) GetRoomID() int64 {
+	if m != nil {
+		return m.RoomID
+	}
+	return 0
+}
+
+func (m *CSRspRoomHistory) GetHistory() []*HistoryChat {
+	if m != nil {
+		return m.History
+	}
+	return nil
+}
+
+func (m *CSRspRoomHistory) GetMore() bool {
+	if m != nil {
+		return m.More
+	}
+	return false
+}
+
+// 搜索当前房间的历史消息
+type CSReqSearchHistory struct {
+	Query                string   `protobuf:"bytes,1,opt,name=Query,proto3" json:"Query,omitempty"`
+	From                 string   `protobuf:"bytes,2,opt,name=From,proto3" json:"From,omitempty"`
+	BeforeID             int64    `protobuf:"varint,3,opt,name=BeforeID,proto3" json:"BeforeID,omitempty"`
+	Limit                int32    `protobuf:"varint,4,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSReqSearchHistory) Reset()         { *m = CSReqSearchHistory{} }
+func (m *CSReqSearchHistory) String() string { return proto.CompactTextString(m) }
+func (*CSReqSearchHistory) ProtoMessage()    {}
+func (*CSReqSearchHistory) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{24}
+}
+
+func (m *CSReqSearchHistory) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSReqSearchHistory.Unmarshal(m, b)
+}
+func (m *CSReqSearchHistory) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSReqSearchHistory.Marshal(b, m, deterministic)
+}
+func (m *CSReqSearchHistory) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSReqSearchHistory.Merge(m, src)
+}
+func (m *CSReqSearchHistory) XXX_Size() int {
+	return xxx_messageInfo_CSReqSearchHistory.Size(m)
+}
+func (m *CSReqSearchHistory) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSReqSearchHistory.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSReqSearchHistory proto.InternalMessageInfo
+
+func (m *CSReqSearchHistory) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
+func (m *CSReqSearchHistory) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *CSReqSearchHistory) GetBeforeID() int64 {
+	if m != nil {
+		return m.BeforeID
+	}
+	return 0
+}
+
+func (m *CSReqSearchHistory) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type CSRspSearchHistory struct {
+	RoomID               int64          `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	Results              []*HistoryChat `protobuf:"bytes,2,rep,name=Results,proto3" json:"Results,omitempty"`
+	More                 bool           `protobuf:"varint,3,opt,name=More,proto3" json:"More,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *CSRspSearchHistory) Reset()         { *m = CSRspSearchHistory{} }
+func (m *CSRspSearchHistory) String() string { return proto.CompactTextString(m) }
+func (*CSRspSearchHistory) ProtoMessage()    {}
+func (*CSRspSearchHistory) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{25}
+}
+
+func (m *CSRspSearchHistory) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSRspSearchHistory.Unmarshal(m, b)
+}
+func (m *CSRspSearchHistory) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSRspSearchHistory.Marshal(b, m, deterministic)
+}
+func (m *CSRspSearchHistory) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSRspSearchHistory.Merge(m, src)
+}
+func (m *CSRspSearchHistory) XXX_Size() int {
+	return xxx_messageInfo_CSRspSearchHistory.Size(m)
+}
+func (m *CSRspSearchHistory) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSRspSearchHistory.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSRspSearchHistory proto.InternalMessageInfo
+
+func (m *CSRspSearchHistory) GetRoomID() int64 {
+	if m != nil {
+		return m.RoomID
+	}
+	return 0
+}
+
+func (m *CSRspSearchHistory) GetResults() []*HistoryChat {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+func (m *CSRspSearchHistory) GetMore() bool {
+	if m != nil {
+		return m.More
+	}
+	return false
+}
+
+// 私聊, 对方不在线时存入收件箱, 下次登录时下发
+type CSReqChat struct {
+	Content              string   `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	Username             string   `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSReqChat) Reset()         { *m = CSReqChat{} }
+func (m *CSReqChat) String() string { return proto.CompactTextString(m) }
+func (*CSReqChat) ProtoMessage()    {}
+func (*CSReqChat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{26}
+}
+
+func (m *CSReqChat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSReqChat.Unmarshal(m, b)
+}
+func (m *CSReqChat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSReqChat.Marshal(b, m, deterministic)
+}
+func (m *CSReqChat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSReqChat.Merge(m, src)
+}
+func (m *CSReqChat) XXX_Size() int {
+	return xxx_messageInfo_CSReqChat.Size(m)
+}
+func (m *CSReqChat) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSReqChat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSReqChat proto.InternalMessageInfo
+
+func (m *CSReqChat) GetContent() string {
+	if m != nil {
+		return m.Content
+	}
+	return ""
+}
+
+func (m *CSReqChat) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+type CSRspChat struct {
+	MsgID                int64    `protobuf:"varint,1,opt,name=MsgID,proto3" json:"MsgID,omitempty"`
+	Stored               bool     `protobuf:"varint,2,opt,name=Stored,proto3" json:"Stored,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSRspChat) Reset()         { *m = CSRspChat{} }
+func (m *CSRspChat) String() string { return proto.CompactTextString(m) }
+func (*CSRspChat) ProtoMessage()    {}
+func (*CSRspChat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{27}
+}
+
+func (m *CSRspChat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSRspChat.Unmarshal(m, b)
+}
+func (m *CSRspChat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSRspChat.Marshal(b, m, deterministic)
+}
+func (m *CSRspChat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSRspChat.Merge(m, src)
+}
+func (m *CSRspChat) XXX_Size() int {
+	return xxx_messageInfo_CSRspChat.Size(m)
+}
+func (m *CSRspChat) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSRspChat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSRspChat proto.InternalMessageInfo
+
+func (m *CSRspChat) GetMsgID() int64 {
+	if m != nil {
+		return m.MsgID
+	}
+	return 0
+}
+
+func (m *CSRspChat) GetStored() bool {
+	if m != nil {
+		return m.Stored
+	}
+	return false
+}
+
+type PrivateMsg struct {
+	ID                   int64    `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	From                 string   `protobuf:"bytes,2,opt,name=From,proto3" json:"From,omitempty"`
+	To                   string   `protobuf:"bytes,3,opt,name=To,proto3" json:"To,omitempty"`
+	Content              string   `protobuf:"bytes,4,opt,name=Content,proto3" json:"Content,omitempty"`
+	Time                 int64    `protobuf:"varint,5,opt,name=Time,proto3" json:"Time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PrivateMsg) Reset()         { *m = PrivateMsg{} }
+func (m *PrivateMsg) String() string { return proto.CompactTextString(m) }
+func (*PrivateMsg) ProtoMessage()    {}
+func (*PrivateMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{28}
+}
+
+func (m *PrivateMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrivateMsg.Unmarshal(m, b)
+}
+func (m *PrivateMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrivateMsg.Marshal(b, m, deterministic)
+}
+func (m *PrivateMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrivateMsg.Merge(m, src)
+}
+func (m *PrivateMsg) XXX_Size() int {
+	return xxx_messageInfo_PrivateMsg.Size(m)
+}
+func (m *PrivateMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrivateMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrivateMsg proto.InternalMessageInfo
+
+func (m *PrivateMsg) GetID() int64 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *PrivateMsg) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *PrivateMsg) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *PrivateMsg) GetContent() string {
+	if m != nil {
+		return m.Content
+	}
+	return ""
+}
+
+func (m *PrivateMsg) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+type InboxUnread struct {
+	From                 string   `protobuf:"bytes,1,opt,name=From,proto3" json:"From,omitempty"`
+	Count                int32    `protobuf:"varint,2,opt,name=Count,proto3" json:"Count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InboxUnread) Reset()         { *m = InboxUnread{} }
+func (m *InboxUnread) String() string { return proto.CompactTextString(m) }
+func (*InboxUnread) ProtoMessage()    {}
+func (*InboxUnread) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{29}
+}
+
+func (m *InboxUnread) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InboxUnread.Unmarshal(m, b)
+}
+func (m *InboxUnread) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InboxUnread.Marshal(b, m, deterministic)
+}
+func (m *InboxUnread) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InboxUnread.Merge(m, src)
+}
+func (m *InboxUnread) XXX_Size() int {
+	return xxx_messageInfo_InboxUnread.Size(m)
+}
+func (m *InboxUnread) XXX_DiscardUnknown() {
+	xxx_messageInfo_InboxUnread.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InboxUnread proto.InternalMessageInfo
+
+func (m *InboxUnread) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *InboxUnread) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+// 登录后下发收件箱中的未读私聊, 客户端处理后需以REQ_INBOX_READ确认, 否则下次登录重新下发
+type CSNtfInbox struct {
+	Msgs                 []*PrivateMsg  `protobuf:"bytes,1,rep,name=Msgs,proto3" json:"Msgs,omitempty"`
+	Unread               []*InboxUnread `protobuf:"bytes,2,rep,name=Unread,proto3" json:"Unread,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *CSNtfInbox) Reset()         { *m = CSNtfInbox{} }
+func (m *CSNtfInbox) String() string { return proto.CompactTextString(m) }
+func (*CSNtfInbox) ProtoMessage()    {}
+func (*CSNtfInbox) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{30}
+}
+
+func (m *CSNtfInbox) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSNtfInbox.Unmarshal(m, b)
+}
+func (m *CSNtfInbox) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSNtfInbox.Marshal(b, m, deterministic)
+}
+func (m *CSNtfInbox) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSNtfInbox.Merge(m, src)
+}
+func (m *CSNtfInbox) XXX_Size() int {
+	return xxx_messageInfo_CSNtfInbox.Size(m)
+}
+func (m *CSNtfInbox) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSNtfInbox.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSNtfInbox proto.InternalMessageInfo
+
+func (m *CSNtfInbox) GetMsgs() []*PrivateMsg {
+	if m != nil {
+		return m.Msgs
+	}
+	return nil
+}
+
+func (m *CSNtfInbox) GetUnread() []*InboxUnread {
+	if m != nil {
+		return m.Unread
+	}
+	return nil
+}
+
+type CSReqInboxRead struct {
+	UpToID               int64    `protobuf:"varint,1,opt,name=UpToID,proto3" json:"UpToID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSReqInboxRead) Reset()         { *m = CSReqInboxRead{} }
+func (m *CSReqInboxRead) String() string { return proto.CompactTextString(m) }
+func (*CSReqInboxRead) ProtoMessage()    {}
+func (*CSReqInboxRead) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{31}
+}
+
+func (m *CSReqInboxRead) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSReqInboxRead.Unmarshal(m, b)
+}
+func (m *CSReqInboxRead) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSReqInboxRead.Marshal(b, m, deterministic)
+}
+func (m *CSReqInboxRead) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSReqInboxRead.Merge(m, src)
+}
+func (m *CSReqInboxRead) XXX_Size() int {
+	return xxx_messageInfo_CSReqInboxRead.Size(m)
+}
+func (m *CSReqInboxRead) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSReqInboxRead.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSReqInboxRead proto.InternalMessageInfo
+
+func (m *CSReqInboxRead) GetUpToID() int64 {
+	if m != nil {
+		return m.UpToID
+	}
+	return 0
+}
+
+type CSRspInboxRead struct {
+	Unread               int32    `protobuf:"varint,1,opt,name=Unread,proto3" json:"Unread,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSRspInboxRead) Reset()         { *m = CSRspInboxRead{} }
+func (m *CSRspInboxRead) String() string { return proto.CompactTextString(m) }
+func (*CSRspInboxRead) ProtoMessage()    {}
+func (*CSRspInboxRead) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{32}
+}
+
+func (m *CSRspInboxRead) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSRspInboxRead.Unmarshal(m, b)
+}
+func (m *CSRspInboxRead) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSRspInboxRead.Marshal(b, m, deterministic)
+}
+func (m *CSRspInboxRead) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSRspInboxRead.Merge(m, src)
+}
+func (m *CSRspInboxRead) XXX_Size() int {
+	return xxx_messageInfo_CSRspInboxRead.Size(m)
+}
+func (m *CSRspInboxRead) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSRspInboxRead.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSRspInboxRead proto.InternalMessageInfo
+
+func (m *CSRspInboxRead) GetUnread() int32 {
+	if m != nil {
+		return m.Unread
+	}
+	return 0
+}
+
+// 在当前房间任免管理员, 需要房主权限; 设为ROLE_OWNER时转让房间, 原房主成为管理员
+type CSReqSetRole struct {
+	Username             string    `protobuf:"bytes,1,opt,name=Username,proto3" json:"Username,omitempty"`
+	Role                 ROOM_ROLE `protobuf:"varint,2,opt,name=Role,proto3,enum=pb.ROOM_ROLE" json:"Role,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CSReqSetRole) Reset()         { *m = CSReqSetRole{} }
+func (m *CSReqSetRole) String() string { return proto.CompactTextString(m) }
+func (*CSReqSetRole) ProtoMessage()    {}
+func (*CSReqSetRole) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{33}
+}
+
+func (m *CSReqSetRole) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSReqSetRole.Unmarshal(m, b)
+}
+func (m *CSReqSetRole) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSReqSetRole.Marshal(b, m, deterministic)
+}
+func (m *CSReqSetRole) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSReqSetRole.Merge(m, src)
+}
+func (m *CSReqSetRole) XXX_Size() int {
+	return xxx_messageInfo_CSReqSetRole.Size(m)
+}
+func (m *CSReqSetRole) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSReqSetRole.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSReqSetRole proto.InternalMessageInfo
+
+func (m *CSReqSetRole) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *CSReqSetRole) GetRole() ROOM_ROLE {
+	if m != nil {
+		return m.Role
+	}
+	return ROOM_ROLE_ROLE_MEMBER
+}
+
+type CSRspSetRole struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSRspSetRole) Reset()         { *m = CSRspSetRole{} }
+func (m *CSRspSetRole) String() string { return proto.CompactTextString(m) }
+func (*CSRspSetRole) ProtoMessage()    {}
+func (*CSRspSetRole) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{34}
+}
+
+func (m *CSRspSetRole) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSRspSetRole.Unmarshal(m, b)
+}
+func (m *CSRspSetRole) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSRspSetRole.Marshal(b, m, deterministic)
+}
+func (m *CSRspSetRole) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSRspSetRole.Merge(m, src)
+}
+func (m *CSRspSetRole) XXX_Size() int {
+	return xxx_messageInfo_CSRspSetRole.Size(m)
+}
+func (m *CSRspSetRole) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSRspSetRole.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSRspSetRole proto.InternalMessageInfo
+
+// 在当前房间禁言, 对方可在其他房间发言
+type CSReqRoomMute struct {
+	Username             string   `protobuf:"bytes,1,opt,name=Username,proto3" json:"Username,omitempty"`
+	Seconds              int64    `protobuf:"varint,2,opt,name=Seconds,proto3" json:"Seconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSReqRoomMute) Reset()         { *m = CSReqRoomMute{} }
+func (m *CSReqRoomMute) String() string { return proto.CompactTextString(m) }
+func (*CSReqRoomMute) ProtoMessage()    {}
+func (*CSReqRoomMute) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{35}
+}
+
+func (m *CSReqRoomMute) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSReqRoomMute.Unmarshal(m, b)
+}
+func (m *CSReqRoomMute) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSReqRoomMute.Marshal(b, m, deterministic)
+}
+func (m *CSReqRoomMute) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSReqRoomMute.Merge(m, src)
+}
+func (m *CSReqRoomMute) XXX_Size() int {
+	return xxx_messageInfo_CSReqRoomMute.Size(m)
+}
+func (m *CSReqRoomMute) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSReqRoomMute.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSReqRoomMute proto.InternalMessageInfo
+
+func (m *CSReqRoomMute) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *CSReqRoomMute) GetSeconds() int64 {
+	if m != nil {
+		return m.Seconds
+	}
+	return 0
+}
+
+type CSRspRoomMute struct {
+	Until                int64    `protobuf:"varint,1,opt,name=Until,proto3" json:"Until,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSRspRoomMute) Reset()         { *m = CSRspRoomMute{} }
+func (m *CSRspRoomMute) String() string { return proto.CompactTextString(m) }
+func (*CSRspRoomMute) ProtoMessage()    {}
+func (*CSRspRoomMute) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{36}
+}
+
+func (m *CSRspRoomMute) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSRspRoomMute.Unmarshal(m, b)
+}
+func (m *CSRspRoomMute) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSRspRoomMute.Marshal(b, m, deterministic)
+}
+func (m *CSRspRoomMute) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSRspRoomMute.Merge(m, src)
+}
+func (m *CSRspRoomMute) XXX_Size() int {
+	return xxx_messageInfo_CSRspRoomMute.Size(m)
+}
+func (m *CSRspRoomMute) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSRspRoomMute.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSRspRoomMute proto.InternalMessageInfo
+
+func (m *CSRspRoomMute) GetUntil() int64 {
+	if m != nil {
+		return m.Until
+	}
+	return 0
+}
+
+// 移出当前房间, 对方保持在线, 一段时间内不能重新加入
+type CSReqRoomKick struct {
+	Username             string   `protobuf:"bytes,1,opt,name=Username,proto3" json:"Username,omitempty"`
+	Reason               string   `protobuf:"bytes,2,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSReqRoomKick) Reset()         { *m = CSReqRoomKick{} }
+func (m *CSReqRoomKick) String() string { return proto.CompactTextString(m) }
+func (*CSReqRoomKick) ProtoMessage()    {}
+func (*CSReqRoomKick) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{37}
+}
+
+func (m *CSReqRoomKick) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSReqRoomKick.Unmarshal(m, b)
+}
+func (m *CSReqRoomKick) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSReqRoomKick.Marshal(b, m, deterministic)
+}
+func (m *CSReqRoomKick) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSReqRoomKick.Merge(m, src)
+}
+func (m *CSReqRoomKick) XXX_Size() int {
+	return xxx_messageInfo_CSReqRoomKick.Size(m)
+}
+func (m *CSReqRoomKick) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSReqRoomKick.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSReqRoomKick proto.InternalMessageInfo
+
+func (m *CSReqRoomKick) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *CSReqRoomKick) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type CSRspRoomKick struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSRspRoomKick) Reset()         { *m = CSRspRoomKick{} }
+func (m *CSRspRoomKick) String() string { return proto.CompactTextString(m) }
+func (*CSRspRoomKick) ProtoMessage()    {}
+func (*CSRspRoomKick) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{38}
+}
+
+func (m *CSRspRoomKick) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSRspRoomKick.Unmarshal(m, b)
+}
+func (m *CSRspRoomKick) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSRspRoomKick.Marshal(b, m, deterministic)
+}
+func (m *CSRspRoomKick) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSRspRoomKick.Merge(m, src)
+}
+func (m *CSRspRoomKick) XXX_Size() int {
+	return xxx_messageInfo_CSRspRoomKick.Size(m)
+}
+func (m *CSRspRoomKick) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSRspRoomKick.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSRspRoomKick proto.InternalMessageInfo
+
+// 封禁用户名或IP, 需要ROLE_ADMIN; 在线的玩家会被踢下线
+type CSReqBan struct {
+	Username             string   `protobuf:"bytes,1,opt,name=Username,proto3" json:"Username,omitempty"`
+	IP                   string   `protobuf:"bytes,2,opt,name=IP,proto3" json:"IP,omitempty"`
+	WithIP               bool     `protobuf:"varint,3,opt,name=WithIP,proto3" json:"WithIP,omitempty"`
+	Seconds              int64    `protobuf:"varint,4,opt,name=Seconds,proto3" json:"Seconds,omitempty"`
+	Reason               string   `protobuf:"bytes,5,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	Lift                 bool     `protobuf:"varint,6,opt,name=Lift,proto3" json:"Lift,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSReqBan) Reset()         { *m = CSReqBan{} }
+func (m *CSReqBan) String() string { return proto.CompactTextString(m) }
+func (*CSReqBan) ProtoMessage()    {}
+func (*CSReqBan) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{39}
+}
+
+func (m *CSReqBan) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSReqBan.Unmarshal(m, b)
+}
+func (m *CSReqBan) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSReqBan.Marshal(b, m, deterministic)
+}
+func (m *CSReqBan) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSReqBan.Merge(m, src)
+}
+func (m *CSReqBan) XXX_Size() int {
+	return xxx_messageInfo_CSReqBan.Size(m)
+}
+func (m *CSReqBan) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSReqBan.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSReqBan proto.InternalMessageInfo
+
+func (m *CSReqBan) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *CSReqBan) GetIP() string {
+	if m != nil {
+		return m.IP
+	}
+	return ""
+}
+
+func (m *CSReqBan) GetWithIP() bool {
+	if m != nil {
+		return m.WithIP
+	}
+	return false
+}
+
+func (m *CSReqBan) GetSeconds() int64 {
+	if m != nil {
+		return m.Seconds
+	}
+	return 0
+}
+
+func (m *CSReqBan) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *CSReqBan) GetLift() bool {
+	if m != nil {
+		return m.Lift
+	}
+	return false
+}
+
+type CSRspBan struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSRspBan) Reset()         { *m = CSRspBan{} }
+func (m *CSRspBan) String() string { return proto.CompactTextString(m) }
+func (*CSRspBan) ProtoMessage()    {}
+func (*CSRspBan) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{40}
+}
+
+func (m *CSRspBan) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSRspBan.Unmarshal(m, b)
+}
+func (m *CSRspBan) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSRspBan.Marshal(b, m, deterministic)
+}
+func (m *CSRspBan) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSRspBan.Merge(m, src)
+}
+func (m *CSRspBan) XXX_Size() int {
+	return xxx_messageInfo_CSRspBan.Size(m)
+}
+func (m *CSRspBan) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSRspBan.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSRspBan proto.InternalMessageInfo
+
+type CSNtfRoomRole struct {
+	RoomID               int64     `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	Username             string    `protobuf:"bytes,2,opt,name=Username,proto3" json:"Username,omitempty"`
+	Role                 ROOM_ROLE `protobuf:"varint,3,opt,name=Role,proto3,enum=pb.ROOM_ROLE" json:"Role,omitempty"`
+	By                   string    `protobuf:"bytes,4,opt,name=By,proto3" json:"By,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CSNtfRoomRole) Reset()         { *m = CSNtfRoomRole{} }
+func (m *CSNtfRoomRole) String() string { return proto.CompactTextString(m) }
+func (*CSNtfRoomRole) ProtoMessage()    {}
+func (*CSNtfRoomRole) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{41}
+}
+
+func (m *CSNtfRoomRole) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSNtfRoomRole.Unmarshal(m, b)
+}
+func (m *CSNtfRoomRole) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSNtfRoomRole.Marshal(b, m, deterministic)
+}
+func (m *CSNtfRoomRole) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSNtfRoomRole.Merge(m, src)
+}
+func (m *CSNtfRoomRole) XXX_Size() int {
+	return xxx_messageInfo_CSNtfRoomRole.Size(m)
+}
+func (m *CSNtfRoomRole) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSNtfRoomRole.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSNtfRoomRole proto.InternalMessageInfo
+
+func (m *CSNtfRoomRole) GetRoomID() int64 {
+	if m != nil {
+		return m.RoomID
+	}
+	return 0
+}
+
+func (m *CSNtfRoomRole) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *CSNtfRoomRole) GetRole() ROOM_ROLE {
+	if m != nil {
+		return m.Role
+	}
+	return ROOM_ROLE_ROLE_MEMBER
+}
+
+func (m *CSNtfRoomRole) GetBy() string {
+	if m != nil {
+		return m.By
+	}
+	return ""
+}
+
+type CSNtfRoomMute struct {
+	RoomID               int64    `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	Username             string   `protobuf:"bytes,2,opt,name=Username,proto3" json:"Username,omitempty"`
+	Until                int64    `protobuf:"varint,3,opt,name=Until,proto3" json:"Until,omitempty"`
+	By                   string   `protobuf:"bytes,4,opt,name=By,proto3" json:"By,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSNtfRoomMute) Reset()         { *m = CSNtfRoomMute{} }
+func (m *CSNtfRoomMute) String() string { return proto.CompactTextString(m) }
+func (*CSNtfRoomMute) ProtoMessage()    {}
+func (*CSNtfRoomMute) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{42}
+}
+
+func (m *CSNtfRoomMute) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSNtfRoomMute.Unmarshal(m, b)
+}
+func (m *CSNtfRoomMute) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSNtfRoomMute.Marshal(b, m, deterministic)
+}
+func (m *CSNtfRoomMute) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSNtfRoomMute.Merge(m, src)
+}
+func (m *CSNtfRoomMute) XXX_Size() int {
+	return xxx_messageInfo_CSNtfRoomMute.Size(m)
+}
+func (m *CSNtfRoomMute) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSNtfRoomMute.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSNtfRoomMute proto.InternalMessageInfo
+
+func (m *CSNtfRoomMute) GetRoomID() int64 {
+	if m != nil {
+		return m.RoomID
+	}
+	return 0
+}
+
+func (m *CSNtfRoomMute) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *CSNtfRoomMute) GetUntil() int64 {
+	if m != nil {
+		return m.Until
+	}
+	return 0
+}
+
+func (m *CSNtfRoomMute) GetBy() string {
+	if m != nil {
+		return m.By
+	}
+	return ""
+}
+
+type CSNtfRoomKicked struct {
+	RoomID               int64    `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	By                   string   `protobuf:"bytes,2,opt,name=By,proto3" json:"By,omitempty"`
+	Reason               string   `protobuf:"bytes,3,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSNtfRoomKicked) Reset()         { *m = CSNtfRoomKicked{} }
+func (m *CSNtfRoomKicked) String() string { return proto.CompactTextString(m) }
+func (*CSNtfRoomKicked) ProtoMessage()    {}
+func (*CSNtfRoomKicked) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{43}
+}
+
+func (m *CSNtfRoomKicked) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSNtfRoomKicked.Unmarshal(m, b)
+}
+func (m *CSNtfRoomKicked) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSNtfRoomKicked.Marshal(b, m, deterministic)
+}
+func (m *CSNtfRoomKicked) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSNtfRoomKicked.Merge(m, src)
+}
+func (m *CSNtfRoomKicked) XXX_Size() int {
+	return xxx_messageInfo_CSNtfRoomKicked.Size(m)
+}
+func (m *CSNtfRoomKicked) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSNtfRoomKicked.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSNtfRoomKicked proto.InternalMessageInfo
+
+func (m *CSNtfRoomKicked) GetRoomID() int64 {
+	if m != nil {
+		return m.RoomID
+	}
+	return 0
+}
+
+func (m *CSNtfRoomKicked) GetBy() string {
+	if m != nil {
+		return m.By
+	}
+	return ""
+}
+
+func (m *CSNtfRoomKicked) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type CSNtfRename struct {
+	RoomID               int64    `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	OldName              string   `protobuf:"bytes,2,opt,name=OldName,proto3" json:"OldName,omitempty"`
+	NewName              string   `protobuf:"bytes,3,opt,name=NewName,proto3" json:"NewName,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSNtfRename) Reset()         { *m = CSNtfRename{} }
+func (m *CSNtfRename) String() string { return proto.CompactTextString(m) }
+func (*CSNtfRename) ProtoMessage()    {}
+func (*CSNtfRename) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{44}
+}
+
+func (m *CSNtfRename) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSNtfRename.Unmarshal(m, b)
+}
+func (m *CSNtfRename) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSNtfRename.Marshal(b, m, deterministic)
+}
+func (m *CSNtfRename) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSNtfRename.Merge(m, src)
+}
+func (m *CSNtfRename) XXX_Size() int {
+	return xxx_messageInfo_CSNtfRename.Size(m)
+}
+func (m *CSNtfRename) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSNtfRename.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSNtfRename proto.InternalMessageInfo
+
+func (m *CSNtfRename) GetRoomID() int64 {
+	if m != nil {
+		return m.RoomID
+	}
+	return 0
+}
+
+func (m *CSNtfRename) GetOldName() string {
+	if m != nil {
+		return m.OldName
+	}
+	return ""
+}
+
+func (m *CSNtfRename) GetNewName() string {
+	if m != nil {
+		return m.NewName
+	}
+	return ""
+}
+
+// 密钥交换, 以明文传输, 完成后双方切换到会话密钥
+type CSReqHandshake struct {
+	PublicKey            []byte   `protobuf:"bytes,1,opt,name=PublicKey,proto3" json:"PublicKey,omitempty"`
+	Nonce                []byte   `protobuf:"bytes,2,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSReqHandshake) Reset()         { *m = CSReqHandshake{} }
+func (m *CSReqHandshake) String() string { return proto.CompactTextString(m) }
+func (*CSReqHandshake) ProtoMessage()    {}
+func (*CSReqHandshake) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{45}
+}
+
+func (m *CSReqHandshake) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSReqHandshake.Unmarshal(m, b)
+}
+func (m *CSReqHandshake) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSReqHandshake.Marshal(b, m, deterministic)
+}
+func (m *CSReqHandshake) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSReqHandshake.Merge(m, src)
+}
+func (m *CSReqHandshake) XXX_Size() int {
+	return xxx_messageInfo_CSReqHandshake.Size(m)
+}
+func (m *CSReqHandshake) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSReqHandshake.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSReqHandshake proto.InternalMessageInfo
+
+func (m *CSReqHandshake) GetPublicKey() []byte {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+func (m *CSReqHandshake) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
+type CSRspHandshake struct {
+	PublicKey            []byte   `protobuf:"bytes,1,opt,name=PublicKey,proto3" json:"PublicKey,omitempty"`
+	Nonce                []byte   `protobuf:"bytes,2,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
+	Signature            []byte   `protobuf:"bytes,3,opt,name=Signature,proto3" json:"Signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSRspHandshake) Reset()         { *m = CSRspHandshake{} }
+func (m *CSRspHandshake) String() string { return proto.CompactTextString(m) }
+func (*CSRspHandshake) ProtoMessage()    {}
+func (*CSRspHandshake) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{46}
+}
+
+func (m *CSRspHandshake) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSRspHandshake.Unmarshal(m, b)
+}
+func (m *CSRspHandshake) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSRspHandshake.Marshal(b, m, deterministic)
+}
+func (m *CSRspHandshake) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSRspHandshake.Merge(m, src)
+}
+func (m *CSRspHandshake) XXX_Size() int {
+	return xxx_messageInfo_CSRspHandshake.Size(m)
+}
+func (m *CSRspHandshake) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSRspHandshake.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSRspHandshake proto.InternalMessageInfo
+
+func (m *CSRspHandshake) GetPublicKey() []byte {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+func (m *CSRspHandshake) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
+func (m *CSRspHandshake) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type CSNtfKick struct {
+	Reason               KICK_REASON `protobuf:"varint,1,opt,name=Reason,proto3,enum=pb.KICK_REASON" json:"Reason,omitempty"`
+	Msg                  string      `protobuf:"bytes,2,opt,name=Msg,proto3" json:"Msg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *CSNtfKick) Reset()         { *m = CSNtfKick{} }
+func (m *CSNtfKick) String() string { return proto.CompactTextString(m) }
+func (*CSNtfKick) ProtoMessage()    {}
+func (*CSNtfKick) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{47}
+}
+
+func (m *CSNtfKick) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSNtfKick.Unmarshal(m, b)
+}
+func (m *CSNtfKick) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSNtfKick.Marshal(b, m, deterministic)
+}
+func (m *CSNtfKick) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSNtfKick.Merge(m, src)
+}
+func (m *CSNtfKick) XXX_Size() int {
+	return xxx_messageInfo_CSNtfKick.Size(m)
+}
+func (m *CSNtfKick) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSNtfKick.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSNtfKick proto.InternalMessageInfo
+
+func (m *CSNtfKick) GetReason() KICK_REASON {
+	if m != nil {
+		return m.Reason
+	}
+	return KICK_REASON_KICK_UNKNOWN
+}
+
+func (m *CSNtfKick) GetMsg() string {
+	if m != nil {
+		return m.Msg
+	}
+	return ""
+}
+
+type CSNtfRoomMemberOnline struct {
+	RoomID               int64    `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	Username             string   `protobuf:"bytes,2,opt,name=Username,proto3" json:"Username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSNtfRoomMemberOnline) Reset()         { *m = CSNtfRoomMemberOnline{} }
+func (m *CSNtfRoomMemberOnline) String() string { return proto.CompactTextString(m) }
+func (*CSNtfRoomMemberOnline) ProtoMessage()    {}
+func (*CSNtfRoomMemberOnline) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{48}
+}
+
+func (m *CSNtfRoomMemberOnline) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSNtfRoomMemberOnline.Unmarshal(m, b)
+}
+func (m *CSNtfRoomMemberOnline) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSNtfRoomMemberOnline.Marshal(b, m, deterministic)
+}
+func (m *CSNtfRoomMemberOnline) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSNtfRoomMemberOnline.Merge(m, src)
+}
+func (m *CSNtfRoomMemberOnline) XXX_Size() int {
+	return xxx_messageInfo_CSNtfRoomMemberOnline.Size(m)
+}
+func (m *CSNtfRoomMemberOnline) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSNtfRoomMemberOnline.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSNtfRoomMemberOnline proto.InternalMessageInfo
+
+func (m *CSNtfRoomMemberOnline) GetRoomID() int64 {
+	if m != nil {
+		return m.RoomID
+	}
+	return 0
+}
+
+func (m *CSNtfRoomMemberOnline) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+type CSNtfRoomChat struct {
+	Username             string   `protobuf:"bytes,1,opt,name=Username,proto3" json:"Username,omitempty"`
+	Content              string   `protobuf:"bytes,2,opt,name=Content,proto3" json:"Content,omitempty"`
+	Seq                  int64    `protobuf:"varint,3,opt,name=Seq,proto3" json:"Seq,omitempty"`
+	MsgID                int64    `protobuf:"varint,4,opt,name=MsgID,proto3" json:"MsgID,omitempty"`
+	Time                 int64    `protobuf:"varint,5,opt,name=Time,proto3" json:"Time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSNtfRoomChat) Reset()         { *m = CSNtfRoomChat{} }
+func (m *CSNtfRoomChat) String() string { return proto.CompactTextString(m) }
+func (*CSNtfRoomChat) ProtoMessage()    {}
+func (*CSNtfRoomChat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{49}
+}
+
+func (m *CSNtfRoomChat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSNtfRoomChat.Unmarshal(m, b)
+}
+func (m *CSNtfRoomChat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSNtfRoomChat.Marshal(b, m, deterministic)
+}
+func (m *CSNtfRoomChat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSNtfRoomChat.Merge(m, src)
+}
+func (m *CSNtfRoomChat) XXX_Size() int {
+	return xxx_messageInfo_CSNtfRoomChat.Size(m)
+}
+func (m *CSNtfRoomChat) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSNtfRoomChat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSNtfRoomChat proto.InternalMessageInfo
+
+func (m *CSNtfRoomChat) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *CSNtfRoomChat) GetContent() string {
+	if m != nil {
+		return m.Content
+	}
+	return ""
+}
+
+func (m *CSNtfRoomChat) GetSeq() int64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *CSNtfRoomChat) GetMsgID() int64 {
+	if m != nil {
+		return m.MsgID
+	}
+	return 0
+}
+
+func (m *CSNtfRoomChat) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+type CSNtfRoomMemberLeave struct {
+	RoomID               int64    `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	Username             string   `protobuf:"bytes,2,opt,name=Username,proto3" json:"Username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSNtfRoomMemberLeave) Reset()         { *m = CSNtfRoomMemberLeave{} }
+func (m *CSNtfRoomMemberLeave) String() string { return proto.CompactTextString(m) }
+func (*CSNtfRoomMemberLeave) ProtoMessage()    {}
+func (*CSNtfRoomMemberLeave) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{50}
+}
+
+func (m *CSNtfRoomMemberLeave) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSNtfRoomMemberLeave.Unmarshal(m, b)
+}
+func (m *CSNtfRoomMemberLeave) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSNtfRoomMemberLeave.Marshal(b, m, deterministic)
+}
+func (m *CSNtfRoomMemberLeave) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSNtfRoomMemberLeave.Merge(m, src)
+}
+func (m *CSNtfRoomMemberLeave) XXX_Size() int {
+	return xxx_messageInfo_CSNtfRoomMemberLeave.Size(m)
+}
+func (m *CSNtfRoomMemberLeave) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSNtfRoomMemberLeave.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSNtfRoomMemberLeave proto.InternalMessageInfo
+
+func (m *CSNtfRoomMemberLeave) GetRoomID() int64 {
+	if m != nil {
+		return m.RoomID
+	}
+	return 0
+}
+
+func (m *CSNtfRoomMemberLeave) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+// 成员断线, 与主动离开房间区分
+type CSNtfRoomMemberOffline struct {
+	RoomID               int64    `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	Username             string   `protobuf:"bytes,2,opt,name=Username,proto3" json:"Username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSNtfRoomMemberOffline) Reset()         { *m = CSNtfRoomMemberOffline{} }
+func (m *CSNtfRoomMemberOffline) String() string { return proto.CompactTextString(m) }
+func (*CSNtfRoomMemberOffline) ProtoMessage()    {}
+func (*CSNtfRoomMemberOffline) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{51}
+}
+
+func (m *CSNtfRoomMemberOffline) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSNtfRoomMemberOffline.Unmarshal(m, b)
+}
+func (m *CSNtfRoomMemberOffline) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSNtfRoomMemberOffline.Marshal(b, m, deterministic)
+}
+func (m *CSNtfRoomMemberOffline) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSNtfRoomMemberOffline.Merge(m, src)
+}
+func (m *CSNtfRoomMemberOffline) XXX_Size() int {
+	return xxx_messageInfo_CSNtfRoomMemberOffline.Size(m)
+}
+func (m *CSNtfRoomMemberOffline) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSNtfRoomMemberOffline.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSNtfRoomMemberOffline proto.InternalMessageInfo
+
+func (m *CSNtfRoomMemberOffline) GetRoomID() int64 {
+	if m != nil {
+		return m.RoomID
+	}
+	return 0
+}
+
+func (m *CSNtfRoomMemberOffline) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+// 房间成员变化, 用于维护成员列表
+// Snapshot为true时Joined为完整列表, 客户端应替换本地列表; 否则按Joined、Left增量更新
+type CSNtfRoomPresence struct {
+	RoomID               int64         `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	Snapshot             bool          `protobuf:"varint,2,opt,name=Snapshot,proto3" json:"Snapshot,omitempty"`
+	Joined               []*RoomMember `protobuf:"bytes,3,rep,name=Joined,proto3" json:"Joined,omitempty"`
+	Left                 []string      `protobuf:"bytes,4,rep,name=Left,proto3" json:"Left,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *CSNtfRoomPresence) Reset()         { *m = CSNtfRoomPresence{} }
+func (m *CSNtfRoomPresence) String() string { return proto.CompactTextString(m) }
+func (*CSNtfRoomPresence) ProtoMessage()    {}
+func (*CSNtfRoomPresence) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{52}
+}
+
+func (m *CSNtfRoomPresence) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSNtfRoomPresence.Unmarshal(m, b)
+}
+func (m *CSNtfRoomPresence) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSNtfRoomPresence.Marshal(b, m, deterministic)
+}
+func (m *CSNtfRoomPresence) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSNtfRoomPresence.Merge(m, src)
+}
+func (m *CSNtfRoomPresence) XXX_Size() int {
+	return xxx_messageInfo_CSNtfRoomPresence.Size(m)
+}
+func (m *CSNtfRoomPresence) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSNtfRoomPresence.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSNtfRoomPresence proto.InternalMessageInfo
+
+func (m *CSNtfRoomPresence) GetRoomID() int64 {
+	if m != nil {
+		return m.RoomID
+	}
+	return 0
+}
+
+func (m *CSNtfRoomPresence) GetSnapshot() bool {
+	if m != nil {
+		return m.Snapshot
+	}
+	return false
+}
+
+func (m *CSNtfRoomPresence) GetJoined() []*RoomMember {
+	if m != nil {
+		return m.Joined
+	}
+	return nil
+}
+
+func (m *CSNtfRoomPresence) GetLeft() []string {
+	if m != nil {
+		return m.Left
+	}
+	return nil
+}
+
+// 序号小于NextSeq且尚未收到的消息不会再重发
+type CSNtfResync struct {
+	Missed               int64    `protobuf:"varint,1,opt,name=Missed,proto3" json:"Missed,omitempty"`
+	NextSeq              int64    `protobuf:"varint,2,opt,name=NextSeq,proto3" json:"NextSeq,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSNtfResync) Reset()         { *m = CSNtfResync{} }
+func (m *CSNtfResync) String() string { return proto.CompactTextString(m) }
+func (*CSNtfResync) ProtoMessage()    {}
+func (*CSNtfResync) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{53}
+}
+
+func (m *CSNtfResync) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSNtfResync.Unmarshal(m, b)
+}
+func (m *CSNtfResync) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSNtfResync.Marshal(b, m, deterministic)
+}
+func (m *CSNtfResync) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSNtfResync.Merge(m, src)
+}
+func (m *CSNtfResync) XXX_Size() int {
+	return xxx_messageInfo_CSNtfResync.Size(m)
+}
+func (m *CSNtfResync) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSNtfResync.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSNtfResync proto.InternalMessageInfo
+
+func (m *CSNtfResync) GetMissed() int64 {
+	if m != nil {
+		return m.Missed
+	}
+	return 0
+}
+
+func (m *CSNtfResync) GetNextSeq() int64 {
+	if m != nil {
+		return m.NextSeq
+	}
+	return 0
+}
+
+type CSNtfRoomClosed struct {
+	RoomID               int64    `protobuf:"varint,1,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CSNtfRoomClosed) Reset()         { *m = CSNtfRoomClosed{} }
+func (m *CSNtfRoomClosed) String() string { return proto.CompactTextString(m) }
+func (*CSNtfRoomClosed) ProtoMessage()    {}
+func (*CSNtfRoomClosed) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{54}
+}
+
+func (m *CSNtfRoomClosed) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CSNtfRoomClosed.Unmarshal(m, b)
+}
+func (m *CSNtfRoomClosed) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CSNtfRoomClosed.Marshal(b, m, deterministic)
+}
+func (m *CSNtfRoomClosed) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CSNtfRoomClosed.Merge(m, src)
+}
+func (m *CSNtfRoomClosed) XXX_Size() int {
+	return xxx_messageInfo_CSNtfRoomClosed.Size(m)
+}
+func (m *CSNtfRoomClosed) XXX_DiscardUnknown() {
+	xxx_messageInfo_CSNtfRoomClosed.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CSNtfRoomClosed proto.InternalMessageInfo
+
+func (m *CSNtfRoomClosed) GetRoomID() int64 {
+	if m != nil {
+		return m.RoomID
+	}
+	return 0
+}
+
+type HistoryChat struct {
+	From                 string   `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	Dt                   string   `protobuf:"bytes,2,opt,name=dt,proto3" json:"dt,omitempty"`
+	Content              string   `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Seq                  int64    `protobuf:"varint,4,opt,name=seq,proto3" json:"seq,omitempty"`
+	Id                   int64    `protobuf:"varint,5,opt,name=id,proto3" json:"id,omitempty"`
+	Time                 int64    `protobuf:"varint,6,opt,name=time,proto3" json:"time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HistoryChat) Reset()         { *m = HistoryChat{} }
+func (m *HistoryChat) String() string { return proto.CompactTextString(m) }
+func (*HistoryChat) ProtoMessage()    {}
+func (*HistoryChat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af7bf51985781725, []int{55}
+}
+
 func (m *HistoryChat) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_HistoryChat.Unmarshal(m, b)
 }
@@ -1258,9 +3557,31 @@ func (m *HistoryChat) GetContent() string {
 	return ""
 }
 
+func (m *HistoryChat) GetSeq() int64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *HistoryChat) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *HistoryChat) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
 type CSNtfHistoryMsg struct {
 	History              []*HistoryChat `protobuf:"bytes,1,rep,name=History,proto3" json:"History,omitempty"`
 	RoomID               int64          `protobuf:"varint,2,opt,name=RoomID,proto3" json:"RoomID,omitempty"`
+	More                 bool           `protobuf:"varint,3,opt,name=More,proto3" json:"More,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
 	XXX_unrecognized     []byte         `json:"-"`
 	XXX_sizecache        int32          `json:"-"`
@@ -1270,7 +3591,7 @@ func (m *CSNtfHistoryMsg) Reset()         { *m = CSNtfHistoryMsg{} }
 func (m *CSNtfHistoryMsg) String() string { return proto.CompactTextString(m) }
 func (*CSNtfHistoryMsg) ProtoMessage()    {}
 func (*CSNtfHistoryMsg) Descriptor() ([]byte, []int) {
-	return fileDescriptor_af7bf51985781725, []int{24}
+	return fileDescriptor_af7bf51985781725, []int{56}
 }
 
 func (m *CSNtfHistoryMsg) XXX_Unmarshal(b []byte) error {
@@ -1305,9 +3626,18 @@ func (m *CSNtfHistoryMsg) GetRoomID() int64 {
 	return 0
 }
 
+func (m *CSNtfHistoryMsg) GetMore() bool {
+	if m != nil {
+		return m.More
+	}
+	return false
+}
+
 type CSNtfChat struct {
 	From                 string   `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
 	Content              string   `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	MsgID                int64    `protobuf:"varint,3,opt,name=MsgID,proto3" json:"MsgID,omitempty"`
+	Time                 int64    `protobuf:"varint,4,opt,name=Time,proto3" json:"Time,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1317,7 +3647,7 @@ func (m *CSNtfChat) Reset()         { *m = CSNtfChat{} }
 func (m *CSNtfChat) String() string { return proto.CompactTextString(m) }
 func (*CSNtfChat) ProtoMessage()    {}
 func (*CSNtfChat) Descriptor() ([]byte, []int) {
-	return fileDescriptor_af7bf51985781725, []int{25}
+	return fileDescriptor_af7bf51985781725, []int{57}
 }
 
 func (m *CSNtfChat) XXX_Unmarshal(b []byte) error {
@@ -1352,8 +3682,25 @@ func (m *CSNtfChat) GetContent() string {
 	return ""
 }
 
+func (m *CSNtfChat) GetMsgID() int64 {
+	if m != nil {
+		return m.MsgID
+	}
+	return 0
+}
+
+func (m *CSNtfChat) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("pb.ERROR_CODE", ERROR_CODE_name, ERROR_CODE_value)
+	proto.RegisterEnum("pb.KICK_REASON", KICK_REASON_name, KICK_REASON_value)
+	proto.RegisterEnum("pb.ROOM_ROLE", ROOM_ROLE_name, ROOM_ROLE_value)
+	proto.RegisterEnum("pb.COMPRESS_CODEC", COMPRESS_CODEC_name, COMPRESS_CODEC_value)
 	proto.RegisterEnum("pb.CSMsgID", CSMsgID_name, CSMsgID_value)
 	proto.RegisterType((*CSHead)(nil), "pb.CSHead")
 	proto.RegisterType((*CSReqBody)(nil), "pb.CSReqBody")
@@ -1372,11 +3719,43 @@ func init() {
 	proto.RegisterType((*CSRspRoomList)(nil), "pb.CSRspRoomList")
 	proto.RegisterType((*CSReqJoinRoom)(nil), "pb.CSReqJoinRoom")
 	proto.RegisterType((*CSRspJoinRoom)(nil), "pb.CSRspJoinRoom")
+	proto.RegisterType((*CSReqLeaveRoom)(nil), "pb.CSReqLeaveRoom")
+	proto.RegisterType((*CSRspLeaveRoom)(nil), "pb.CSRspLeaveRoom")
+	proto.RegisterType((*RoomMember)(nil), "pb.RoomMember")
+	proto.RegisterType((*CSReqRoomMembers)(nil), "pb.CSReqRoomMembers")
+	proto.RegisterType((*CSRspRoomMembers)(nil), "pb.CSRspRoomMembers")
+	proto.RegisterType((*CSReqRoomHistory)(nil), "pb.CSReqRoomHistory")
+	proto.RegisterType((*CSRspRoomHistory)(nil), "pb.CSRspRoomHistory")
+	proto.RegisterType((*CSReqSearchHistory)(nil), "pb.CSReqSearchHistory")
+	proto.RegisterType((*CSRspSearchHistory)(nil), "pb.CSRspSearchHistory")
 	proto.RegisterType((*CSReqChat)(nil), "pb.CSReqChat")
 	proto.RegisterType((*CSRspChat)(nil), "pb.CSRspChat")
+	proto.RegisterType((*PrivateMsg)(nil), "pb.PrivateMsg")
+	proto.RegisterType((*InboxUnread)(nil), "pb.InboxUnread")
+	proto.RegisterType((*CSNtfInbox)(nil), "pb.CSNtfInbox")
+	proto.RegisterType((*CSReqInboxRead)(nil), "pb.CSReqInboxRead")
+	proto.RegisterType((*CSRspInboxRead)(nil), "pb.CSRspInboxRead")
+	proto.RegisterType((*CSReqSetRole)(nil), "pb.CSReqSetRole")
+	proto.RegisterType((*CSRspSetRole)(nil), "pb.CSRspSetRole")
+	proto.RegisterType((*CSReqRoomMute)(nil), "pb.CSReqRoomMute")
+	proto.RegisterType((*CSRspRoomMute)(nil), "pb.CSRspRoomMute")
+	proto.RegisterType((*CSReqRoomKick)(nil), "pb.CSReqRoomKick")
+	proto.RegisterType((*CSRspRoomKick)(nil), "pb.CSRspRoomKick")
+	proto.RegisterType((*CSReqBan)(nil), "pb.CSReqBan")
+	proto.RegisterType((*CSRspBan)(nil), "pb.CSRspBan")
+	proto.RegisterType((*CSNtfRoomRole)(nil), "pb.CSNtfRoomRole")
+	proto.RegisterType((*CSNtfRoomMute)(nil), "pb.CSNtfRoomMute")
+	proto.RegisterType((*CSNtfRoomKicked)(nil), "pb.CSNtfRoomKicked")
+	proto.RegisterType((*CSNtfRename)(nil), "pb.CSNtfRename")
+	proto.RegisterType((*CSReqHandshake)(nil), "pb.CSReqHandshake")
+	proto.RegisterType((*CSRspHandshake)(nil), "pb.CSRspHandshake")
 	proto.RegisterType((*CSNtfKick)(nil), "pb.CSNtfKick")
 	proto.RegisterType((*CSNtfRoomMemberOnline)(nil), "pb.CSNtfRoomMemberOnline")
 	proto.RegisterType((*CSNtfRoomChat)(nil), "pb.CSNtfRoomChat")
+	proto.RegisterType((*CSNtfRoomMemberLeave)(nil), "pb.CSNtfRoomMemberLeave")
+	proto.RegisterType((*CSNtfRoomMemberOffline)(nil), "pb.CSNtfRoomMemberOffline")
+	proto.RegisterType((*CSNtfRoomPresence)(nil), "pb.CSNtfRoomPresence")
+	proto.RegisterType((*CSNtfResync)(nil), "pb.CSNtfResync")
 	proto.RegisterType((*CSNtfRoomClosed)(nil), "pb.CSNtfRoomClosed")
 	proto.RegisterType((*HistoryChat)(nil), "pb.HistoryChat")
 	proto.RegisterType((*CSNtfHistoryMsg)(nil), "pb.CSNtfHistoryMsg")
@@ -1386,70 +3765,187 @@ func init() {
 func init() { proto.RegisterFile("cs.proto", fileDescriptor_af7bf51985781725) }
 
 var fileDescriptor_af7bf51985781725 = []byte{
-	// 1033 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xdb, 0x6e, 0xdb, 0x46,
-	0x10, 0x0d, 0x45, 0x5d, 0x47, 0x96, 0x4c, 0x6f, 0xdd, 0x40, 0xbd, 0x3c, 0xd8, 0x44, 0x5b, 0x28,
-	0x06, 0x6a, 0x14, 0x36, 0x50, 0xa0, 0x6f, 0x95, 0xe9, 0x4d, 0xa4, 0x58, 0x22, 0xdd, 0xa5, 0xfc,
-	0xd0, 0x27, 0x41, 0xb2, 0x56, 0x8e, 0x1a, 0x8b, 0xa4, 0x49, 0xba, 0x6d, 0xbe, 0xa6, 0xe8, 0x27,
-	0xf4, 0x2f, 0x7a, 0xfd, 0x84, 0xfe, 0x4b, 0xb1, 0xc3, 0xe5, 0x65, 0x19, 0x5b, 0x0d, 0xf2, 0xc6,
-	0x99, 0x39, 0x33, 0x3c, 0x9c, 0x33, 0x33, 0x12, 0x34, 0xaf, 0xa3, 0xe3, 0x20, 0xf4, 0x63, 0x9f,
-	0x54, 0x82, 0x85, 0xb9, 0x86, 0xba, 0xe5, 0x0e, 0xf9, 0x7c, 0x49, 0x0e, 0xa1, 0x36, 0x89, 0x6e,
-	0x46, 0xe7, 0x3d, 0xed, 0x40, 0xeb, 0x77, 0x4f, 0xda, 0xc7, 0xc1, 0xe2, 0xd8, 0x72, 0xd1, 0xc5,
-	0x92, 0x08, 0xe9, 0x41, 0xe3, 0xcc, 0x5f, 0xbe, 0x19, 0x73, 0xaf, 0x57, 0x39, 0xd0, 0xfa, 0x35,
-	0x96, 0x9a, 0xc4, 0x84, 0x9d, 0x51, 0x64, 0xf9, 0x9b, 0x20, 0xe4, 0x51, 0xc4, 0x97, 0x3d, 0xfd,
-	0x40, 0xeb, 0x37, 0x99, 0xe2, 0x33, 0xff, 0xad, 0x40, 0xcb, 0x72, 0x19, 0xbf, 0x13, 0x49, 0xc4,
-	0x00, 0xdd, 0xe5, 0x77, 0xf8, 0x32, 0x9d, 0x89, 0x47, 0xf2, 0x19, 0xd4, 0xc6, 0xfe, 0xcd, 0x3a,
-	0xa9, 0xdd, 0x3e, 0xe9, 0x26, 0x04, 0x18, 0xbf, 0x43, 0x2f, 0x4b, 0x82, 0xe4, 0x2b, 0x68, 0x0d,
-	0xf9, 0x3c, 0x8c, 0x17, 0x7c, 0x1e, 0xe3, 0x6b, 0xda, 0x27, 0x24, 0x43, 0x66, 0x11, 0x96, 0x83,
-	0xc8, 0xd7, 0xd0, 0x76, 0x79, 0x7c, 0x15, 0xf1, 0xd0, 0x9b, 0x6f, 0x78, 0xaf, 0x8a, 0x39, 0xfb,
-	0x59, 0x4e, 0x21, 0xc6, 0x8a, 0x40, 0xf2, 0x25, 0x34, 0x99, 0xef, 0x6f, 0xac, 0x57, 0xf3, 0xb8,
-	0x57, 0xc3, 0xa4, 0xbd, 0x2c, 0x29, 0x0d, 0xb0, 0x0c, 0x92, 0xc2, 0xc7, 0xeb, 0x28, 0xee, 0xd5,
-	0x1f, 0x80, 0x8b, 0x00, 0xcb, 0x20, 0x02, 0xfe, 0xd2, 0x5f, 0x7b, 0xc2, 0xee, 0x35, 0x4a, 0xf0,
-	0x34, 0xc0, 0x32, 0x08, 0x39, 0x84, 0x2a, 0x12, 0x69, 0x22, 0xb4, 0x93, 0x41, 0x91, 0x04, 0x86,
-	0xcc, 0x5f, 0x74, 0xec, 0x6f, 0x14, 0x3c, 0xd2, 0xdf, 0x3e, 0x34, 0x68, 0x18, 0x5a, 0xfe, 0x92,
-	0x63, 0x87, 0xbb, 0x49, 0x87, 0x29, 0x63, 0x0e, 0x9b, 0x59, 0xce, 0x39, 0x65, 0x69, 0x98, 0x3c,
-	0x85, 0x3a, 0x0d, 0xc3, 0x49, 0x74, 0x83, 0x0d, 0x6e, 0x31, 0x69, 0xe5, 0x0a, 0x55, 0x15, 0x85,
-	0xa2, 0xe0, 0x71, 0x85, 0x6a, 0x8a, 0x42, 0x51, 0xf0, 0x2e, 0x0a, 0xd5, 0x15, 0x85, 0xa2, 0xe0,
-	0x9d, 0x14, 0x52, 0x7b, 0x18, 0x05, 0xff, 0xa3, 0x50, 0xf3, 0x01, 0xf8, 0x16, 0x85, 0x5a, 0x25,
-	0xf8, 0x16, 0x85, 0x40, 0x51, 0x28, 0x0a, 0x0a, 0x0a, 0xfd, 0x86, 0x1b, 0x60, 0xc7, 0x2b, 0x54,
-	0xe8, 0x10, 0xaa, 0x17, 0xeb, 0xeb, 0xd7, 0x28, 0x51, 0x96, 0x60, 0xc7, 0x2b, 0xe1, 0x64, 0x18,
-	0x22, 0x14, 0x0c, 0x51, 0x7b, 0xc2, 0x37, 0x0b, 0x1e, 0x3a, 0xde, 0xed, 0xda, 0xe3, 0x72, 0x3b,
-	0x3e, 0xca, 0xe0, 0x65, 0x00, 0x7b, 0x2b, 0x45, 0xe9, 0x93, 0x5e, 0xfc, 0x12, 0x99, 0x5e, 0xea,
-	0xd3, 0x29, 0x00, 0x3e, 0xdf, 0xfa, 0x62, 0x95, 0x13, 0xad, 0x3f, 0x50, 0x13, 0x30, 0xc4, 0x0a,
-	0x30, 0x91, 0x34, 0x5c, 0x47, 0xb1, 0x1f, 0xbe, 0x11, 0x73, 0x53, 0x2b, 0x25, 0xe5, 0x21, 0x56,
-	0x80, 0x65, 0x3d, 0xab, 0x97, 0x5a, 0x50, 0xe8, 0x59, 0x1f, 0x20, 0x3f, 0x02, 0xe4, 0x63, 0x68,
-	0x66, 0x63, 0xa2, 0xe1, 0x6c, 0x66, 0xb6, 0xf9, 0x2d, 0x22, 0xe5, 0x30, 0x8a, 0x19, 0x16, 0xec,
-	0xe4, 0x3d, 0xd3, 0x99, 0xb4, 0x94, 0x0a, 0x95, 0x52, 0x05, 0x03, 0xba, 0xea, 0x19, 0x91, 0x9e,
-	0xc2, 0xd8, 0x9a, 0xc7, 0x60, 0x94, 0xcf, 0xc6, 0x56, 0x56, 0x04, 0xf1, 0xca, 0x10, 0x9b, 0xcf,
-	0xa0, 0xa3, 0x5c, 0x11, 0x71, 0x58, 0xaf, 0x7d, 0x2f, 0xe6, 0x5e, 0x2c, 0xf3, 0x53, 0xd3, 0xdc,
-	0x45, 0x68, 0x3e, 0xce, 0xe6, 0x69, 0x21, 0x17, 0xc7, 0xd4, 0x84, 0x9d, 0xc9, 0xfc, 0x67, 0x8c,
-	0xfb, 0xf7, 0xb2, 0x40, 0x8d, 0x29, 0x3e, 0xf3, 0xc7, 0x64, 0x00, 0x46, 0xde, 0xca, 0x27, 0x47,
-	0x60, 0x58, 0xf7, 0x61, 0xc8, 0xbd, 0x38, 0x99, 0x11, 0xfb, 0x7e, 0x23, 0x73, 0xde, 0xf2, 0x93,
-	0x2f, 0xa0, 0x3b, 0xf5, 0xe3, 0xf9, 0x6d, 0x8e, 0x4c, 0xee, 0x7e, 0xc9, 0x5b, 0x68, 0xb6, 0x5e,
-	0x6c, 0xb6, 0x24, 0x9b, 0x6f, 0x17, 0x31, 0xa1, 0x26, 0x9e, 0xa3, 0x9e, 0x76, 0xa0, 0xf7, 0xdb,
-	0x27, 0x3b, 0x42, 0xf1, 0x94, 0x19, 0x4b, 0x42, 0x26, 0x95, 0x5f, 0x98, 0x6d, 0xd6, 0x63, 0x52,
-	0x7e, 0x0a, 0x2d, 0x2b, 0xe4, 0xf3, 0x98, 0xdb, 0xfc, 0x27, 0x24, 0xd6, 0x64, 0xb9, 0x23, 0xeb,
-	0x5c, 0x5a, 0xc6, 0x1c, 0xc8, 0x9f, 0x9f, 0xed, 0x1d, 0x17, 0x62, 0xde, 0x97, 0x06, 0x24, 0xb5,
-	0xcd, 0xb6, 0xbc, 0xb0, 0xa8, 0x44, 0x5b, 0x2e, 0xb3, 0xd8, 0x54, 0xf3, 0x02, 0x3e, 0x7c, 0x70,
-	0x1b, 0xdf, 0x6b, 0x0e, 0xb1, 0x03, 0x85, 0xdd, 0xdc, 0x36, 0x60, 0xe2, 0x4b, 0x2c, 0xf9, 0x25,
-	0x49, 0x9d, 0xd4, 0x34, 0xf7, 0x60, 0xb7, 0xb4, 0xb1, 0xe6, 0x05, 0xb4, 0xe5, 0xfa, 0x61, 0x5d,
-	0x02, 0xd5, 0x55, 0xe8, 0x6f, 0x64, 0x4d, 0x7c, 0x26, 0x5d, 0xa8, 0x2c, 0xd3, 0x52, 0x95, 0xa5,
-	0xd2, 0x29, 0x5d, 0x9d, 0xcd, 0xa9, 0xac, 0x5f, 0x58, 0xe8, 0x67, 0xd0, 0x90, 0x96, 0x54, 0x78,
-	0x57, 0x28, 0x5c, 0x78, 0x25, 0x4b, 0xe3, 0x85, 0xc6, 0x54, 0x94, 0x99, 0xf9, 0x46, 0xb6, 0xf5,
-	0x51, 0x82, 0x05, 0x42, 0x15, 0x85, 0xd0, 0xd1, 0xe7, 0x00, 0xf9, 0xcf, 0x19, 0x69, 0x43, 0xc3,
-	0xbd, 0xb2, 0x2c, 0xea, 0xba, 0xc6, 0x13, 0x02, 0x50, 0x7f, 0x3e, 0x18, 0x8d, 0xe9, 0xb9, 0xa1,
-	0x1d, 0xfd, 0xaa, 0x43, 0x43, 0xfe, 0xb3, 0x21, 0x1d, 0x68, 0x31, 0xfa, 0xdd, 0xec, 0x8c, 0xbe,
-	0x18, 0xd9, 0xc6, 0x93, 0xd4, 0x1c, 0x3b, 0xc2, 0xd4, 0xc8, 0x1e, 0x74, 0x84, 0x39, 0xa4, 0x03,
-	0x36, 0x3d, 0xa3, 0x83, 0xa9, 0x51, 0x21, 0xfb, 0x60, 0x08, 0x97, 0x4b, 0xa7, 0xb3, 0x2b, 0x97,
-	0x32, 0x7b, 0x30, 0xa1, 0x86, 0x9e, 0x02, 0x99, 0xe3, 0x4c, 0x66, 0xd6, 0x70, 0x30, 0x35, 0xaa,
-	0x8a, 0x6b, 0x3c, 0x72, 0xa7, 0x46, 0x2d, 0x75, 0xbd, 0x74, 0x46, 0x36, 0xfa, 0x8d, 0x3a, 0xd9,
-	0x81, 0xa6, 0x70, 0x61, 0x4e, 0x03, 0x5f, 0xef, 0x5e, 0x4a, 0x36, 0xcb, 0xd4, 0x4c, 0xd8, 0x70,
-	0x4c, 0x77, 0x2f, 0x0b, 0x6c, 0x56, 0xc8, 0xc6, 0xbd, 0x54, 0xd9, 0xdc, 0xa4, 0xc0, 0x9c, 0xcd,
-	0x2b, 0xc5, 0x85, 0x6c, 0xd6, 0xa9, 0x2b, 0x67, 0xf3, 0x03, 0xb2, 0x71, 0x2f, 0x93, 0x9c, 0xd7,
-	0xa4, 0x0b, 0x2d, 0x7b, 0xfa, 0x5c, 0xb2, 0xf9, 0x5d, 0x23, 0x9f, 0xc0, 0x53, 0x61, 0x63, 0x8d,
-	0x09, 0x9d, 0x9c, 0x51, 0x36, 0x73, 0xec, 0xf1, 0xc8, 0xa6, 0xc6, 0x1f, 0x1a, 0x21, 0xd0, 0xc9,
-	0x82, 0x98, 0xff, 0xa7, 0x46, 0xf6, 0x61, 0x37, 0xf7, 0x8d, 0x1d, 0x97, 0x9e, 0x1b, 0x7f, 0x65,
-	0xde, 0xe1, 0xc8, 0x9d, 0x32, 0xe7, 0xfb, 0xd9, 0xc4, 0x7d, 0x61, 0xfc, 0xad, 0x91, 0x0e, 0x34,
-	0x85, 0x17, 0x53, 0xff, 0xd1, 0x16, 0x75, 0xfc, 0x8b, 0x7a, 0xfa, 0x5f, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x43, 0x3b, 0x77, 0x7c, 0xae, 0x0a, 0x00, 0x00,
+	// 2912 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x3a, 0x5d, 0x6f, 0xe3, 0xc6,
+	0xb5, 0x4b, 0x51, 0x92, 0xa5, 0x23, 0x59, 0xa6, 0x27, 0x8e, 0xa3, 0xbb, 0x77, 0x11, 0x6c, 0x88,
+	0x7b, 0x13, 0xc7, 0xb8, 0x37, 0x28, 0x76, 0x81, 0x16, 0x41, 0x0b, 0x14, 0xfa, 0xa0, 0xd7, 0x5c,
+	0x4b, 0xa4, 0x76, 0x28, 0x27, 0x71, 0x1e, 0x2a, 0xc8, 0x16, 0x6d, 0xab, 0xb6, 0x45, 0x2d, 0x49,
+	0x6f, 0x62, 0xa0, 0x2f, 0x41, 0xd1, 0x97, 0x02, 0x7d, 0xeb, 0x5f, 0xe8, 0x7f, 0x69, 0x9b, 0x7e,
+	0x37, 0x6d, 0xd1, 0x3f, 0xd2, 0x87, 0x3e, 0x15, 0x73, 0xe6, 0x83, 0x43, 0x59, 0xb2, 0xb7, 0xdb,
+	0x27, 0xf3, 0x7c, 0xcd, 0x9c, 0x73, 0xe6, 0x7c, 0xcd, 0xc8, 0x50, 0x39, 0x49, 0x3e, 0x9a, 0xc7,
+	0x51, 0x1a, 0x91, 0xc2, 0xfc, 0xd8, 0xfe, 0x85, 0x01, 0xe5, 0x4e, 0xb0, 0x1f, 0x8e, 0x27, 0xe4,
+	0x3d, 0x28, 0xf5, 0x93, 0x33, 0xb7, 0xdb, 0x34, 0x1e, 0x1b, 0x3b, 0x8d, 0x27, 0xb5, 0x8f, 0xe6,
+	0xc7, 0x1f, 0x75, 0x02, 0x44, 0x51, 0x4e, 0x21, 0x4d, 0x58, 0x6b, 0x47, 0x93, 0x9b, 0x5e, 0x38,
+	0x6b, 0x16, 0x1e, 0x1b, 0x3b, 0x25, 0x2a, 0x41, 0x62, 0x43, 0xdd, 0x4d, 0x3a, 0xd1, 0xd5, 0x3c,
+	0x0e, 0x93, 0x24, 0x9c, 0x34, 0xcd, 0xc7, 0xc6, 0x4e, 0x85, 0xe6, 0x70, 0x64, 0x07, 0x4a, 0x9d,
+	0x68, 0x12, 0x9e, 0x34, 0x8b, 0xb8, 0x01, 0xc1, 0x0d, 0xfc, 0xfe, 0x80, 0x3a, 0x41, 0x30, 0xea,
+	0xf8, 0x5d, 0xa7, 0x43, 0x39, 0x03, 0xb1, 0xc0, 0x0c, 0xc2, 0x97, 0xcd, 0xd2, 0x63, 0x63, 0xc7,
+	0xa4, 0xec, 0xd3, 0xfe, 0xba, 0x0c, 0xd5, 0x4e, 0x40, 0xc3, 0x97, 0x6c, 0x43, 0x49, 0x37, 0x14,
+	0x9d, 0xfc, 0x0f, 0x94, 0x7a, 0xd1, 0xd9, 0x94, 0xeb, 0x55, 0x7b, 0xd2, 0xe0, 0xca, 0xd3, 0xf0,
+	0x25, 0x62, 0x29, 0x27, 0x92, 0x6f, 0x41, 0x75, 0x3f, 0x1c, 0xc7, 0xe9, 0x71, 0x38, 0x4e, 0x51,
+	0xc5, 0xda, 0x13, 0xa2, 0x38, 0x15, 0x85, 0x66, 0x4c, 0xe4, 0xdb, 0x50, 0x0b, 0xc2, 0xf4, 0x30,
+	0x09, 0xe3, 0xd9, 0xf8, 0x2a, 0x44, 0xcd, 0x6b, 0x4f, 0xb6, 0x94, 0x8c, 0x46, 0xa3, 0x3a, 0x23,
+	0xf9, 0x7f, 0xa8, 0xd0, 0x28, 0xba, 0xea, 0x9c, 0x8f, 0x53, 0x34, 0xa3, 0xf6, 0x64, 0x53, 0x09,
+	0x49, 0x02, 0x55, 0x2c, 0x92, 0xbd, 0x37, 0x4d, 0xd2, 0x66, 0x79, 0x09, 0x3b, 0x23, 0x50, 0xc5,
+	0xc2, 0xd8, 0x9f, 0x47, 0xd3, 0x19, 0x83, 0x9b, 0x6b, 0x0b, 0xec, 0x92, 0x40, 0x15, 0x0b, 0x79,
+	0x0f, 0x8a, 0xa8, 0x48, 0x05, 0x59, 0xd7, 0x15, 0x2b, 0x2a, 0x81, 0x24, 0xf4, 0xcc, 0x78, 0x36,
+	0x49, 0xce, 0xc7, 0x17, 0x61, 0xb3, 0xba, 0xe8, 0x19, 0x49, 0xa1, 0x19, 0x13, 0x93, 0xe8, 0x85,
+	0xe3, 0x57, 0x21, 0x2a, 0x01, 0x0b, 0x12, 0x8a, 0x42, 0x33, 0x26, 0xe6, 0x4b, 0xf6, 0xb7, 0x1f,
+	0x5e, 0x1d, 0x87, 0x71, 0xd2, 0xac, 0x2d, 0xf8, 0x52, 0xa3, 0x51, 0x9d, 0x51, 0xca, 0xed, 0x4f,
+	0x93, 0x34, 0x8a, 0x6f, 0x9a, 0xf5, 0x25, 0x72, 0x82, 0x46, 0x75, 0x46, 0xf2, 0x3d, 0x58, 0x0f,
+	0xc2, 0x71, 0x7c, 0x72, 0x2e, 0x25, 0xd7, 0x51, 0x72, 0x5b, 0x3b, 0x3d, 0x8d, 0x4a, 0xf3, 0xcc,
+	0xcc, 0x3e, 0x77, 0x76, 0x1c, 0x7d, 0x49, 0xc3, 0xf1, 0xa4, 0xd9, 0x58, 0xb0, 0x4f, 0x51, 0x68,
+	0xc6, 0x44, 0x76, 0x61, 0x2d, 0x08, 0x53, 0x1a, 0x5d, 0x86, 0xcd, 0x0d, 0xe4, 0xb7, 0xf4, 0x38,
+	0x61, 0x78, 0x2a, 0x19, 0xe4, 0x81, 0xf7, 0xaf, 0xd3, 0xb0, 0x69, 0x2d, 0x39, 0x70, 0x46, 0xa0,
+	0x8a, 0x45, 0xb2, 0x1f, 0x4c, 0x4f, 0x2e, 0x9a, 0x9b, 0x4b, 0xd8, 0x19, 0x81, 0x2a, 0x16, 0xf2,
+	0x2e, 0x98, 0xed, 0xf1, 0xac, 0x49, 0x90, 0xb3, 0xae, 0x38, 0xdb, 0xe3, 0x19, 0x65, 0x04, 0xfb,
+	0x67, 0x6b, 0x98, 0x4d, 0xc9, 0x7c, 0x45, 0x36, 0xed, 0xc0, 0x9a, 0x13, 0xc7, 0x2c, 0x17, 0x31,
+	0x9f, 0x1a, 0x3c, 0x9f, 0x1c, 0x4a, 0x7d, 0x8a, 0x89, 0x4a, 0x25, 0x99, 0x6c, 0x43, 0xd9, 0x89,
+	0xe3, 0x7e, 0x72, 0x86, 0xe9, 0x54, 0xa5, 0x02, 0xca, 0xf2, 0xb1, 0x98, 0xcb, 0xc7, 0x64, 0xbe,
+	0x3a, 0x1f, 0x4b, 0x39, 0x1f, 0x27, 0xf3, 0xd7, 0xc9, 0xc7, 0x72, 0x2e, 0x16, 0x92, 0xf9, 0x6b,
+	0xe5, 0x63, 0x3e, 0x63, 0x92, 0xf9, 0x3d, 0xf9, 0x58, 0x59, 0xc2, 0x7e, 0x47, 0x3e, 0x56, 0x17,
+	0xd8, 0xef, 0xc8, 0x47, 0xc8, 0xe5, 0x63, 0x32, 0x5f, 0x95, 0x8f, 0xb5, 0x45, 0xcf, 0xdc, 0x9b,
+	0x8f, 0xf5, 0x05, 0x89, 0xd7, 0xc9, 0xc7, 0xf5, 0x05, 0x5f, 0xbe, 0x6e, 0x3e, 0x36, 0x96, 0xc8,
+	0xbd, 0x5e, 0x3e, 0x6e, 0xe4, 0xf2, 0x31, 0x99, 0xe7, 0xa8, 0x77, 0xe6, 0xa3, 0xb5, 0x60, 0xdf,
+	0x7d, 0xf9, 0xb8, 0x99, 0xcb, 0xc7, 0x64, 0x2e, 0xf0, 0xcb, 0xf3, 0x91, 0x2c, 0x39, 0xf0, 0x3b,
+	0xf2, 0xf1, 0xad, 0x25, 0xec, 0xcb, 0xf3, 0x71, 0x2b, 0x97, 0x8f, 0xc9, 0x5c, 0xe5, 0xe3, 0x4f,
+	0xb0, 0xbb, 0x79, 0xe9, 0x29, 0xe6, 0xe3, 0x7b, 0x50, 0xc4, 0x85, 0x0d, 0x3d, 0x3c, 0xbc, 0xf4,
+	0x14, 0x17, 0x45, 0x12, 0x71, 0xc0, 0xca, 0x4e, 0xc4, 0x9f, 0x5d, 0x4e, 0x67, 0xa1, 0xe8, 0x7c,
+	0xff, 0xa5, 0xd8, 0x17, 0x19, 0xe8, 0x2d, 0x91, 0x5c, 0x56, 0x98, 0xba, 0x19, 0x42, 0x7c, 0x21,
+	0x2b, 0x9e, 0x02, 0xe0, 0xf7, 0x65, 0xc4, 0x5a, 0x3c, 0xcf, 0xec, 0xb7, 0xf2, 0x02, 0x48, 0xa2,
+	0x1a, 0x1b, 0x13, 0x12, 0x47, 0xc8, 0xaa, 0x44, 0x69, 0x41, 0x28, 0x23, 0x51, 0x8d, 0x4d, 0x65,
+	0x48, 0x79, 0xc1, 0x05, 0x5a, 0x86, 0xb4, 0x61, 0x23, 0xb3, 0x07, 0x83, 0x5a, 0x24, 0x76, 0x73,
+	0x89, 0x07, 0x90, 0x4e, 0x17, 0x05, 0xc8, 0x3e, 0x6c, 0x6a, 0x3e, 0x39, 0x3d, 0x45, 0x3f, 0xf2,
+	0x7c, 0x7f, 0xb8, 0xcc, 0x8f, 0x9c, 0x83, 0xde, 0x16, 0x22, 0x1f, 0x43, 0x9d, 0x21, 0x07, 0x71,
+	0x98, 0x84, 0xb3, 0x13, 0xd9, 0x42, 0xdf, 0xce, 0x2d, 0x22, 0x89, 0x34, 0xc7, 0x4a, 0x3e, 0x80,
+	0x32, 0x0d, 0x93, 0x9b, 0xd9, 0x89, 0xa8, 0x07, 0x1b, 0x99, 0x10, 0xa2, 0xa9, 0x20, 0xb3, 0x9a,
+	0x8a, 0xc1, 0xdd, 0xac, 0xe9, 0x35, 0xd5, 0x4b, 0x4f, 0x11, 0x4b, 0x39, 0x51, 0x9e, 0x29, 0x86,
+	0x7d, 0x7d, 0xc9, 0x99, 0x32, 0x02, 0x55, 0x2c, 0xb9, 0xc0, 0x5f, 0x5f, 0xc2, 0xbe, 0x10, 0xf8,
+	0x22, 0x04, 0x58, 0x10, 0x86, 0xb2, 0x2d, 0xe6, 0x43, 0x80, 0x93, 0xa8, 0xc6, 0xc6, 0x2d, 0xc4,
+	0x7a, 0xbd, 0x71, 0xcb, 0x42, 0x86, 0xa6, 0x82, 0x6c, 0xff, 0xc3, 0x00, 0xc8, 0xa6, 0x36, 0xf2,
+	0x10, 0x2a, 0xaa, 0xd2, 0x1b, 0xd8, 0x5e, 0x14, 0x4c, 0x76, 0xa1, 0x8c, 0xb3, 0x62, 0xd2, 0x2c,
+	0x3c, 0x36, 0x57, 0x4c, 0x93, 0x82, 0x83, 0x3c, 0x86, 0x1a, 0x0d, 0x93, 0xeb, 0xab, 0x70, 0x18,
+	0x5d, 0x84, 0x33, 0xd1, 0xa9, 0x74, 0x14, 0x1b, 0x6c, 0x7b, 0xe3, 0x24, 0x65, 0x6d, 0xb0, 0x88,
+	0x6d, 0x50, 0x82, 0xac, 0x39, 0xb6, 0x4e, 0x2e, 0xe4, 0x28, 0xda, 0x3a, 0xb9, 0x60, 0xbc, 0xde,
+	0xf8, 0x2a, 0x3c, 0x08, 0x6f, 0x30, 0x3c, 0xab, 0x54, 0x82, 0x4c, 0xdf, 0xc1, 0x38, 0x49, 0xbe,
+	0x88, 0xe2, 0x09, 0xc6, 0x62, 0x95, 0x2a, 0x98, 0x3c, 0x82, 0x6a, 0xeb, 0x3a, 0x3d, 0xe7, 0x1a,
+	0x54, 0x90, 0x98, 0x21, 0xec, 0x6f, 0xb8, 0xe1, 0xa2, 0x3d, 0xb2, 0xae, 0xca, 0xdc, 0x27, 0x66,
+	0x71, 0x93, 0x0a, 0x28, 0xe7, 0x90, 0xc2, 0x82, 0x43, 0xd4, 0x74, 0x6d, 0xde, 0x37, 0x5d, 0xff,
+	0x1f, 0x6c, 0xaa, 0x86, 0xea, 0xce, 0xd2, 0x30, 0x7e, 0x35, 0xbe, 0x44, 0xb3, 0x4b, 0xf4, 0x36,
+	0x61, 0xd1, 0x79, 0xa5, 0xa5, 0xce, 0xe3, 0xe0, 0x04, 0x1d, 0x52, 0xa1, 0x12, 0xb4, 0xdb, 0xd0,
+	0xc8, 0x8f, 0xd6, 0xe4, 0x5d, 0x80, 0xce, 0xe5, 0x34, 0x9c, 0xa5, 0xc3, 0xa9, 0x38, 0x54, 0x93,
+	0x6a, 0x18, 0xe9, 0xee, 0x82, 0x72, 0xb7, 0x3d, 0x80, 0x46, 0x7e, 0x1c, 0xb8, 0x77, 0x8d, 0x77,
+	0x01, 0x82, 0x30, 0x7e, 0x15, 0xc6, 0x48, 0xe7, 0x4b, 0x69, 0x18, 0x7b, 0x1f, 0xac, 0xc5, 0xe1,
+	0xfd, 0xce, 0x50, 0xe3, 0xf6, 0x31, 0xf9, 0x66, 0x41, 0xd9, 0xc7, 0x40, 0xb1, 0x52, 0x32, 0xff,
+	0x37, 0x56, 0x92, 0xa1, 0x53, 0xc8, 0x85, 0x8e, 0xfd, 0x21, 0xac, 0xe7, 0xee, 0x06, 0x8c, 0xf5,
+	0x24, 0x9a, 0xa5, 0xe1, 0x2c, 0x15, 0xab, 0x48, 0xd0, 0xde, 0x80, 0x75, 0xd5, 0x67, 0x18, 0xab,
+	0xfd, 0x54, 0x93, 0xc5, 0x71, 0xc4, 0x86, 0x7a, 0x7f, 0xfc, 0x25, 0xd2, 0xa3, 0x6b, 0xb1, 0x40,
+	0x89, 0xe6, 0x70, 0xf6, 0x4f, 0x0d, 0x9e, 0xf8, 0xee, 0xec, 0x34, 0x22, 0xbb, 0x60, 0x75, 0xae,
+	0xe3, 0x38, 0x9c, 0xa5, 0xbc, 0xaa, 0x79, 0xd7, 0x57, 0x42, 0xe8, 0x16, 0x9e, 0xbc, 0x0f, 0x8d,
+	0x61, 0x94, 0x8e, 0x2f, 0x33, 0x4e, 0x7e, 0x15, 0x5c, 0xc0, 0x6a, 0x31, 0x6c, 0xe6, 0x62, 0x98,
+	0x40, 0xd1, 0x93, 0x57, 0xa9, 0x2a, 0xc5, 0x6f, 0xfb, 0xa9, 0x66, 0x92, 0xb0, 0xa0, 0xc4, 0xbe,
+	0x93, 0xa6, 0xf1, 0xd8, 0x94, 0x2d, 0x53, 0x6a, 0x4b, 0x39, 0xc9, 0x3e, 0x12, 0x66, 0xab, 0xb1,
+	0x6a, 0x55, 0xd6, 0x3c, 0x82, 0x6a, 0x27, 0x0e, 0xc7, 0x69, 0xe8, 0x85, 0x5f, 0x88, 0x13, 0xcc,
+	0x10, 0x4a, 0x1f, 0x53, 0xd3, 0xe7, 0xbb, 0x42, 0x9f, 0x7b, 0x97, 0x96, 0xc2, 0x05, 0x4d, 0xd8,
+	0x12, 0x41, 0xaf, 0x06, 0x2d, 0x7b, 0x47, 0x84, 0xb0, 0xc2, 0xac, 0x5a, 0xcf, 0x3e, 0xe3, 0xe5,
+	0x95, 0x7b, 0xf1, 0xce, 0x50, 0x7a, 0xc8, 0x47, 0x4e, 0x2d, 0xc4, 0x15, 0xcc, 0xba, 0x27, 0x96,
+	0x7f, 0x5e, 0x09, 0xb0, 0x7b, 0x52, 0xdf, 0xef, 0x8f, 0xa8, 0xdf, 0x73, 0x28, 0x92, 0x6c, 0x22,
+	0x72, 0x20, 0xdb, 0x2d, 0xb1, 0x87, 0x22, 0x9a, 0x35, 0xdc, 0x4a, 0xc3, 0x77, 0x60, 0x4d, 0xb0,
+	0x60, 0xfd, 0x15, 0xdd, 0x28, 0x93, 0xa4, 0x92, 0x6c, 0xff, 0x40, 0xdb, 0x49, 0xce, 0x72, 0x0f,
+	0xa1, 0xd2, 0x0e, 0x4f, 0xa3, 0x38, 0x54, 0xeb, 0x2a, 0x98, 0x05, 0x7e, 0xeb, 0x34, 0x0d, 0x63,
+	0xb7, 0x2b, 0xec, 0x92, 0x20, 0xd9, 0x82, 0x52, 0x6f, 0x7a, 0x35, 0xe5, 0xa3, 0x4a, 0x89, 0x72,
+	0xc0, 0x9e, 0x6a, 0x5a, 0xcb, 0xf5, 0x57, 0x69, 0xfd, 0x21, 0xac, 0x09, 0x16, 0xa1, 0x35, 0x76,
+	0x22, 0x81, 0xc2, 0xd9, 0x42, 0xd2, 0xd9, 0xc9, 0xf6, 0xa3, 0x38, 0x14, 0x0f, 0x19, 0xf8, 0x6d,
+	0xcf, 0x81, 0xdc, 0xbe, 0x37, 0x32, 0xb5, 0x5e, 0x5c, 0x87, 0xf1, 0x8d, 0x38, 0x22, 0x0e, 0x30,
+	0xf9, 0xbd, 0x38, 0xba, 0x92, 0x91, 0xc1, 0xbe, 0x73, 0x66, 0x9b, 0x0b, 0x66, 0x2b, 0xe3, 0x8a,
+	0xba, 0x71, 0x17, 0x40, 0x44, 0x81, 0xd1, 0x77, 0xbc, 0xc3, 0x3c, 0x56, 0x79, 0x2f, 0xd3, 0x64,
+	0xa5, 0x79, 0x82, 0xbe, 0xd4, 0xbc, 0x96, 0x78, 0x62, 0xb9, 0xbb, 0xfe, 0x30, 0x2b, 0xae, 0x17,
+	0x9a, 0x90, 0x84, 0xed, 0x8f, 0xc5, 0xbd, 0x12, 0x97, 0xd8, 0xd2, 0x1f, 0x94, 0x4c, 0xf9, 0x86,
+	0xb4, 0x0d, 0xe5, 0x20, 0x8d, 0xe2, 0x70, 0x22, 0x52, 0x51, 0x40, 0xf6, 0x0c, 0x60, 0x10, 0x4f,
+	0x5f, 0x8d, 0xd3, 0x90, 0x0d, 0x80, 0x0d, 0x28, 0x28, 0xc1, 0x02, 0x4f, 0xb4, 0x5b, 0xee, 0x6c,
+	0x40, 0x61, 0x18, 0x89, 0xbc, 0x2d, 0x0c, 0x23, 0xa6, 0x72, 0x47, 0xa8, 0xcc, 0x8b, 0x8b, 0x04,
+	0x99, 0x34, 0x26, 0x0a, 0xef, 0xe2, 0xf8, 0x6d, 0x7f, 0x07, 0x6a, 0x38, 0x30, 0x1d, 0xce, 0x62,
+	0x76, 0x59, 0x90, 0x1b, 0x18, 0xda, 0x06, 0x5b, 0x50, 0xe2, 0x05, 0x94, 0x57, 0x38, 0x0e, 0xd8,
+	0x47, 0x00, 0xd9, 0xd4, 0x45, 0x6c, 0x28, 0xf6, 0x93, 0x33, 0x59, 0xa8, 0x30, 0x0b, 0x32, 0x33,
+	0x28, 0xd2, 0xd8, 0xfc, 0xc3, 0x77, 0xd1, 0x8f, 0x45, 0xdb, 0x9c, 0x0a, 0xb2, 0x28, 0x14, 0xda,
+	0xf3, 0x02, 0xf3, 0xd6, 0xe1, 0x7c, 0x18, 0x65, 0x47, 0xcd, 0x21, 0x55, 0x52, 0xf2, 0x9c, 0x7c,
+	0x13, 0x5e, 0xb9, 0xe5, 0x9a, 0x7d, 0xa8, 0xeb, 0x4f, 0x10, 0x77, 0x16, 0x15, 0x59, 0x38, 0x0a,
+	0xab, 0x0b, 0x47, 0x03, 0xea, 0x22, 0x22, 0x71, 0x39, 0xdb, 0xd1, 0x9a, 0x0f, 0x4e, 0x88, 0xf7,
+	0xf4, 0xbf, 0x20, 0x3c, 0x89, 0x66, 0x93, 0x44, 0xe6, 0xb6, 0x00, 0xed, 0xff, 0xd5, 0x3a, 0x00,
+	0x2e, 0xb3, 0x05, 0xa5, 0xc3, 0x59, 0x3a, 0xbd, 0x94, 0xc1, 0x83, 0x80, 0xdd, 0xd1, 0x76, 0xc3,
+	0x8b, 0xd0, 0x5d, 0xbb, 0xb1, 0x34, 0x09, 0xc7, 0x49, 0x34, 0x13, 0x51, 0x23, 0xa0, 0x5c, 0x03,
+	0x65, 0x8b, 0xd8, 0x3f, 0x37, 0xa0, 0x22, 0x1f, 0x48, 0xee, 0x5c, 0x91, 0x45, 0xe5, 0x40, 0xac,
+	0x56, 0x70, 0x07, 0x6c, 0x87, 0x4f, 0xa7, 0xe9, 0xb9, 0x3b, 0x10, 0x79, 0x24, 0x20, 0xdd, 0xce,
+	0x62, 0xce, 0x4e, 0x4d, 0xa7, 0x92, 0xae, 0x13, 0x0b, 0xbf, 0xde, 0xf4, 0x34, 0x15, 0x03, 0x14,
+	0x7e, 0xdb, 0x00, 0x15, 0x79, 0x4d, 0xb4, 0x5f, 0xc1, 0x3a, 0x1f, 0x98, 0xe5, 0xdc, 0xfe, 0x26,
+	0x23, 0xe2, 0xfd, 0x7d, 0x81, 0x59, 0xd8, 0xbe, 0x11, 0xe9, 0x53, 0x68, 0xdf, 0xd8, 0x53, 0x6d,
+	0x5f, 0x3c, 0x97, 0x37, 0xd9, 0x57, 0x9d, 0xa5, 0xa9, 0x9d, 0xe5, 0xad, 0xad, 0x5e, 0xc0, 0xc6,
+	0xc2, 0x25, 0x62, 0xe5, 0x66, 0x5c, 0xb4, 0x20, 0x45, 0x35, 0xaf, 0x9a, 0xb9, 0x93, 0x3e, 0x82,
+	0x9a, 0x76, 0xcd, 0x58, 0xb9, 0x5c, 0x13, 0xd6, 0xfc, 0xcb, 0x89, 0xd6, 0xc8, 0x25, 0xc8, 0x28,
+	0x5e, 0xf8, 0x85, 0x36, 0x1f, 0x48, 0xd0, 0xee, 0xca, 0xd1, 0x56, 0x3d, 0xc0, 0x3c, 0x82, 0xea,
+	0xe0, 0xfa, 0xf8, 0x72, 0x7a, 0xc2, 0xc6, 0x3b, 0xb6, 0x41, 0x9d, 0x66, 0x08, 0xe6, 0x03, 0x2f,
+	0x9a, 0x9d, 0xf0, 0x1d, 0xea, 0x94, 0x03, 0xf6, 0xb1, 0x1c, 0x6e, 0xff, 0x93, 0x55, 0x98, 0x4c,
+	0x30, 0x3d, 0x9b, 0x8d, 0xd3, 0x6b, 0x51, 0xd1, 0xeb, 0x34, 0x43, 0xd8, 0x7b, 0xe2, 0x6d, 0x01,
+	0xf3, 0xe5, 0x03, 0xe5, 0x29, 0xfe, 0xca, 0x8f, 0xa5, 0xe8, 0xc0, 0xed, 0x1c, 0x8c, 0xa8, 0xd3,
+	0x0a, 0x7c, 0x4f, 0x05, 0xa4, 0x05, 0x26, 0xbb, 0xaf, 0x73, 0x7f, 0xb0, 0x4f, 0xfb, 0x00, 0xde,
+	0x5e, 0xfa, 0xae, 0xf0, 0x26, 0x21, 0x61, 0x7f, 0x65, 0x68, 0x81, 0x85, 0xdd, 0xe2, 0x9e, 0xba,
+	0x21, 0x2b, 0x7b, 0x21, 0x5f, 0xd9, 0xc5, 0xdb, 0xa5, 0x99, 0xbd, 0x5d, 0xaa, 0xae, 0x53, 0xd4,
+	0xbb, 0xce, 0xb2, 0x0e, 0xf0, 0x1c, 0xb6, 0x96, 0x3d, 0x13, 0xbc, 0x91, 0x3d, 0x3d, 0xd8, 0x5e,
+	0xfe, 0x58, 0xf0, 0x46, 0xab, 0xfd, 0xd8, 0x80, 0xcd, 0x5b, 0xcf, 0x06, 0x77, 0xad, 0x14, 0xcc,
+	0xc6, 0xf3, 0xe4, 0x3c, 0x4a, 0x45, 0x4f, 0x55, 0x30, 0x79, 0x1f, 0xca, 0x6c, 0x2c, 0xc4, 0x5f,
+	0x64, 0x96, 0x8d, 0x69, 0x82, 0x8a, 0xf5, 0x27, 0x3c, 0x65, 0x8d, 0xd3, 0x64, 0xed, 0x8f, 0x7d,
+	0xdb, 0xdf, 0x57, 0xd9, 0x83, 0xcf, 0x0f, 0xdb, 0x50, 0xee, 0x4f, 0xf1, 0xc7, 0x1d, 0xb1, 0x3d,
+	0x87, 0x78, 0x8e, 0x7c, 0x89, 0x77, 0x67, 0x51, 0xd4, 0x05, 0x68, 0x7f, 0xa8, 0x65, 0xb4, 0x78,
+	0x0d, 0x5a, 0x35, 0xf8, 0x7e, 0x65, 0x40, 0x4d, 0x1b, 0x54, 0x98, 0x3e, 0xa7, 0x5a, 0x3b, 0x3e,
+	0x15, 0xfd, 0x7e, 0x22, 0x03, 0xa0, 0x30, 0xc9, 0x8d, 0x28, 0x66, 0x7e, 0x44, 0xb1, 0xc0, 0x4c,
+	0xd4, 0x55, 0x9e, 0x7d, 0x32, 0xd9, 0xe9, 0x44, 0x9c, 0x7e, 0x61, 0x8a, 0xf6, 0xa6, 0x53, 0xf1,
+	0x80, 0x6c, 0x52, 0xfc, 0xb6, 0xcf, 0x85, 0xba, 0xda, 0x3b, 0x94, 0x36, 0x30, 0x1a, 0xf7, 0x0c,
+	0x8c, 0x99, 0x65, 0x85, 0xc5, 0x2b, 0xc2, 0xad, 0x49, 0xeb, 0x44, 0xa4, 0xe4, 0x4a, 0x53, 0x35,
+	0xd3, 0x0a, 0x79, 0xd3, 0x54, 0x78, 0x9b, 0xcb, 0xc2, 0xbb, 0x98, 0x85, 0xf7, 0xee, 0xdf, 0x0d,
+	0x80, 0xec, 0xc9, 0x9e, 0xd4, 0x60, 0x2d, 0x38, 0xec, 0x74, 0x9c, 0x20, 0xb0, 0x1e, 0x10, 0x80,
+	0xf2, 0x5e, 0xcb, 0xed, 0x39, 0x5d, 0xcb, 0x20, 0x16, 0xd4, 0x69, 0x6b, 0xe8, 0x8c, 0x7a, 0x6e,
+	0xdf, 0x1d, 0x3a, 0x5d, 0xab, 0xc0, 0xa8, 0xed, 0x96, 0xe7, 0x39, 0x5d, 0xcb, 0x24, 0x9b, 0xb0,
+	0xee, 0xf9, 0xa3, 0x81, 0x43, 0xfb, 0x6e, 0x10, 0xb8, 0xbe, 0x67, 0x15, 0x99, 0x80, 0xeb, 0x7d,
+	0xd2, 0xea, 0xb9, 0xdd, 0x91, 0xd7, 0xea, 0x3b, 0x56, 0x89, 0x34, 0x00, 0xd8, 0xd7, 0x68, 0xd8,
+	0x3a, 0x70, 0x3c, 0xab, 0xcc, 0x84, 0x5a, 0x87, 0xc3, 0xfd, 0x11, 0x75, 0x5e, 0x1c, 0xba, 0xd4,
+	0xe9, 0x5a, 0x6b, 0x64, 0x03, 0x6a, 0x88, 0x12, 0xdb, 0x56, 0x18, 0xcf, 0xd0, 0x3f, 0x70, 0xbc,
+	0x91, 0xf3, 0xd9, 0x00, 0x79, 0xaa, 0x84, 0x40, 0xa3, 0xd5, 0xe9, 0xf8, 0x87, 0xde, 0x70, 0xd4,
+	0xf3, 0x3b, 0x07, 0x4e, 0xd7, 0x02, 0xb2, 0x05, 0x56, 0xc7, 0xf7, 0x86, 0x8e, 0x37, 0x1c, 0x51,
+	0xe7, 0xb9, 0xd3, 0x61, 0x1a, 0xd6, 0x76, 0x7f, 0x04, 0x35, 0xad, 0x68, 0x31, 0x8d, 0x10, 0x3c,
+	0xf4, 0x0e, 0x3c, 0xff, 0x53, 0xcf, 0x7a, 0x40, 0x9a, 0xb0, 0x85, 0x98, 0xc0, 0xa1, 0x9f, 0x38,
+	0x74, 0x14, 0xec, 0x1f, 0x0e, 0xbb, 0x8c, 0x62, 0xb0, 0x7d, 0x91, 0xd2, 0x3e, 0x1a, 0xb5, 0xba,
+	0x7d, 0xd7, 0xb3, 0x0a, 0x64, 0x1d, 0xaa, 0x88, 0x72, 0xbb, 0x3d, 0xc7, 0x32, 0x99, 0x35, 0x08,
+	0xee, 0xf5, 0x7c, 0xbf, 0x6b, 0x15, 0x99, 0xea, 0x5c, 0x82, 0xfb, 0xa4, 0xb4, 0x3b, 0x80, 0xaa,
+	0xea, 0x9b, 0x8c, 0xca, 0xfe, 0x8e, 0xfa, 0x4e, 0xbf, 0xed, 0x50, 0xeb, 0x01, 0xb3, 0x82, 0x23,
+	0xd8, 0x0f, 0x25, 0xad, 0xa1, 0x4f, 0x2d, 0x83, 0x2d, 0x89, 0x38, 0xff, 0x53, 0xcf, 0xa1, 0x56,
+	0x41, 0xc1, 0x5c, 0x03, 0x73, 0xf7, 0x15, 0x34, 0xf2, 0x6f, 0x35, 0x4c, 0x4d, 0x85, 0xf1, 0x7c,
+	0xcf, 0xb1, 0x1e, 0xe4, 0x50, 0x9f, 0xf7, 0xdc, 0xb6, 0x65, 0xb0, 0xbd, 0x14, 0x6a, 0xaf, 0xd7,
+	0x1a, 0x3a, 0x56, 0x21, 0xc7, 0xf6, 0xec, 0x73, 0x77, 0x60, 0x99, 0xe4, 0x1d, 0x78, 0x2b, 0xcf,
+	0x36, 0xea, 0xba, 0x9d, 0xa1, 0x55, 0xdc, 0xfd, 0xe7, 0x1a, 0xac, 0x89, 0xdf, 0x78, 0x99, 0x17,
+	0xa8, 0xf3, 0x62, 0xd4, 0x76, 0x9e, 0xb9, 0xcc, 0x83, 0x02, 0xec, 0xf9, 0xcf, 0x5c, 0xe1, 0x36,
+	0x06, 0xee, 0x3b, 0x2d, 0x3a, 0x6c, 0x3b, 0xad, 0xa1, 0x55, 0x60, 0x47, 0xc3, 0x50, 0x81, 0x33,
+	0x1c, 0x1d, 0x06, 0x0e, 0xc5, 0x58, 0x30, 0x25, 0x23, 0x3a, 0xa8, 0xb3, 0xdf, 0x1a, 0x5a, 0xc5,
+	0x1c, 0xaa, 0xe7, 0x06, 0x43, 0xab, 0x24, 0x51, 0xcf, 0x7d, 0xd7, 0x43, 0xbc, 0x55, 0x26, 0x75,
+	0xa8, 0x30, 0x14, 0xca, 0xac, 0xa9, 0xfd, 0x5a, 0x5e, 0x37, 0xd8, 0x6f, 0x1d, 0x38, 0x56, 0x05,
+	0x1d, 0xcb, 0x34, 0x72, 0x5a, 0x9f, 0x38, 0x5c, 0xa8, 0x2a, 0x75, 0xc0, 0xa5, 0xf9, 0x09, 0x04,
+	0x16, 0xe4, 0xb0, 0xfb, 0x6e, 0x30, 0xf4, 0xe9, 0x91, 0x55, 0x23, 0xdb, 0x40, 0xb8, 0xbe, 0x2d,
+	0xda, 0xd9, 0x57, 0xf8, 0xba, 0x5c, 0xd7, 0xf5, 0xda, 0xfe, 0x67, 0x2c, 0xa2, 0xba, 0xd6, 0x3a,
+	0x26, 0x85, 0xb0, 0x8d, 0x1d, 0x94, 0xd5, 0xc8, 0x19, 0xd1, 0x3f, 0x1c, 0x3a, 0xd6, 0x46, 0x0e,
+	0xc5, 0x22, 0xc4, 0xb2, 0x58, 0x96, 0xa1, 0x13, 0x5b, 0x9e, 0xb5, 0x89, 0x2e, 0x0c, 0x06, 0xc2,
+	0xa3, 0x13, 0x09, 0x72, 0x8f, 0x86, 0x28, 0x1d, 0x0c, 0x34, 0x8f, 0x9e, 0xa2, 0xde, 0xc1, 0x20,
+	0xef, 0xd1, 0x33, 0xc9, 0x98, 0x79, 0xf4, 0x3c, 0x87, 0x42, 0x8f, 0x4e, 0x25, 0x2a, 0xf3, 0xe8,
+	0x0f, 0xd1, 0xa3, 0xc1, 0x80, 0xcb, 0x5c, 0xa8, 0xfd, 0x94, 0x47, 0x2f, 0xd1, 0xf2, 0x60, 0xa0,
+	0x7b, 0xf4, 0x4a, 0xea, 0x90, 0xf3, 0xe8, 0x2c, 0x87, 0x95, 0x9e, 0x8b, 0xd0, 0xa3, 0xc1, 0x60,
+	0xd1, 0xa3, 0x73, 0xb9, 0xae, 0xe6, 0xd1, 0x97, 0xe8, 0xd1, 0x60, 0x90, 0x79, 0x34, 0xce, 0x19,
+	0x81, 0x1e, 0x4d, 0x72, 0x28, 0xf4, 0x68, 0x8a, 0x1e, 0x65, 0x4e, 0x6c, 0x79, 0xd6, 0x35, 0x69,
+	0x40, 0xd5, 0x1b, 0xee, 0x09, 0x8f, 0xfe, 0xd2, 0x20, 0xff, 0x0d, 0xdb, 0x0c, 0xd6, 0x94, 0x1d,
+	0xf9, 0x5e, 0xcf, 0xf5, 0x1c, 0xeb, 0x57, 0x2c, 0x39, 0xd6, 0x15, 0x11, 0x7d, 0xf0, 0x6b, 0x83,
+	0x6c, 0xc1, 0x46, 0x86, 0xeb, 0xf9, 0x81, 0xd3, 0xb5, 0xbe, 0x56, 0x58, 0x66, 0x00, 0xf5, 0x8f,
+	0x46, 0xfd, 0xe0, 0x99, 0xf5, 0x1b, 0x83, 0xac, 0x43, 0x85, 0x61, 0x51, 0xf4, 0xb7, 0x0a, 0x44,
+	0xb5, 0x7e, 0x67, 0x90, 0x87, 0xf0, 0xf6, 0xe2, 0xd6, 0xe8, 0x47, 0xeb, 0xf7, 0x06, 0x79, 0x04,
+	0xef, 0xdc, 0x52, 0x6b, 0x6f, 0x0f, 0xf5, 0xfa, 0x83, 0x41, 0xb6, 0x61, 0x53, 0x51, 0x59, 0x4e,
+	0x3a, 0x5e, 0xc7, 0xb1, 0xfe, 0x68, 0x90, 0x0d, 0x00, 0xc4, 0x3b, 0xc1, 0x91, 0xd7, 0xb1, 0xfe,
+	0x64, 0x48, 0x6b, 0xd1, 0x8d, 0xd6, 0x9f, 0xf3, 0x06, 0xa1, 0x0f, 0xbf, 0xc9, 0xe3, 0xd0, 0x89,
+	0x7f, 0xc9, 0x1b, 0xc9, 0xd4, 0x75, 0xba, 0xd6, 0x5f, 0xb5, 0xe5, 0x31, 0xac, 0xfe, 0x66, 0x1c,
+	0x97, 0xf1, 0xdf, 0x40, 0x9e, 0xfe, 0x6b, 0x00, 0x89, 0x31, 0xbd, 0xff, 0x12, 0x22, 0x00, 0x00,
 }
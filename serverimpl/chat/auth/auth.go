@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 登录凭证校验, 按凭证类型选择后端
+// 密码哈希较慢, 调用方应在主协程以外调用Verify
+
+var (
+	ErrNoCredentials  = errors.New("credentials required")
+	ErrBadCredentials = errors.New("wrong username or password")
+	ErrBadToken       = errors.New("invalid token")
+	ErrTokenExpired   = errors.New("token expired")
+	ErrLocked         = errors.New("too many failed logins")
+)
+
+type Credentials struct {
+	Password string
+	Token    string // 网页登录签发的令牌
+}
+
+// 实现需可在多个协程中使用
+type Backend interface {
+	// 是否由该后端校验此凭证
+	Accepts(c Credentials) bool
+	Verify(username string, c Credentials, now time.Time) error
+}
+
+type Authenticator struct {
+	backends []Backend
+	lockout  *Lockout
+}
+
+// 没有后端时不认证, lockout为nil时不锁定
+func New(lockout *Lockout, backends ...Backend) *Authenticator {
+	return &Authenticator{
+		backends: backends,
+		lockout:  lockout,
+	}
+}
+
+func (a *Authenticator) Enabled() bool {
+	return a != nil && len(a.backends) > 0
+}
+
+// ip为登录来源, 来源或用户名锁定期间不再校验, 失败的校验同时计入两者的锁定次数
+func (a *Authenticator) Verify(username, ip string, c Credentials, now time.Time) error {
+	if !a.Enabled() {
+		return nil
+	}
+	if until, ok := a.lockout.Locked(username, ip, now); ok {
+		return fmt.Errorf("%w, retry after %s", ErrLocked, until.Format(time.RFC3339))
+	}
+
+	for _, b := range a.backends {
+		if !b.Accepts(c) {
+			continue
+		}
+		e := b.Verify(username, c, now)
+		a.lockout.Record(username, ip, e == nil, now)
+		return e
+	}
+	return ErrNoCredentials
+}
@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPBKDF2(t *testing.T) {
+	cases := []struct {
+		iter int
+		want string
+	}{
+		{1, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"},
+		{2, "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43"},
+		{4096, "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"},
+	}
+	for _, c := range cases {
+		if got := hex.EncodeToString(pbkdf2([]byte("password"), []byte("salt"), c.iter, 32)); got != c.want {
+			t.Errorf("iter %d got %s", c.iter, got)
+		}
+	}
+}
+
+func TestFileBackend(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "auth")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "accounts.json")
+
+	b, e := OpenFileBackend(path)
+	if e != nil {
+		t.Fatal(e)
+	}
+	b.iter = 10
+	if e = b.SetPassword("alice", "secret"); e != nil {
+		t.Fatal(e)
+	}
+
+	// 重新打开后仍可校验, 相同密码的盐不同
+	b, e = OpenFileBackend(path)
+	if e != nil {
+		t.Fatal(e)
+	}
+	b.iter = 10
+	b.SetPassword("bob", "secret")
+	if b.users["alice"].Salt == b.users["bob"].Salt {
+		t.Fatal("salt should be random")
+	}
+	now := time.Now()
+	if e = b.Verify("alice", Credentials{Password: "secret"}, now); e != nil {
+		t.Fatal(e)
+	}
+	if e = b.Verify("alice", Credentials{Password: "Secret"}, now); e != ErrBadCredentials {
+		t.Fatalf("wrong password:%v", e)
+	}
+	if e = b.Verify("carol", Credentials{Password: ""}, now); e != ErrBadCredentials {
+		t.Fatalf("unknown user:%v", e)
+	}
+
+	if e = b.Remove("alice"); e != nil {
+		t.Fatal(e)
+	}
+	if e = b.Verify("alice", Credentials{Password: "secret"}, now); e != ErrBadCredentials {
+		t.Fatalf("removed user:%v", e)
+	}
+}
+
+func TestTokenBackend(t *testing.T) {
+	secret := []byte("web login secret")
+	b := NewTokenBackend(secret)
+	now := time.Now()
+	token := Sign(secret, "alice", now.Add(time.Minute))
+
+	if e := b.Verify("alice", Credentials{Token: token}, now); e != nil {
+		t.Fatal(e)
+	}
+	if e := b.Verify("bob", Credentials{Token: token}, now); e != ErrBadToken {
+		t.Fatalf("other user:%v", e)
+	}
+	if e := b.Verify("alice", Credentials{Token: token}, now.Add(time.Minute)); e != ErrTokenExpired {
+		t.Fatalf("expired:%v", e)
+	}
+	forged := Sign([]byte("guess"), "alice", now.Add(time.Minute))
+	for _, tk := range []string{forged, token + "x", "abc", token[:len(token)-2]} {
+		if e := b.Verify("alice", Credentials{Token: tk}, now); e != ErrBadToken {
+			t.Errorf("%s:%v", tk, e)
+		}
+	}
+}
+
+type staticBackend map[string]string
+
+func (b staticBackend) Accepts(c Credentials) bool { return c.Password != "" }
+
+func (b staticBackend) Verify(username string, c Credentials, now time.Time) error {
+	if b[username] != c.Password {
+		return ErrBadCredentials
+	}
+	return nil
+}
+
+func TestLockout(t *testing.T) {
+	a := New(NewLockout(3, 0, time.Minute), staticBackend{"alice": "pw"})
+	now := time.Now()
+	ip := "10.0.0.1"
+	if e := a.Verify("alice", ip, Credentials{Token: "x"}, now); e != ErrNoCredentials {
+		t.Fatalf("no backend for token:%v", e)
+	}
+
+	// 成功后重新计数
+	a.Verify("alice", ip, Credentials{Password: "bad"}, now)
+	a.Verify("alice", ip, Credentials{Password: "bad"}, now)
+	if e := a.Verify("alice", ip, Credentials{Password: "pw"}, now); e != nil {
+		t.Fatal(e)
+	}
+	for i := 0; i < 3; i++ {
+		a.Verify("alice", ip, Credentials{Password: "bad"}, now)
+	}
+	if e := a.Verify("alice", ip, Credentials{Password: "pw"}, now.Add(59*time.Second)); !errors.Is(e, ErrLocked) {
+		t.Fatalf("should be locked:%v", e)
+	}
+	if e := a.Verify("bob", ip, Credentials{Password: "bad"}, now); e != ErrBadCredentials {
+		t.Fatalf("other user:%v", e)
+	}
+	if e := a.Verify("alice", ip, Credentials{Password: "pw"}, now.Add(61*time.Second)); e != nil {
+		t.Fatalf("lock should expire:%v", e)
+	}
+
+	// 间隔超过锁定时长的失败不累计
+	for i := 0; i < 3; i++ {
+		now = now.Add(2 * time.Minute)
+		a.Verify("alice", ip, Credentials{Password: "bad"}, now)
+	}
+	if _, ok := a.lockout.Locked("alice", ip, now); ok {
+		t.Fatal("sparse failures should not lock")
+	}
+
+	if New(nil).Verify("alice", ip, Credentials{}, now) != nil {
+		t.Fatal("no backend means no auth")
+	}
+}
+
+// 攻击者的IP连续失败只锁定自己, 不影响用户从其它IP登录
+func TestLockoutPerIP(t *testing.T) {
+	a := New(NewLockout(3, 0, time.Minute), staticBackend{"alice": "pw"})
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		a.Verify("alice", "10.0.0.66", Credentials{Password: "bad"}, now)
+	}
+	if e := a.Verify("alice", "10.0.0.66", Credentials{Password: "pw"}, now); !errors.Is(e, ErrLocked) {
+		t.Fatalf("attacker ip should be locked:%v", e)
+	}
+	if e := a.Verify("alice", "10.0.0.1", Credentials{Password: "pw"}, now); e != nil {
+		t.Fatalf("other ip locked out:%v", e)
+	}
+
+	// 其它IP的失败单独计数
+	a.Verify("alice", "10.0.0.1", Credentials{Password: "bad"}, now)
+	a.Verify("alice", "10.0.0.1", Credentials{Password: "bad"}, now)
+	if e := a.Verify("alice", "10.0.0.1", Credentials{Password: "pw"}, now); e != nil {
+		t.Fatalf("failures from other ip counted:%v", e)
+	}
+}
+
+// 从多个IP轮流猜同一用户名, 累计达到用户名的上限后锁定该用户名
+func TestLockoutPerUsername(t *testing.T) {
+	a := New(NewLockout(3, 5, time.Minute), staticBackend{"alice": "pw", "bob": "pw"})
+	now := time.Now()
+	for i := 0; i < 4; i++ {
+		a.Verify("alice", fmt.Sprintf("10.0.1.%d", i), Credentials{Password: "bad"}, now)
+	}
+	if _, ok := a.lockout.Locked("alice", "10.0.0.1", now); ok {
+		t.Fatal("locked below the username threshold")
+	}
+
+	a.Verify("alice", "10.0.1.4", Credentials{Password: "bad"}, now)
+	if e := a.Verify("alice", "10.0.0.1", Credentials{Password: "pw"}, now.Add(59*time.Second)); !errors.Is(e, ErrLocked) {
+		t.Fatalf("username should be locked:%v", e)
+	}
+	if e := a.Verify("bob", "10.0.1.1", Credentials{Password: "pw"}, now); e != nil {
+		t.Fatalf("other user locked out:%v", e)
+	}
+	if e := a.Verify("alice", "10.0.0.1", Credentials{Password: "pw"}, now.Add(61*time.Second)); e != nil {
+		t.Fatalf("lock should expire:%v", e)
+	}
+}
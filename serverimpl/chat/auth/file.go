@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	defaultIterations = 100000
+	saltSize          = 16
+	hashSize          = 32
+)
+
+// 账号文件, 保存加盐的PBKDF2-HMAC-SHA256密码哈希, 修改后立即写回
+type FileBackend struct {
+	sync.RWMutex
+	path  string
+	iter  int
+	users map[string]*account
+	dummy *account // 用户不存在时同样计算一次哈希, 不暴露用户是否存在
+}
+
+type account struct {
+	Salt string `json:"salt"`
+	Hash string `json:"hash"`
+	Iter int    `json:"iter"`
+}
+
+// 文件不存在时从空的账号表开始
+func OpenFileBackend(path string) (*FileBackend, error) {
+	b := &FileBackend{
+		path:  path,
+		iter:  defaultIterations,
+		users: map[string]*account{},
+	}
+	data, e := ioutil.ReadFile(path)
+	if e != nil && !os.IsNotExist(e) {
+		return nil, e
+	}
+	if len(data) > 0 {
+		if e = json.Unmarshal(data, &b.users); e != nil {
+			return nil, e
+		}
+	}
+	if b.dummy, e = b.newAccount(""); e != nil {
+		return nil, e
+	}
+	return b, nil
+}
+
+func (b *FileBackend) Accepts(c Credentials) bool {
+	return c.Password != ""
+}
+
+func (b *FileBackend) Verify(username string, c Credentials, now time.Time) error {
+	b.RLock()
+	acc, ok := b.users[username]
+	b.RUnlock()
+	if !ok {
+		acc = b.dummy
+	}
+
+	salt, e1 := hex.DecodeString(acc.Salt)
+	hash, e2 := hex.DecodeString(acc.Hash)
+	if e1 != nil || e2 != nil {
+		return ErrBadCredentials
+	}
+	sum := pbkdf2([]byte(c.Password), salt, acc.Iter, len(hash))
+	if subtle.ConstantTimeCompare(sum, hash) != 1 || !ok {
+		return ErrBadCredentials
+	}
+	return nil
+}
+
+func (b *FileBackend) Has(username string) bool {
+	b.RLock()
+	defer b.RUnlock()
+	_, ok := b.users[username]
+	return ok
+}
+
+// 新建账号或重置密码
+func (b *FileBackend) SetPassword(username, password string) error {
+	if username == "" || password == "" {
+		return errors.New("empty username or password")
+	}
+	acc, e := b.newAccount(password)
+	if e != nil {
+		return e
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	old := b.users[username]
+	b.users[username] = acc
+	if e = b.save(); e != nil {
+		if old != nil {
+			b.users[username] = old
+		} else {
+			delete(b.users, username)
+		}
+		return e
+	}
+	return nil
+}
+
+func (b *FileBackend) Remove(username string) error {
+	b.Lock()
+	defer b.Unlock()
+	old, ok := b.users[username]
+	if !ok {
+		return errors.New("account not found")
+	}
+	delete(b.users, username)
+	if e := b.save(); e != nil {
+		b.users[username] = old
+		return e
+	}
+	return nil
+}
+
+func (b *FileBackend) newAccount(password string) (*account, error) {
+	salt := make([]byte, saltSize)
+	if _, e := rand.Read(salt); e != nil {
+		return nil, e
+	}
+	return &account{
+		Salt: hex.EncodeToString(salt),
+		Hash: hex.EncodeToString(pbkdf2([]byte(password), salt, b.iter, hashSize)),
+		Iter: b.iter,
+	}, nil
+}
+
+// 调用方需持有写锁
+func (b *FileBackend) save() error {
+	if b.path == "" {
+		return nil
+	}
+	data, e := json.MarshalIndent(b.users, "", "  ")
+	if e != nil {
+		return e
+	}
+	if e = os.MkdirAll(filepath.Dir(b.path), 0755); e != nil {
+		return e
+	}
+	tmp := b.path + ".tmp"
+	if e = ioutil.WriteFile(tmp, data, 0600); e != nil {
+		return e
+	}
+	return os.Rename(tmp, b.path)
+}
+
+// RFC 8018 PBKDF2, PRF为HMAC-SHA256
+func pbkdf2(password, salt []byte, iter, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	var (
+		key   = make([]byte, 0, keyLen)
+		u     = make([]byte, 0, sha256.Size)
+		block [4]byte
+	)
+	for i := uint32(1); len(key) < keyLen; i++ {
+		binary.BigEndian.PutUint32(block[:], i)
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(block[:])
+		u = prf.Sum(u[:0])
+		t := append([]byte(nil), u...)
+		for n := 1; n < iter; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
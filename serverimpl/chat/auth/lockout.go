@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// 超过该数量时清理已过期的失败记录
+const maxTracked = 10000
+
+// 未配置用户名的阈值时, 取来源阈值的倍数
+const defaultNameMaxFactor = 4
+
+// 按用户名和来源IP记录连续失败的次数, 达到上限后锁定该来源一段时间, 其它IP登录同一用户名不受影响;
+// 同时按用户名累计各来源的失败, 达到更高的上限后锁定该用户名, 防止换IP猜密码
+// 最后一次失败超过锁定时长后重新计数
+type Lockout struct {
+	sync.Mutex
+	lock    time.Duration
+	sources failureSet // 键为lockKey(username, ip)
+	names   failureSet // 键为用户名
+}
+
+type failureSet struct {
+	max int
+	m   map[string]*failures
+}
+
+type failures struct {
+	count int
+	last  time.Time
+	until time.Time
+}
+
+// max为0时不锁定, nameMax为0时取max的defaultNameMaxFactor倍
+func NewLockout(max, nameMax int, lock time.Duration) *Lockout {
+	if max <= 0 || lock <= 0 {
+		return nil
+	}
+	if nameMax <= 0 {
+		nameMax = max * defaultNameMaxFactor
+	}
+	return &Lockout{
+		lock:    lock,
+		sources: failureSet{max: max, m: map[string]*failures{}},
+		names:   failureSet{max: nameMax, m: map[string]*failures{}},
+	}
+}
+
+func lockKey(username, ip string) string {
+	return username + "\x00" + ip
+}
+
+// 来源或用户名被锁定时返回较晚的解锁时间
+func (l *Lockout) Locked(username, ip string, now time.Time) (time.Time, bool) {
+	if l == nil {
+		return time.Time{}, false
+	}
+	l.Lock()
+	defer l.Unlock()
+
+	var until time.Time
+	for _, f := range []*failures{l.sources.m[lockKey(username, ip)], l.names.m[username]} {
+		if f != nil && now.Before(f.until) && f.until.After(until) {
+			until = f.until
+		}
+	}
+	return until, !until.IsZero()
+}
+
+func (l *Lockout) Record(username, ip string, ok bool, now time.Time) {
+	if l == nil {
+		return
+	}
+	l.Lock()
+	defer l.Unlock()
+
+	l.sources.record(lockKey(username, ip), ok, now, l.lock)
+	l.names.record(username, ok, now, l.lock)
+}
+
+func (s *failureSet) record(key string, ok bool, now time.Time, lock time.Duration) {
+	if ok {
+		delete(s.m, key)
+		return
+	}
+	f := s.m[key]
+	if f == nil || now.Sub(f.last) > lock {
+		if len(s.m) >= maxTracked {
+			s.sweep(now, lock)
+		}
+		f = &failures{}
+		s.m[key] = f
+	}
+	f.count++
+	f.last = now
+	if f.count >= s.max {
+		f.count = 0
+		f.until = now.Add(lock)
+	}
+}
+
+func (s *failureSet) sweep(now time.Time, lock time.Duration) {
+	for key, f := range s.m {
+		if now.Sub(f.last) > lock && !now.Before(f.until) {
+			delete(s.m, key)
+		}
+	}
+}
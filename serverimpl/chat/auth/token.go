@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// 网页登录签发的令牌: base64url(载荷).base64url(HMAC-SHA256(载荷)), 密钥由双方共享
+type TokenBackend struct {
+	secret []byte
+}
+
+type tokenPayload struct {
+	Sub string `json:"sub"` // 用户名
+	Exp int64  `json:"exp"` // 过期时间, unix秒
+}
+
+func NewTokenBackend(secret []byte) *TokenBackend {
+	return &TokenBackend{secret: secret}
+}
+
+// 供网页登录和测试签发令牌
+func Sign(secret []byte, username string, expire time.Time) string {
+	payload, _ := json.Marshal(&tokenPayload{Sub: username, Exp: expire.Unix()})
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(mac(secret, payload))
+}
+
+func (b *TokenBackend) Accepts(c Credentials) bool {
+	return c.Token != ""
+}
+
+func (b *TokenBackend) Verify(username string, c Credentials, now time.Time) error {
+	parts := strings.Split(c.Token, ".")
+	if len(parts) != 2 {
+		return ErrBadToken
+	}
+	enc := base64.RawURLEncoding
+	payload, e1 := enc.DecodeString(parts[0])
+	sig, e2 := enc.DecodeString(parts[1])
+	if e1 != nil || e2 != nil || !hmac.Equal(sig, mac(b.secret, payload)) {
+		return ErrBadToken
+	}
+
+	var p tokenPayload
+	if e := json.Unmarshal(payload, &p); e != nil || p.Sub != username {
+		return ErrBadToken
+	}
+	if now.Unix() >= p.Exp {
+		return ErrTokenExpired
+	}
+	return nil
+}
+
+func mac(secret, payload []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write(payload)
+	return h.Sum(nil)
+}
@@ -8,11 +8,61 @@ import (
 )
 
 type ServerCfg struct {
-	MaxConnNum            int   `json:"max_conn_num"`
-	MaxExecFuncTime       int   `json:"max_exec_func_time"`
-	GatePendingWriteNum   int   `json:"gate_pending_write_num"`
-	ConnNumPerSecond      int32 `json:"conn_num_per_second"`
-	PlayerInteractiveTime int   `json:"player_interactive_time"`
+	MaxConnNum            int    `json:"max_conn_num"`
+	MaxExecFuncTime       int    `json:"max_exec_func_time"`
+	GatePendingWriteNum   int    `json:"gate_pending_write_num"`
+	ConnNumPerSecond      int32  `json:"conn_num_per_second"`
+	PlayerInteractiveTime int    `json:"player_interactive_time"`
+	WSAddr                string `json:"ws_addr"`        // 为空则不开启websocket监听
+	WSMaxMsgLen           uint32 `json:"ws_max_msg_len"` // 单个websocket帧的最大长度
+	TLSCertFile           string `json:"tls_cert_file"`  // 为空则使用明文
+	TLSKeyFile            string `json:"tls_key_file"`
+	TLSClientCAFile       string `json:"tls_client_ca_file"`  // 不为空则要求客户端证书
+	Encrypt               bool   `json:"encrypt"`             // 是否要求客户端先完成密钥交换
+	IdentityKeyFile       string `json:"identity_key_file"`   // 服务端签名私钥, 不存在时自动生成
+	DrainTimeout          int    `json:"drain_timeout"`       // 秒, 退出时排空连接的时限
+	SessionGraceTime      int    `json:"session_grace_time"`  // 秒, 断线后保留会话等待恢复, 为0则不保留
+	ResendBufferSize      int    `json:"resend_buffer_size"`  // 每个玩家缓存的未确认下行消息数, 为0则下行消息不带序号
+	RoomStateFile         string `json:"room_state_file"`     // 退出时保存房间状态, 启动时恢复
+	RejectLegacyFrame     bool   `json:"reject_legacy_frame"` // 旧客户端迁移完成后开启, 拒绝1字节头长度的旧格式帧
+
+	CompressCodecs  []string         `json:"compress_codecs"`   // 压缩算法偏好, 为空则使用默认顺序
+	MinCompressSize int32            `json:"min_compress_size"` // 默认压缩阈值, 0使用默认值, 负数表示不压缩
+	MsgCompressSize map[string]int32 `json:"msg_compress_size"` // 按消息名覆盖压缩阈值
+
+	HistoryDir    string `json:"history_dir"`    // 聊天记录目录, 为空则只保存在内存中
+	HistoryRetain int    `json:"history_retain"` // 每个房间至少保留的聊天记录条数
+	HistoryFsync  string `json:"history_fsync"`  // 刷盘策略: interval(默认), always, none
+	InboxFile     string `json:"inbox_file"`     // 退出时保存离线私聊, 启动时恢复
+	InboxSize     int    `json:"inbox_size"`     // 每个玩家收件箱的上限, 为0使用默认值
+
+	InboxRecipients int `json:"inbox_recipients"` // 收件箱中收件人数的上限, 为0使用默认值
+
+	FilterPolicies map[string]string `json:"filter_policies"` // 按场景(name, room_chat, private_chat)处理敏感词: mask, reject, flag
+
+	ChatRate       float64 `json:"chat_rate"`        // 每个玩家每秒可发的聊天消息数(房间与私聊合计), 为0不限制
+	ChatBurst      int     `json:"chat_burst"`       // 玩家可连续发送的条数
+	RoomChatRate   float64 `json:"room_chat_rate"`   // 每个房间每秒可发的聊天消息数, 为0不限制
+	RoomChatBurst  int     `json:"room_chat_burst"`  // 房间可连续发送的条数
+	RepeatLimit    int     `json:"repeat_limit"`     // 连续发送相同内容的上限, 为0不限制
+	FloodMuteTime  int     `json:"flood_mute_time"`  // 秒, 第二次违规起禁言, 之后每次翻倍, 为0只警告
+	FloodKickCount int     `json:"flood_kick_count"` // 违规次数达到后踢下线, 为0不踢
+
+	AdminNames []string `json:"admin_names"` // 服务器管理员的用户名, 在所有房间有ROLE_ADMIN权限
+	BanFile    string   `json:"ban_file"`    // 封禁列表, 修改后立即保存
+
+	NameFile        string `json:"name_file"`         // 保留的用户名, 修改后立即保存
+	NameReserveDays int    `json:"name_reserve_days"` // 保留的用户名连续多少天未登录后释放, 为0不允许保留
+
+	AuthBackends        []string `json:"auth_backends"`          // 启用的认证方式: password, token; 为空则不认证
+	AccountFile         string   `json:"account_file"`           // password认证的账号文件
+	AuthTokenSecret     string   `json:"auth_token_secret"`      // token认证的HMAC密钥, 与网页登录共用
+	AuthMaxFailures     int      `json:"auth_max_failures"`      // 同一IP对同一用户名连续失败多少次后锁定该来源, 为0不锁定
+	AuthNameMaxFailures int      `json:"auth_name_max_failures"` // 同一用户名在所有IP上累计失败多少次后锁定该用户名, 为0取auth_max_failures的4倍
+	AuthLockTime        int      `json:"auth_lock_time"`         // 秒, 锁定时长
+
+	AdminAddr  string `json:"admin_addr"`  // 管理http端口, 为空则不开启
+	AdminToken string `json:"admin_token"` // 管理接口的Bearer令牌, 为空则只开放/metrics
 }
 
 var Server *ServerCfg
@@ -0,0 +1,188 @@
+package game
+
+import (
+	"cloudcadetest/framework/log"
+	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/search"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 管理接口使用的操作, 均需在主协程中调用
+
+const systemName = "system"
+
+type MemberView struct {
+	Name       string     `json:"name"`
+	FD         int64      `json:"fd"`
+	Addr       string     `json:"addr"`
+	LoginTime  time.Time  `json:"login_time"`
+	Role       string     `json:"role,omitempty"`
+	MutedUntil *time.Time `json:"muted_until,omitempty"`
+}
+
+type RoomView struct {
+	ID      int64        `json:"id"`
+	Name    string       `json:"name,omitempty"`
+	History int          `json:"history"`
+	Members []MemberView `json:"members"`
+}
+
+// 平滑重启时旧进程保存状态前不能管理
+var ErrLoading = errors.New("server is loading state")
+
+// 在主协程中执行f并等待完成, 供其他协程调用; 状态加载前不执行, 返回ErrLoading
+func RunSync(ctx context.Context, name string, f func()) error {
+	var err error
+	done := make(chan struct{})
+	SM.RunInSkeleton(name, func() {
+		defer close(done)
+		if !RoomMgr.loaded {
+			err = ErrLoading
+			return
+		}
+		f()
+	})
+
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *Manager) Rooms() []RoomView {
+	now := time.Now()
+	rooms := make([]RoomView, 0, len(m.rooms))
+	for id, r := range m.rooms {
+		rv := RoomView{
+			ID:      id,
+			Name:    r.name,
+			History: m.history.Len(id),
+			Members: make([]MemberView, 0, len(r.members)),
+		}
+		for fd := range r.members {
+			p := m.players[fd]
+			if p == nil {
+				continue
+			}
+			mv := MemberView{
+				Name:      p.username,
+				FD:        fd,
+				Addr:      p.Addr(),
+				LoginTime: p.LoginTime,
+			}
+			if role := roleOf(r, p.username); role != pb.ROOM_ROLE_ROLE_MEMBER {
+				mv.Role = role.String()
+			}
+			if until, ok := m.mutedUntil(p, now); ok {
+				mv.MutedUntil = &until
+			} else if until, ok := r.mutedUntil(p.username, now); ok {
+				mv.MutedUntil = &until
+			}
+			rv.Members = append(rv.Members, mv)
+		}
+		sort.Slice(rv.Members, func(i, j int) bool { return rv.Members[i].Name < rv.Members[j].Name })
+		rooms = append(rooms, rv)
+	}
+	sort.Slice(rooms, func(i, j int) bool { return rooms[i].ID < rooms[j].ID })
+	return rooms
+}
+
+func (m *Manager) Kick(name, msg string) error {
+	p := m.playersByName[name]
+	if p == nil {
+		return fmt.Errorf("player %s not found", name)
+	}
+	p.Kick(pb.KICK_REASON_KICK_BY_ADMIN, msg)
+	return nil
+}
+
+// 全服禁言按用户名记录, 重新登录后仍然有效; d不大于0时解除禁言
+func (m *Manager) Mute(name string, d time.Duration) error {
+	now := time.Now()
+	for n, until := range m.mutes {
+		if !now.Before(until) {
+			delete(m.mutes, n)
+		}
+	}
+	if d <= 0 {
+		if _, ok := m.mutes[name]; !ok {
+			return fmt.Errorf("%s is not muted", name)
+		}
+		delete(m.mutes, name)
+		log.Release("admin unmuted %s", name)
+		return nil
+	}
+	if e := validateName(name); e != nil {
+		return e
+	}
+	m.mutes[name] = now.Add(d)
+	log.Release("admin muted %s until %s", name, m.mutes[name].Format(time.RFC3339))
+	return nil
+}
+
+// roomID为0时发给所有房间
+func (m *Manager) Notice(roomID int64, content string) error {
+	if roomID == 0 {
+		for _, r := range m.rooms {
+			r.notice(content)
+		}
+		return nil
+	}
+
+	r := m.rooms[roomID]
+	if r == nil {
+		return fmt.Errorf("room %d not found", roomID)
+	}
+	r.notice(content)
+	return nil
+}
+
+// 通知并移出房间内所有成员, 成员保持在线
+func (m *Manager) CloseRoom(roomID int64) error {
+	r := m.rooms[roomID]
+	if r == nil {
+		return fmt.Errorf("room %d not found", roomID)
+	}
+
+	ntf := &pb.CSNtfBody{RoomClosed: &pb.CSNtfRoomClosed{
+		RoomID: roomID,
+	}}
+	for fd := range r.members {
+		p := m.players[fd]
+		if p == nil {
+			continue
+		}
+		p.SendClient(pb.CSMsgID_NTF_ROOM_CLOSED, ntf, nil)
+		p.SetRoomID(0)
+	}
+	r.members = map[int64]time.Time{}
+	m.DeleteRoom(roomID)
+	return nil
+}
+
+func (m *Manager) Search(roomID int64, q *search.Query) ([]*pb.HistoryChat, bool, error) {
+	r := m.rooms[roomID]
+	if r == nil {
+		return nil, false, fmt.Errorf("room %d not found", roomID)
+	}
+	return r.index.Search(q)
+}
+
+// 重新加载所有过滤器的词表, 出错时各过滤器保留原词表
+func (m *Manager) ReloadWords() error {
+	if e := m.filter.Reload(); e != nil {
+		return e
+	}
+	for id, r := range m.rooms {
+		if e := r.filter.Reload(); e != nil {
+			return fmt.Errorf("room %d:%s", id, e.Error())
+		}
+	}
+	return nil
+}
@@ -44,3 +44,28 @@ func GetAgentPlayerCount() int {
 
 	return len(agentSet)
 }
+
+// 遍历当前所有连接, 回调在锁外执行
+func RangeAgentPlayers(f func(IPlayer)) {
+	agentSetLock.Lock()
+	players := make([]IPlayer, 0, len(agentSet))
+	for _, p := range agentSet {
+		players = append(players, p)
+	}
+	agentSetLock.Unlock()
+
+	for _, p := range players {
+		f(p)
+	}
+}
+
+// 所有连接尚未写出的消息数
+func GetAgentPendingWrites() int {
+	num := 0
+	RangeAgentPlayers(func(ip IPlayer) {
+		if p, ok := ip.(*Agent); ok {
+			num += p.GetConn().WriteQueueLen()
+		}
+	})
+	return num
+}
@@ -0,0 +1,73 @@
+package game
+
+import (
+	"cloudcadetest/framework/log"
+	"cloudcadetest/serverimpl/chat/auth"
+	"cloudcadetest/serverimpl/chat/conf"
+	"errors"
+	"time"
+)
+
+// 按配置组装认证后端, 配置有误的后端不启用
+func openAuth() (*auth.Authenticator, *auth.FileBackend) {
+	var (
+		backends []auth.Backend
+		accounts *auth.FileBackend
+	)
+	for _, name := range conf.Server.AuthBackends {
+		switch name {
+		case "password":
+			b, e := auth.OpenFileBackend(conf.Server.AccountFile)
+			if e != nil {
+				log.Error("open account file %s failed:%s", conf.Server.AccountFile, e.Error())
+				continue
+			}
+			accounts = b
+			backends = append(backends, b)
+		case "token":
+			if conf.Server.AuthTokenSecret == "" {
+				log.Error("auth_token_secret is empty, token auth disabled")
+				continue
+			}
+			backends = append(backends, auth.NewTokenBackend([]byte(conf.Server.AuthTokenSecret)))
+		default:
+			log.Error("unknown auth backend %s", name)
+		}
+	}
+
+	lockout := auth.NewLockout(conf.Server.AuthMaxFailures, conf.Server.AuthNameMaxFailures, time.Duration(conf.Server.AuthLockTime)*time.Second)
+	return auth.New(lockout, backends...), accounts
+}
+
+// 在任务池中校验凭证, 同一用户名的校验依次进行; onFinish在主协程中调用
+func (m *Manager) authenticate(username, ip string, c auth.Credentials, onFinish func(e error)) {
+	if !m.auth.Enabled() {
+		onFinish(nil)
+		return
+	}
+	if m.authTasks == nil {
+		onFinish(m.auth.Verify(username, ip, c, time.Now()))
+		return
+	}
+
+	var err error
+	m.authTasks.AddTask(func() {
+		err = m.auth.Verify(username, ip, c, time.Now())
+	}, func() {
+		onFinish(err)
+	}, username)
+}
+
+// 账号文件线程安全, 哈希较慢, 不在主协程中调用
+func (m *Manager) SetPassword(username, password string) error {
+	if m.accounts == nil {
+		return errors.New("password auth is disabled")
+	}
+	if password == "" {
+		return m.accounts.Remove(username)
+	}
+	if e := validateName(username); e != nil {
+		return e
+	}
+	return m.accounts.SetPassword(username, password)
+}
@@ -0,0 +1,184 @@
+package game
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 服务器封禁列表, 网关接受连接时在其协程中检查IP, 其余操作在主协程中
+type ban struct {
+	Until  time.Time `json:"until"` // 零值表示永久
+	Reason string    `json:"reason,omitempty"`
+	By     string    `json:"by,omitempty"`
+}
+
+func (b *ban) active(now time.Time) bool {
+	return b.Until.IsZero() || now.Before(b.Until)
+}
+
+type banList struct {
+	sync.RWMutex
+	names map[string]*ban
+	ips   map[string]*ban
+}
+
+// 保存到文件的格式
+type banFile struct {
+	Names map[string]*ban `json:"names,omitempty"`
+	IPs   map[string]*ban `json:"ips,omitempty"`
+}
+
+type BanView struct {
+	Name   string     `json:"name,omitempty"`
+	IP     string     `json:"ip,omitempty"`
+	Until  *time.Time `json:"until,omitempty"`
+	Reason string     `json:"reason,omitempty"`
+	By     string     `json:"by,omitempty"`
+}
+
+var bans = newBanList()
+
+func newBanList() *banList {
+	return &banList{
+		names: map[string]*ban{},
+		ips:   map[string]*ban{},
+	}
+}
+
+// 供网关在接受连接时调用
+func AllowIP(ip string) bool {
+	return bans.ip(ip, time.Now()) == nil
+}
+
+// 生效中的封禁, 没有时为nil
+func (l *banList) name(name string, now time.Time) *ban {
+	l.RLock()
+	defer l.RUnlock()
+	if b := l.names[name]; b != nil && b.active(now) {
+		return b
+	}
+	return nil
+}
+
+func (l *banList) ip(ip string, now time.Time) *ban {
+	l.RLock()
+	defer l.RUnlock()
+	if b := l.ips[ip]; b != nil && b.active(now) {
+		return b
+	}
+	return nil
+}
+
+// name或ip为空时忽略
+func (l *banList) add(name, ip string, b *ban) {
+	l.Lock()
+	defer l.Unlock()
+	if name != "" {
+		l.names[name] = b
+	}
+	if ip != "" {
+		l.ips[ip] = b
+	}
+}
+
+func (l *banList) lift(name, ip string) bool {
+	l.Lock()
+	defer l.Unlock()
+	_, byName := l.names[name]
+	_, byIP := l.ips[ip]
+	delete(l.names, name)
+	delete(l.ips, ip)
+	return byName || byIP
+}
+
+// 生效中的封禁, 按用户名、IP排序
+func (l *banList) list(now time.Time) []BanView {
+	l.RLock()
+	defer l.RUnlock()
+
+	views := make([]BanView, 0, len(l.names)+len(l.ips))
+	add := func(name, ip string, b *ban) {
+		if !b.active(now) {
+			return
+		}
+		v := BanView{Name: name, IP: ip, Reason: b.Reason, By: b.By}
+		if !b.Until.IsZero() {
+			until := b.Until
+			v.Until = &until
+		}
+		views = append(views, v)
+	}
+	for name, b := range l.names {
+		add(name, "", b)
+	}
+	for ip, b := range l.ips {
+		add("", ip, b)
+	}
+	sort.Slice(views, func(i, j int) bool {
+		if views[i].Name != views[j].Name {
+			return views[i].Name < views[j].Name
+		}
+		return views[i].IP < views[j].IP
+	})
+	return views
+}
+
+// 过期的封禁不再保存
+func (l *banList) save(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	now := time.Now()
+	f := banFile{Names: map[string]*ban{}, IPs: map[string]*ban{}}
+	l.RLock()
+	for name, b := range l.names {
+		if b.active(now) {
+			f.Names[name] = b
+		}
+	}
+	for ip, b := range l.ips {
+		if b.active(now) {
+			f.IPs[ip] = b
+		}
+	}
+	l.RUnlock()
+
+	data, e := json.Marshal(&f)
+	if e != nil {
+		return e
+	}
+	return writeFile(path, data)
+}
+
+func (l *banList) load(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	data, e := ioutil.ReadFile(path)
+	if e != nil {
+		if os.IsNotExist(e) {
+			return nil
+		}
+		return e
+	}
+	var f banFile
+	if e = json.Unmarshal(data, &f); e != nil {
+		return e
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	for name, b := range f.Names {
+		l.names[name] = b
+	}
+	for ip, b := range f.IPs {
+		l.ips[ip] = b
+	}
+	return nil
+}
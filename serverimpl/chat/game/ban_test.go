@@ -0,0 +1,77 @@
+package game
+
+import (
+	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/auth"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBanList(t *testing.T) {
+	l := newBanList()
+	now := time.Now()
+	l.add("alice", "", &ban{Reason: "spam"})
+	l.add("bob", "10.0.0.2", &ban{Until: now.Add(time.Minute)})
+	l.add("", "10.0.0.3", &ban{Until: now.Add(-time.Second)})
+
+	if l.name("alice", now.Add(24*time.Hour)) == nil {
+		t.Fatal("permanent ban should not expire")
+	}
+	if l.name("bob", now) == nil || l.ip("10.0.0.2", now) == nil {
+		t.Fatal("bob should be banned by name and ip")
+	}
+	if l.name("bob", now.Add(2*time.Minute)) != nil {
+		t.Fatal("ban should expire")
+	}
+	if l.ip("10.0.0.3", now) != nil || l.name("carol", now) != nil {
+		t.Fatal("expired or missing ban should not match")
+	}
+	if views := l.list(now); len(views) != 3 || views[0].IP != "10.0.0.2" || views[1].Name != "alice" || views[1].Until != nil {
+		t.Fatalf("list %+v", views)
+	}
+
+	// 过期的封禁不保存
+	dir, _ := ioutil.TempDir("", "bans")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "bans.json")
+	if e := l.save(path); e != nil {
+		t.Fatal(e)
+	}
+	loaded := newBanList()
+	if e := loaded.load(path); e != nil {
+		t.Fatal(e)
+	}
+	if len(loaded.names) != 2 || len(loaded.ips) != 1 || loaded.name("alice", now).Reason != "spam" {
+		t.Fatalf("loaded names %v ips %v", loaded.names, loaded.ips)
+	}
+
+	if !loaded.lift("bob", "10.0.0.2") || loaded.name("bob", now) != nil || loaded.ip("10.0.0.2", now) != nil {
+		t.Fatal("lift should remove name and ip")
+	}
+	if loaded.lift("bob", "") {
+		t.Fatal("lift missing ban should fail")
+	}
+}
+
+func TestErrCode(t *testing.T) {
+	cases := map[error]pb.ERROR_CODE{
+		errNoPermission:                           pb.ERROR_CODE_NO_PERMISSION,
+		fmt.Errorf("%w: spam", errBanned):         pb.ERROR_CODE_BANNED,
+		fmt.Errorf("room %d not found", int64(1)): pb.ERROR_CODE_FAILED,
+		validateName("a b"):                       pb.ERROR_CODE_INVALID_NAME,
+		validateName("system"):                    pb.ERROR_CODE_NAME_TAKEN,
+		auth.ErrNoCredentials:                     pb.ERROR_CODE_AUTH_REQUIRED,
+		auth.ErrBadToken:                          pb.ERROR_CODE_AUTH_FAILED,
+		auth.ErrTokenExpired:                      pb.ERROR_CODE_TOKEN_EXPIRED,
+		fmt.Errorf("%w, retry", auth.ErrLocked):   pb.ERROR_CODE_ACCOUNT_LOCKED,
+	}
+	for e, code := range cases {
+		if got := errCode(e); got != code {
+			t.Errorf("%v: got %s want %s", e, got, code)
+		}
+	}
+}
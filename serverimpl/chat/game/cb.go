@@ -3,6 +3,8 @@ package game
 import (
 	"cloudcadetest/framework/log"
 	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/auth"
+	"time"
 )
 
 func reqLogin(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
@@ -10,20 +12,76 @@ func reqLogin(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
 		p.LogError("nil ReqLogin")
 		return
 	}
+	if !RoomMgr.loaded {
+		e := RoomMgr.queueLogin(func() {
+			if !p.IsDestroyed() {
+				reqLogin(p, req, rsp)
+			}
+		})
+		if e != nil {
+			rsp.ErrCode = errCode(e)
+			rsp.ErrMsg = e.Error()
+			p.SendClient(pb.CSMsgID_RSP_LOGIN, rsp, nil)
+		}
+		return
+	}
 
-	rsp.Login = &pb.CSRspLogin{}
+	// 加入房间时会下发历史消息, 需先确定压缩算法
+	c := CSProcessor.SelectCompressor(req.Login.Codecs)
+	p.SetCompressor(c)
+	rsp.Login = &pb.CSRspLogin{
+		Codec:             pb.COMPRESS_CODEC(c),
+		HeartbeatInterval: heartbeatInterval(),
+	}
 
-	roomID, e := RoomMgr.Join(p, req.Login.Username)
-	if e != nil {
-		rsp.ErrCode = pb.ERROR_CODE_FAILED
-		rsp.ErrMsg = e.Error()
-	} else {
-		rsp.Login.RoomID = roomID
-		rsp.Login.Username = req.Login.Username
+	finish := func(roomID int64, e error) {
+		if p.IsDestroyed() {
+			return
+		}
+		if e != nil {
+			rsp.ErrCode = errCode(e)
+			rsp.ErrMsg = e.Error()
+		} else {
+			rsp.Login.RoomID = roomID
+			rsp.Login.Username = p.GetUsername()
+			rsp.Login.ResumeToken = p.token
+		}
+
+		p.SendClient(pb.CSMsgID_RSP_LOGIN, rsp, nil)
+		if e == nil {
+			RoomMgr.notifyInbox(p)
+		}
+	}
+
+	// 会话已过期时按新登录处理
+	if req.Login.ResumeToken != "" {
+		roomID, e := RoomMgr.Resume(p, req.Login.Username, req.Login.ResumeToken, req.Login.Ack, req.Login.LastSeq)
+		if e == nil {
+			rsp.Login.Resumed = true
+			finish(roomID, nil)
+			return
+		}
+		p.LogRelease("resume failed:%s", e.Error())
 	}
-	p.SetUsername(req.Login.Username)
 
-	p.SendClient(pb.CSMsgID_RSP_LOGIN, rsp, nil)
+	// 新登录先校验名字, 再校验凭证
+	username, key := req.Login.Username, req.Login.NameKey
+	creds := auth.Credentials{Password: req.Login.Password, Token: req.Login.AuthToken}
+	RoomMgr.checkName(username, func(e error) {
+		if e != nil {
+			finish(0, e)
+			return
+		}
+		RoomMgr.authenticate(username, p.IP(), creds, func(e error) {
+			var roomID int64
+			if e == nil {
+				roomID, e = RoomMgr.Join(p, username, key)
+			} else {
+				p.LogRelease("auth %s failed:%s", username, e.Error())
+			}
+			finish(roomID, e)
+		})
+	})
 }
 
 func reqHeartbeat(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
@@ -32,7 +90,18 @@ func reqHeartbeat(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
 		return
 	}
 
-	rsp.Heartbeat = &pb.CSRspHeartbeat{}
+	// 确认随心跳上报, 重发写入失败的下行消息
+	ack := req.Heartbeat.Ack
+	RoomMgr.AddRoomTask(p.GetRoomID(), func() {
+		if e := CSProcessor.Resend(p, ack, false); e != nil {
+			p.LogWarn("resend failed:%s", e.Error())
+		}
+	}, nil)
+
+	rsp.Heartbeat = &pb.CSRspHeartbeat{
+		ClientTime: req.Heartbeat.ClientTime,
+		ServerTime: time.Now().UnixNano() / int64(time.Millisecond),
+	}
 	p.SendClient(pb.CSMsgID_RSP_HEARTBEAT, rsp, nil)
 }
 
@@ -43,7 +112,10 @@ func reqRoomChat(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
 	}
 
 	rsp.RoomChat = &pb.CSRspRoomChat{}
-	RoomMgr.RoomChat(p.GetFD(), p.GetRoomID(), req.RoomChat.Content)
+	if e := RoomMgr.RoomChat(p.GetFD(), p.GetRoomID(), req.RoomChat.Content); e != nil {
+		rsp.ErrCode = pb.ERROR_CODE_FAILED
+		rsp.ErrMsg = e.Error()
+	}
 	p.SendClient(pb.CSMsgID_RSP_ROOM_CHAT, rsp, nil)
 }
 
@@ -66,27 +138,212 @@ func reqJoinRoom(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
 		return
 	}
 
-	log.Release("player:%s join room", p.username)
+	log.Release("player:%s join room:%d create:%t", p.username, req.JoinRoom.RoomID, req.JoinRoom.CreateNew)
 
 	rsp.JoinRoom = &pb.CSRspJoinRoom{}
-	if _, e := RoomMgr.Join(p, p.GetUsername()); e != nil {
+	r, e := RoomMgr.JoinRoom(p, req.JoinRoom.RoomID, req.JoinRoom.CreateNew, req.JoinRoom.Name)
+	if e != nil {
 		rsp.ErrCode = pb.ERROR_CODE_FAILED
 		rsp.ErrMsg = e.Error()
+	} else {
+		rsp.JoinRoom.RoomID = r.id
+		rsp.JoinRoom.Name = r.name
 	}
 
 	p.SendClient(pb.CSMsgID_RSP_JOIN_ROOM, rsp, nil)
 }
 
+func reqLeaveRoom(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
+	if req.LeaveRoom == nil {
+		p.LogError("nil LeaveRoom")
+		return
+	}
+
+	rsp.LeaveRoom = &pb.CSRspLeaveRoom{}
+	roomID, e := RoomMgr.LeaveRoom(p)
+	if e != nil {
+		rsp.ErrCode = pb.ERROR_CODE_FAILED
+		rsp.ErrMsg = e.Error()
+	} else {
+		rsp.LeaveRoom.RoomID = roomID
+	}
+
+	p.SendClient(pb.CSMsgID_RSP_LEAVE_ROOM, rsp, nil)
+}
+
+func reqRoomMembers(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
+	if req.RoomMembers == nil {
+		p.LogError("nil RoomMembers")
+		return
+	}
+
+	rsp.RoomMembers = &pb.CSRspRoomMembers{}
+	r, members, e := RoomMgr.RoomMembers(p)
+	if e != nil {
+		rsp.ErrCode = pb.ERROR_CODE_FAILED
+		rsp.ErrMsg = e.Error()
+	} else {
+		rsp.RoomMembers.RoomID = r.id
+		rsp.RoomMembers.Members = members
+	}
+
+	p.SendClient(pb.CSMsgID_RSP_ROOM_MEMBERS, rsp, nil)
+}
+
+func reqRoomHistory(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
+	if req.RoomHistory == nil {
+		p.LogError("nil RoomHistory")
+		return
+	}
+
+	page, e := RoomMgr.HistoryPage(p, req.RoomHistory)
+	if e != nil {
+		rsp.ErrCode = pb.ERROR_CODE_FAILED
+		rsp.ErrMsg = e.Error()
+		rsp.RoomHistory = &pb.CSRspRoomHistory{}
+	} else {
+		rsp.RoomHistory = page
+	}
+
+	p.SendClient(pb.CSMsgID_RSP_ROOM_HISTORY, rsp, nil)
+}
+
+func reqSearchHistory(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
+	if req.SearchHistory == nil {
+		p.LogError("nil SearchHistory")
+		return
+	}
+
+	e := RoomMgr.SearchHistory(p, req.SearchHistory, func(ret *pb.CSRspSearchHistory, e error) {
+		rsp.SearchHistory = ret
+		if e != nil {
+			rsp.ErrCode = pb.ERROR_CODE_FAILED
+			rsp.ErrMsg = e.Error()
+		}
+		p.SendClient(pb.CSMsgID_RSP_SEARCH_HISTORY, rsp, nil)
+	})
+	if e != nil {
+		rsp.ErrCode = pb.ERROR_CODE_FAILED
+		rsp.ErrMsg = e.Error()
+		rsp.SearchHistory = &pb.CSRspSearchHistory{}
+		p.SendClient(pb.CSMsgID_RSP_SEARCH_HISTORY, rsp, nil)
+	}
+}
+
 func reqChat(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
 	if req.Chat == nil {
 		return
 	}
 
 	rsp.Chat = &pb.CSRspChat{}
-	if e := RoomMgr.Chat(p, req.Chat); e != nil {
+	e := RoomMgr.Chat(p, req.Chat, func(msg *pb.PrivateMsg, stored bool, e error) {
+		if e != nil {
+			rsp.ErrCode = errCode(e)
+			rsp.ErrMsg = e.Error()
+		} else {
+			rsp.Chat.MsgID = msg.ID
+			rsp.Chat.Stored = stored
+		}
+		p.SendClient(pb.CSMsgID_RSP_CHAT, rsp, nil)
+	})
+	if e != nil {
+		rsp.ErrCode = pb.ERROR_CODE_FAILED
+		rsp.ErrMsg = e.Error()
+		p.SendClient(pb.CSMsgID_RSP_CHAT, rsp, nil)
+	}
+}
+
+func reqSetUsername(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
+	if req.SetUsername == nil {
+		p.LogError("nil SetUsername")
+		return
+	}
+
+	rsp.SetUsername = &pb.CSRspSetUsername{}
+	RoomMgr.SetName(p, req.SetUsername.Username, req.SetUsername.Reserve, func(key string, e error) {
+		if e != nil {
+			rsp.ErrCode = errCode(e)
+			rsp.ErrMsg = e.Error()
+		}
+		rsp.SetUsername.Username = p.GetUsername()
+		rsp.SetUsername.NameKey = key
+		p.SendClient(pb.CSMsgID_RSP_SET_USERNAME, rsp, nil)
+	})
+}
+
+func reqInboxRead(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
+	if req.InboxRead == nil {
+		p.LogError("nil InboxRead")
+		return
+	}
+
+	rsp.InboxRead = &pb.CSRspInboxRead{}
+	n, e := RoomMgr.InboxRead(p, req.InboxRead.UpToID)
+	if e != nil {
 		rsp.ErrCode = pb.ERROR_CODE_FAILED
 		rsp.ErrMsg = e.Error()
+	} else {
+		rsp.InboxRead.Unread = int32(n)
+	}
+
+	p.SendClient(pb.CSMsgID_RSP_INBOX_READ, rsp, nil)
+}
+
+func reqSetRole(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
+	if req.SetRole == nil {
+		p.LogError("nil SetRole")
+		return
+	}
+
+	rsp.SetRole = &pb.CSRspSetRole{}
+	if e := RoomMgr.SetRole(p, req.SetRole.Username, req.SetRole.Role); e != nil {
+		rsp.ErrCode = errCode(e)
+		rsp.ErrMsg = e.Error()
+	}
+	p.SendClient(pb.CSMsgID_RSP_SET_ROLE, rsp, nil)
+}
+
+func reqRoomMute(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
+	if req.RoomMute == nil {
+		p.LogError("nil RoomMute")
+		return
+	}
+
+	rsp.RoomMute = &pb.CSRspRoomMute{}
+	until, e := RoomMgr.RoomMute(p, req.RoomMute.Username, time.Duration(req.RoomMute.Seconds)*time.Second)
+	if e != nil {
+		rsp.ErrCode = errCode(e)
+		rsp.ErrMsg = e.Error()
+	} else if !until.IsZero() {
+		rsp.RoomMute.Until = until.UnixNano() / int64(time.Millisecond)
+	}
+	p.SendClient(pb.CSMsgID_RSP_ROOM_MUTE, rsp, nil)
+}
+
+func reqRoomKick(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
+	if req.RoomKick == nil {
+		p.LogError("nil RoomKick")
+		return
 	}
 
-	p.SendClient(pb.CSMsgID_REQ_CHAT, rsp, nil)
+	rsp.RoomKick = &pb.CSRspRoomKick{}
+	if e := RoomMgr.RoomKick(p, req.RoomKick.Username, req.RoomKick.Reason); e != nil {
+		rsp.ErrCode = errCode(e)
+		rsp.ErrMsg = e.Error()
+	}
+	p.SendClient(pb.CSMsgID_RSP_ROOM_KICK, rsp, nil)
+}
+
+func reqBan(p *Agent, req *pb.CSReqBody, rsp *pb.CSRspBody) {
+	if req.Ban == nil {
+		p.LogError("nil Ban")
+		return
+	}
+
+	rsp.Ban = &pb.CSRspBan{}
+	if e := RoomMgr.BanBy(p, req.Ban); e != nil {
+		rsp.ErrCode = errCode(e)
+		rsp.ErrMsg = e.Error()
+	}
+	p.SendClient(pb.CSMsgID_RSP_BAN, rsp, nil)
 }
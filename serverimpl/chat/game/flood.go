@@ -0,0 +1,164 @@
+package game
+
+import (
+	"cloudcadetest/common/ratelimit"
+	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/conf"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 聊天限流, 在handleCS中先于处理函数执行, 只在主协程中访问
+// 玩家超限或重复发送时按违规次数逐级处罚: 警告、禁言(时长逐次翻倍)、踢下线
+// 违规记录按用户名与IP保存在Manager中, 重新登录不会清零; 房间超限只拒绝, 不计违规
+
+const (
+	strikeInterval = time.Second      // 同一秒内的多次违规只计一次
+	strikeDecay    = 10 * time.Minute // 超过该时间没有违规则清零
+	maxFloodMute   = 24 * time.Hour
+	maxPenalties   = 10000 // 超过该数量时清理已过期的违规记录
+)
+
+// 连接内的限速与重复检测
+type floodState struct {
+	chat    *ratelimit.Bucket // 为nil则不限速
+	last    string            // 最近一条聊天内容
+	repeats int               // last连续发送的次数
+}
+
+type penalty struct {
+	strikes    int
+	struckAt   time.Time
+	mutedUntil time.Time // 刷屏禁言的截止时间
+}
+
+func penaltyKey(p *Agent) string {
+	return p.GetUsername() + "\x00" + p.IP()
+}
+
+func newFloodState() *floodState {
+	f := &floodState{}
+	if conf.Server.ChatRate > 0 {
+		f.chat = ratelimit.NewBucket(conf.Server.ChatRate, conf.Server.ChatBurst)
+	}
+	return f
+}
+
+func newRoomLimit() *ratelimit.Bucket {
+	if conf.Server.RoomChatRate <= 0 {
+		return nil
+	}
+	return ratelimit.NewBucket(conf.Server.RoomChatRate, conf.Server.RoomChatBurst)
+}
+
+// 返回非nil时拒绝该请求
+func checkFlood(p *Agent, reqID pb.CSMsgID, req *pb.CSReqBody, now time.Time) error {
+	var (
+		content string
+		r       *Room
+	)
+	switch {
+	case reqID == pb.CSMsgID_REQ_ROOM_CHAT && req.RoomChat != nil:
+		content = req.RoomChat.Content
+		r = RoomMgr.rooms[p.GetRoomID()]
+	case reqID == pb.CSMsgID_REQ_CHAT && req.Chat != nil:
+		content = req.Chat.Content
+	default:
+		return nil
+	}
+	// 禁言中的消息由处理函数拒绝, 不计违规
+	if _, muted := RoomMgr.mutedUntil(p, now); muted {
+		return nil
+	}
+	if r != nil {
+		if _, muted := r.mutedUntil(p.GetUsername(), now); muted {
+			return nil
+		}
+	}
+
+	if p.flood == nil {
+		p.flood = newFloodState()
+	}
+	f := p.flood
+	if content == f.last {
+		f.repeats++
+	} else {
+		f.last, f.repeats = content, 1
+	}
+
+	if f.chat != nil && !f.chat.Allow(now) {
+		return RoomMgr.penalize(p, "sending too fast", now)
+	}
+	if limit := conf.Server.RepeatLimit; limit > 0 && f.repeats > limit {
+		return RoomMgr.penalize(p, "repeated message", now)
+	}
+	if r != nil && r.limit != nil && !r.limit.Allow(now) {
+		floodCounter.With("room").Inc()
+		return errors.New("room is too busy, try later")
+	}
+	return nil
+}
+
+func (m *Manager) penalize(p *Agent, reason string, now time.Time) error {
+	key := penaltyKey(p)
+	f := m.penalties[key]
+	if f == nil {
+		if len(m.penalties) >= maxPenalties {
+			m.sweepPenalties(now)
+		}
+		f = &penalty{}
+		m.penalties[key] = f
+	}
+	if !f.struckAt.IsZero() && now.Sub(f.struckAt) > strikeDecay {
+		f.strikes = 0
+	}
+	if f.strikes > 0 && now.Sub(f.struckAt) < strikeInterval {
+		floodCounter.With("reject").Inc()
+		return errors.New(reason)
+	}
+	f.strikes++
+	f.struckAt = now
+
+	kickAt := conf.Server.FloodKickCount
+	switch {
+	case kickAt > 0 && f.strikes >= kickAt:
+		floodCounter.With("kick").Inc()
+		p.LogRelease("kicked for flooding, strikes:%d", f.strikes)
+		p.Kick(pb.KICK_REASON_KICK_FLOOD, reason)
+		return fmt.Errorf("%s, kicked", reason)
+	case f.strikes >= 2 && conf.Server.FloodMuteTime > 0:
+		d := time.Duration(conf.Server.FloodMuteTime) * time.Second
+		for i := 2; i < f.strikes && d < maxFloodMute; i++ {
+			d *= 2
+		}
+		if d > maxFloodMute {
+			d = maxFloodMute
+		}
+		f.mutedUntil = now.Add(d)
+		floodCounter.With("mute").Inc()
+		p.LogRelease("muted %s for flooding, strikes:%d", d, f.strikes)
+		return fmt.Errorf("%s, muted for %s", reason, d)
+	default:
+		floodCounter.With("warn").Inc()
+		return fmt.Errorf("%s, please slow down", reason)
+	}
+}
+
+// 违规已清零且禁言已结束的记录
+func (m *Manager) sweepPenalties(now time.Time) {
+	for key, f := range m.penalties {
+		if now.Sub(f.struckAt) > strikeDecay && !now.Before(f.mutedUntil) {
+			delete(m.penalties, key)
+		}
+	}
+}
+
+// 刷屏禁言的截止时间
+func (m *Manager) floodMutedUntil(p *Agent, now time.Time) (time.Time, bool) {
+	f := m.penalties[penaltyKey(p)]
+	if f == nil || !now.Before(f.mutedUntil) {
+		return time.Time{}, false
+	}
+	return f.mutedUntil, true
+}
@@ -0,0 +1,121 @@
+package game
+
+import (
+	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/conf"
+	"testing"
+	"time"
+)
+
+func TestCheckFlood(t *testing.T) {
+	gt := newGMTest(t)
+	m := gt.m
+	conf.Server = &conf.ServerCfg{
+		ChatRate:      1,
+		ChatBurst:     2,
+		RepeatLimit:   2,
+		FloodMuteTime: 30,
+	}
+
+	p := m.playersByName["carol"]
+	now := time.Unix(1000, 0)
+	chat := func(content string) error {
+		req := &pb.CSReqBody{Chat: &pb.CSReqChat{Content: content}}
+		return checkFlood(p, pb.CSMsgID_REQ_CHAT, req, now)
+	}
+	muted := func() time.Time {
+		until, _ := m.mutedUntil(p, now)
+		return until
+	}
+	strikes := func() int {
+		return m.penalties[penaltyKey(p)].strikes
+	}
+
+	if chat("a") != nil || chat("b") != nil {
+		t.Fatal("burst should pass")
+	}
+	// 第一次违规只警告, 不禁言
+	if chat("c") == nil || !muted().IsZero() {
+		t.Fatal("first strike should only warn")
+	}
+
+	// 重复发送也计违规, 第二次起禁言
+	now = now.Add(10 * time.Second)
+	if chat("d") != nil || chat("d") != nil {
+		t.Fatal("repeats under limit should pass")
+	}
+	if chat("d") == nil || muted() != now.Add(30*time.Second) {
+		t.Fatalf("second strike should mute 30s, muted until %s", muted())
+	}
+	// 禁言期间不再计违规
+	if chat("d") != nil || strikes() != 2 {
+		t.Fatalf("strikes %d while muted", strikes())
+	}
+
+	// 禁言时长逐次翻倍
+	now = muted().Add(10 * time.Second)
+	chat("e")
+	chat("f")
+	if chat("g") == nil || muted() != now.Add(60*time.Second) {
+		t.Fatalf("third strike should mute 60s, muted until %s", muted())
+	}
+
+	// 长时间没有违规后重新计数
+	now = now.Add(strikeDecay + time.Minute)
+	chat("h")
+	chat("i")
+	if chat("j") == nil || strikes() != 1 || !muted().IsZero() {
+		t.Fatalf("strikes %d after decay", strikes())
+	}
+}
+
+// 违规记录不随连接清零, 被踢后从同一IP以同一名字重新登录继续累计
+func TestFloodAcrossReconnect(t *testing.T) {
+	gt := newGMTest(t)
+	m := gt.m
+	conf.Server = &conf.ServerCfg{ChatRate: 1, ChatBurst: 1, FloodMuteTime: 30, FloodKickCount: 3}
+
+	now := time.Unix(1000, 0)
+	login := func() *Agent {
+		return &Agent{conn: testConn{}, fd: UUID.Get(), username: "carol"}
+	}
+	flood := func(p *Agent) error {
+		req := &pb.CSReqBody{Chat: &pb.CSReqChat{Content: "spam"}}
+		checkFlood(p, pb.CSMsgID_REQ_CHAT, req, now)
+		return checkFlood(p, pb.CSMsgID_REQ_CHAT, req, now)
+	}
+
+	p := login()
+	flood(p)
+	now = now.Add(time.Minute)
+	flood(p)
+
+	// 重连后仍在禁言中
+	p = login()
+	if _, muted := m.mutedUntil(p, now); !muted {
+		t.Fatal("flood mute lost on reconnect")
+	}
+	now = now.Add(time.Minute)
+	if flood(p); !p.kicked {
+		t.Fatalf("third strike after reconnect should kick, strikes %d", m.penalties[penaltyKey(p)].strikes)
+	}
+	p = login()
+	now = now.Add(2 * time.Second)
+	if flood(p); !p.kicked {
+		t.Fatal("strikes reset after kick")
+	}
+
+	// 改名不能清零
+	m.names["carol"] = struct{}{}
+	m.playersByName["carol"] = p
+	m.rename(p, "caroline")
+	if f := m.penalties[penaltyKey(p)]; f == nil || f.strikes != 4 || len(m.penalties) != 1 {
+		t.Fatal("penalty not moved on rename")
+	}
+
+	// 过期的记录被清理
+	m.sweepPenalties(now.Add(strikeDecay + time.Hour))
+	if len(m.penalties) != 0 {
+		t.Fatalf("penalties %d after expiry", len(m.penalties))
+	}
+}
@@ -1,23 +1,80 @@
 package game
 
 import (
+	"cloudcadetest/common/compress"
+	"cloudcadetest/common/encrypt/kex"
 	"cloudcadetest/common/uuid"
 	"cloudcadetest/framework/module"
 	"cloudcadetest/framework/msg/cs"
+	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/conf"
+	"crypto/ed25519"
+	"fmt"
 )
 
+const defaultMinCompressSize = 1024
+
 var (
 	SM          *module.ServerMod
 	CSProcessor *cs.Processor
 	UUID        *uuid.UUID
 	RoomMgr     *Manager
+	Identity    ed25519.PrivateKey // 握手时用于签名的服务端身份密钥
 )
 
-func Init(sm *module.ServerMod) {
+// ready关闭后加载持久状态, 平滑重启时为旧进程保存状态之后
+func Init(sm *module.ServerMod, ready <-chan struct{}) {
 	SM = sm
-	CSProcessor = cs.New(sm, true, 10000, 1024, false)
+	CSProcessor = cs.New(sm, true, 10000, minCompressSize(), conf.Server.Encrypt)
+	CSProcessor.SetAcceptLegacyFrame(!conf.Server.RejectLegacyFrame)
+	initCompress()
+	if conf.Server.Encrypt {
+		var e error
+		if Identity, e = kex.LoadOrCreateIdentity(conf.Server.IdentityKeyFile); e != nil {
+			panic(fmt.Sprintf("load identity key failed:%s", e.Error()))
+		}
+	}
 	UUID = &uuid.UUID{}
 	RoomMgr = NewRoomMgr()
+	go func() {
+		<-ready
+		SM.RunInSkeleton("game.load", RoomMgr.load)
+	}()
 
 	registerHandler()
+	startIdleSweeper()
+}
+
+// 未配置时使用默认阈值, 负数表示不压缩
+func minCompressSize() int32 {
+	switch size := conf.Server.MinCompressSize; {
+	case size < 0:
+		return 0
+	case size == 0:
+		return defaultMinCompressSize
+	default:
+		return size
+	}
+}
+
+func initCompress() {
+	if len(conf.Server.CompressCodecs) > 0 {
+		pref := make([]compress.ID, 0, len(conf.Server.CompressCodecs))
+		for _, name := range conf.Server.CompressCodecs {
+			id, ok := compress.ByName(name)
+			if !ok {
+				panic(fmt.Sprintf("unknown compress codec:%s", name))
+			}
+			pref = append(pref, id)
+		}
+		CSProcessor.SetCompressPreference(pref)
+	}
+
+	for name, size := range conf.Server.MsgCompressSize {
+		id, ok := pb.CSMsgID_value[name]
+		if !ok {
+			panic(fmt.Sprintf("unknown msg in msg_compress_size:%s", name))
+		}
+		CSProcessor.SetMsgCompressSize(pb.CSMsgID(id), size)
+	}
 }
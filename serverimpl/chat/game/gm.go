@@ -0,0 +1,274 @@
+package game
+
+import (
+	"cloudcadetest/pb"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// GM命令, 房间聊天中以/开头的内容, 结果只发给执行者
+// 命令声明参数与所需角色, 由gmRegistry统一解析参数、检查权限后执行
+
+type gmArgKind int
+
+const (
+	gmString   gmArgKind = iota
+	gmInt                // 整数, min与max不都为0时检查范围
+	gmDuration           // 时长, 可写作90s、10m、1h, 纯数字按秒
+	gmText               // 剩余的全部内容, 只能是最后一个参数
+)
+
+type gmArg struct {
+	name     string
+	kind     gmArgKind
+	optional bool // 可选参数只能在必选参数之后
+	min, max int64
+}
+
+type gmCommand struct {
+	name string
+	args []gmArg
+	role pb.ROOM_ROLE // 执行需要的最低角色
+	help string
+	// 在主协程中调用, 结果通过c.reply返回, 异步执行时需先回到主协程
+	run func(c *gmCall)
+}
+
+// 一次命令执行
+type gmCall struct {
+	m       *Manager
+	p       *Agent
+	r       *Room
+	cmd     *gmCommand
+	args    map[string]interface{}
+	post    func(name string, f func())
+	onReply func(string)
+	replied bool
+}
+
+type gmRegistry struct {
+	cmds map[string]*gmCommand
+	// 把异步结果投递回主协程
+	post func(name string, f func())
+}
+
+func newGMRegistry(post func(name string, f func())) *gmRegistry {
+	g := &gmRegistry{cmds: map[string]*gmCommand{}, post: post}
+	g.register(&gmCommand{
+		name: "help",
+		args: []gmArg{{name: "command", kind: gmString, optional: true}},
+		help: "list commands, or show usage of one command",
+		run:  g.help,
+	})
+	for _, cmd := range gmCommands() {
+		g.register(cmd)
+	}
+	return g
+}
+
+func (g *gmRegistry) register(cmd *gmCommand) {
+	if _, ok := g.cmds[cmd.name]; ok {
+		panic(fmt.Sprintf("duplicate gm command:%s", cmd.name))
+	}
+	for i, a := range cmd.args {
+		if a.kind == gmText && i != len(cmd.args)-1 {
+			panic(fmt.Sprintf("gm command %s:text arg %s must be the last", cmd.name, a.name))
+		}
+		if i > 0 && cmd.args[i-1].optional && !a.optional {
+			panic(fmt.Sprintf("gm command %s:required arg %s after optional", cmd.name, a.name))
+		}
+	}
+	g.cmds[cmd.name] = cmd
+}
+
+// 解析并执行line, onReply在主协程中调用且只调用一次
+func (g *gmRegistry) exec(m *Manager, p *Agent, r *Room, line string, onReply func(string)) {
+	c := &gmCall{m: m, p: p, r: r, post: g.post, onReply: onReply}
+
+	words, e := splitArgs(line)
+	if e != nil {
+		c.reply(e.Error())
+		return
+	}
+	if len(words) == 0 {
+		c.reply("empty command, /help for the list")
+		return
+	}
+	cmd := g.cmds[words[0]]
+	if cmd == nil {
+		c.reply(fmt.Sprintf("unknown command %s, /help for the list", words[0]))
+		return
+	}
+	if role := roleOf(r, p.GetUsername()); role < cmd.role {
+		c.reply(fmt.Sprintf("permission denied, /%s needs %s", cmd.name, roleName(cmd.role)))
+		return
+	}
+	c.cmd = cmd
+	if c.args, e = parseArgs(cmd, words[1:]); e != nil {
+		c.reply(fmt.Sprintf("%s, usage:%s", e.Error(), usage(cmd)))
+		return
+	}
+	cmd.run(c)
+}
+
+func (g *gmRegistry) help(c *gmCall) {
+	if name := c.str("command"); name != "" {
+		cmd := g.cmds[strings.TrimPrefix(name, "/")]
+		if cmd == nil {
+			c.reply(fmt.Sprintf("unknown command %s", name))
+			return
+		}
+		c.reply(fmt.Sprintf("%s  %s, needs %s", usage(cmd), cmd.help, roleName(cmd.role)))
+		return
+	}
+
+	// 只列出有权限执行的命令
+	role := roleOf(c.r, c.p.GetUsername())
+	lines := make([]string, 0, len(g.cmds))
+	for _, cmd := range g.cmds {
+		if role >= cmd.role {
+			lines = append(lines, fmt.Sprintf("%s  %s", usage(cmd), cmd.help))
+		}
+	}
+	sort.Strings(lines)
+	c.reply(strings.Join(lines, "\n"))
+}
+
+func (c *gmCall) reply(result string) {
+	if c.replied {
+		return
+	}
+	c.replied = true
+	c.onReply(result)
+}
+
+// 在其他协程中完成的命令通过async回到主协程后再回复
+func (c *gmCall) async(f func()) {
+	c.post("gm."+c.cmd.name, f)
+}
+
+func (c *gmCall) str(name string) string {
+	s, _ := c.args[name].(string)
+	return s
+}
+
+func (c *gmCall) int(name string) int64 {
+	n, _ := c.args[name].(int64)
+	return n
+}
+
+func (c *gmCall) duration(name string) time.Duration {
+	d, _ := c.args[name].(time.Duration)
+	return d
+}
+
+func usage(cmd *gmCommand) string {
+	var b strings.Builder
+	b.WriteString("/" + cmd.name)
+	for _, a := range cmd.args {
+		if a.optional {
+			fmt.Fprintf(&b, " [%s]", a.name)
+		} else {
+			fmt.Fprintf(&b, " <%s>", a.name)
+		}
+	}
+	return b.String()
+}
+
+func roleName(role pb.ROOM_ROLE) string {
+	return strings.ToLower(strings.TrimPrefix(role.String(), "ROLE_"))
+}
+
+func parseArgs(cmd *gmCommand, words []string) (map[string]interface{}, error) {
+	args := make(map[string]interface{}, len(cmd.args))
+	for i, a := range cmd.args {
+		if i >= len(words) {
+			if a.optional {
+				break
+			}
+			return nil, fmt.Errorf("missing %s", a.name)
+		}
+
+		w := words[i]
+		switch a.kind {
+		case gmString:
+			args[a.name] = w
+		case gmText:
+			args[a.name] = strings.Join(words[i:], " ")
+			return args, nil
+		case gmInt:
+			n, e := strconv.ParseInt(w, 10, 64)
+			if e != nil {
+				return nil, fmt.Errorf("%s should be an integer", a.name)
+			}
+			if (a.min != 0 || a.max != 0) && (n < a.min || n > a.max) {
+				return nil, fmt.Errorf("%s should be in [%d, %d]", a.name, a.min, a.max)
+			}
+			args[a.name] = n
+		case gmDuration:
+			d, e := parseDuration(w)
+			if e != nil || d <= 0 {
+				return nil, fmt.Errorf("%s should be a duration like 90s, 10m or 1h", a.name)
+			}
+			args[a.name] = d
+		}
+	}
+	if len(words) > len(cmd.args) {
+		return nil, errors.New("too many args")
+	}
+	return args, nil
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	if n, e := strconv.ParseInt(s, 10, 64); e == nil {
+		return time.Duration(n) * time.Second, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// 按空白分隔, 单引号或双引号内的空白不分隔, 引号内可用\转义
+func splitArgs(line string) ([]string, error) {
+	var (
+		words []string
+		cur   strings.Builder
+		quote rune
+		in    bool // cur中有内容, 包括空的引号
+		esc   bool
+	)
+	for _, c := range line {
+		switch {
+		case esc:
+			cur.WriteRune(c)
+			esc = false
+		case quote != 0 && c == '\\':
+			esc = true
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			cur.WriteRune(c)
+		case c == '"' || c == '\'':
+			quote, in = c, true
+		case unicode.IsSpace(c):
+			if in {
+				words = append(words, cur.String())
+				cur.Reset()
+				in = false
+			}
+		default:
+			cur.WriteRune(c)
+			in = true
+		}
+	}
+	if quote != 0 || esc {
+		return nil, errors.New("unterminated quote")
+	}
+	if in {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
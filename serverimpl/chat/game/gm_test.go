@@ -0,0 +1,222 @@
+package game
+
+import (
+	"cloudcadetest/common/uuid"
+	"cloudcadetest/common/word/filter"
+	"cloudcadetest/common/word/frequency"
+	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/conf"
+	"cloudcadetest/serverimpl/chat/history"
+	"container/list"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type gmTest struct {
+	t      *testing.T
+	m      *Manager
+	r      *Room
+	g      *gmRegistry
+	posted chan func()
+}
+
+// 房间1中有房主alice、管理员bob和普通成员carol
+func newGMTest(t *testing.T) *gmTest {
+	saved, savedMgr := conf.Server, RoomMgr
+	t.Cleanup(func() { conf.Server, RoomMgr = saved, savedMgr })
+	conf.Server = &conf.ServerCfg{HistoryRetain: 100}
+	if UUID == nil {
+		UUID = &uuid.UUID{}
+	}
+
+	m := &Manager{
+		rooms:         map[int64]*Room{},
+		validRooms:    list.New(),
+		players:       map[int64]*Agent{},
+		playersByName: map[string]*Agent{},
+		history:       history.NewMemStore(100),
+		wordFrequency: frequency.New(),
+		names:         map[string]struct{}{},
+		reserved:      newReservations(),
+		inbox:         newInbox(0, 0),
+		penalties:     map[string]*penalty{},
+		mutes:         map[string]time.Time{},
+	}
+	RoomMgr = m
+	r := NewRoom(1, m.history)
+	m.rooms[r.id] = r
+	for i, name := range []string{"alice", "bob", "carol"} {
+		p := &Agent{conn: testConn{}, fd: int64(i + 1), username: name, roomID: r.id, LoginTime: time.Now()}
+		m.names[name] = struct{}{}
+		m.players[p.fd] = p
+		m.playersByName[name] = p
+		r.members[p.fd] = time.Now()
+	}
+	r.roles["alice"] = pb.ROOM_ROLE_ROLE_OWNER
+	r.roles["bob"] = pb.ROOM_ROLE_ROLE_MODERATOR
+
+	gt := &gmTest{t: t, m: m, r: r, posted: make(chan func(), 1)}
+	gt.g = newGMRegistry(func(name string, f func()) { gt.posted <- f })
+	return gt
+}
+
+// 以name执行line, 等待异步命令回到主协程后的结果
+func (gt *gmTest) exec(name, line string) string {
+	var (
+		result  string
+		replies int
+	)
+	gt.g.exec(gt.m, gt.m.playersByName[name], gt.r, line, func(s string) {
+		result = s
+		replies++
+	})
+	if replies == 0 {
+		select {
+		case f := <-gt.posted:
+			f()
+		case <-time.After(3 * time.Second):
+			gt.t.Fatalf("%s: no reply", line)
+		}
+	}
+	if replies != 1 {
+		gt.t.Fatalf("%s: replied %d times", line, replies)
+	}
+	return result
+}
+
+func (gt *gmTest) expect(name, line, want string) {
+	gt.t.Helper()
+	if got := gt.exec(name, line); !strings.Contains(got, want) {
+		gt.t.Errorf("%s %s: got %q, want %q", name, line, got, want)
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	cases := []struct {
+		line string
+		want []string
+	}{
+		{"stats  alice ", []string{"stats", "alice"}},
+		{`notice "hello world"`, []string{"notice", "hello world"}},
+		{`kick bob 'say "hi"' ""`, []string{"kick", "bob", `say "hi"`, ""}},
+		{`a "b\"c"d`, []string{"a", `b"cd`}},
+		{"", nil},
+	}
+	for _, c := range cases {
+		got, e := splitArgs(c.line)
+		if e != nil || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%q: got %q, %v", c.line, got, e)
+		}
+	}
+	if _, e := splitArgs(`notice "open`); e == nil {
+		t.Error("unterminated quote should fail")
+	}
+}
+
+func TestGMExec(t *testing.T) {
+	gt := newGMTest(t)
+	gt.expect("carol", "", "empty command")
+	gt.expect("carol", "nope", "unknown command nope")
+	gt.expect("carol", "kick bob", "permission denied, /kick needs moderator")
+	gt.expect("carol", "stats", "missing username, usage:/stats <username>")
+	gt.expect("carol", "stats a b", "too many args")
+	gt.expect("carol", `stats "unterminated`, "unterminated quote")
+}
+
+func TestGMHelp(t *testing.T) {
+	gt := newGMTest(t)
+	member := gt.exec("carol", "help")
+	if !strings.Contains(member, "/popular <seconds>") || strings.Contains(member, "/mute") {
+		t.Errorf("member help:%s", member)
+	}
+	if owner := gt.exec("alice", "help"); strings.Contains(owner, "/notice") || !strings.Contains(owner, "/kick <username> [reason]") {
+		t.Errorf("owner help:%s", owner)
+	}
+	gt.expect("carol", "help /mute", "/mute <username> <duration>  mute a member in this room, needs moderator")
+	gt.expect("carol", "help nope", "unknown command nope")
+}
+
+func TestGMPopular(t *testing.T) {
+	gt := newGMTest(t)
+	gt.expect("carol", "popular 0", "seconds should be in [1, 60]")
+	gt.expect("carol", "popular x", "seconds should be an integer")
+
+	// 固定时钟下查询总是从最新的分桶开始
+	now := time.Unix(1700000000, 0)
+	gt.m.wordFrequency = frequency.NewWithClock(func() time.Time { return now })
+	gt.expect("carol", "popular 5", "no words in the last 5 seconds")
+	gt.m.recordWordFrequency("go go gopher go")
+	gt.expect("carol", "popular 5", "most popular word in the last 5 seconds: go")
+}
+
+func TestGMStats(t *testing.T) {
+	gt := newGMTest(t)
+	gt.m.playersByName["bob"].LoginTime = time.Now().Add(-(26*time.Hour + 3*time.Minute + 4*time.Second))
+	gt.expect("carol", "stats bob", "bob online for 1d02h03m04s")
+	gt.expect("carol", "stats dave", "dave is not online")
+
+	if s := formatDuration(90 * time.Second); s != "00h01m30s" {
+		t.Errorf("format 90s:%s", s)
+	}
+}
+
+func TestGMMute(t *testing.T) {
+	gt := newGMTest(t)
+	gt.expect("bob", "mute carol 10x", "duration should be a duration")
+	gt.expect("bob", "mute alice 10m", "permission denied")
+	gt.expect("bob", "mute carol 10m", "carol muted until")
+	if _, ok := gt.r.mutedUntil("carol", time.Now().Add(9*time.Minute)); !ok {
+		t.Fatal("carol should be muted for 10 minutes")
+	}
+	gt.expect("alice", "mute carol 90", "carol muted until")
+	if until := gt.r.mutes["carol"]; until.After(time.Now().Add(91 * time.Second)) {
+		t.Fatal("plain number should be seconds")
+	}
+
+	gt.expect("bob", "unmute carol", "carol unmuted")
+	gt.expect("bob", "unmute carol", "carol is not muted")
+}
+
+func TestGMKick(t *testing.T) {
+	gt := newGMTest(t)
+	gt.expect("bob", `kick carol "too noisy" today`, "carol kicked")
+	if gt.m.playersByName["carol"].GetRoomID() != 0 || len(gt.r.members) != 2 {
+		t.Fatal("carol should leave the room")
+	}
+	if _, ok := gt.r.kickedUntil("carol", time.Now()); !ok {
+		t.Fatal("carol should not rejoin at once")
+	}
+	gt.expect("bob", "kick carol", "carol is not in room 1")
+}
+
+// 只有服务器管理员能以系统名义发言, 内容同样过滤
+func TestGMNotice(t *testing.T) {
+	gt := newGMTest(t)
+	conf.Server.AdminNames = []string{"root"}
+	gt.m.playersByName["root"] = &Agent{conn: testConn{}, fd: 9, username: "root", roomID: gt.r.id}
+
+	dir, _ := ioutil.TempDir("", "notice")
+	defer os.RemoveAll(dir)
+	words := filepath.Join(dir, "list.txt")
+	ioutil.WriteFile(words, []byte("shit\n"), 0644)
+	gt.r.filter = filter.New(testFS(words))
+
+	gt.expect("alice", "notice hi", "permission denied, /notice needs admin")
+	gt.expect("root", `notice "server restarts"  at 10pm`, "notice posted")
+	gt.expect("root", "notice shit happens", "notice posted")
+	msgs, _ := gt.m.history.Before(1, 0, 2)
+	if len(msgs) != 2 || msgs[0].From != systemName || msgs[0].Content != "server restarts at 10pm" || msgs[1].Content != "**** happens" {
+		t.Fatalf("history %v", msgs)
+	}
+
+	gt.m.policies = map[string]filter.Policy{filterRoomChat: filter.Reject}
+	gt.expect("root", "notice shit", "notice content rejected: contains shit")
+	if n := gt.m.history.Len(1); n != 2 {
+		t.Fatalf("rejected notice stored, history %d", n)
+	}
+}
@@ -0,0 +1,141 @@
+package game
+
+import (
+	"cloudcadetest/common/word/filter"
+	"cloudcadetest/common/word/frequency/wordmeta"
+	"cloudcadetest/pb"
+	"fmt"
+	"time"
+)
+
+func gmCommands() []*gmCommand {
+	return []*gmCommand{
+		{
+			name: "popular",
+			args: []gmArg{{name: "seconds", kind: gmInt, min: 1, max: 60}},
+			help: "most frequent word in room chats of the last seconds",
+			run:  gmPopular,
+		},
+		{
+			name: "stats",
+			args: []gmArg{{name: "username", kind: gmString}},
+			help: "how long an online player has been logged in",
+			run:  gmStats,
+		},
+		{
+			name: "mute",
+			args: []gmArg{{name: "username", kind: gmString}, {name: "duration", kind: gmDuration}},
+			role: pb.ROOM_ROLE_ROLE_MODERATOR,
+			help: "mute a member in this room",
+			run:  gmMute,
+		},
+		{
+			name: "unmute",
+			args: []gmArg{{name: "username", kind: gmString}},
+			role: pb.ROOM_ROLE_ROLE_MODERATOR,
+			help: "unmute a member in this room",
+			run:  gmUnmute,
+		},
+		{
+			name: "kick",
+			args: []gmArg{{name: "username", kind: gmString}, {name: "reason", kind: gmText, optional: true}},
+			role: pb.ROOM_ROLE_ROLE_MODERATOR,
+			help: "kick a member out of this room",
+			run:  gmKick,
+		},
+		{
+			name: "notice",
+			args: []gmArg{{name: "content", kind: gmText}},
+			role: pb.ROOM_ROLE_ROLE_ADMIN,
+			help: "post a system notice to this room",
+			run:  gmNotice,
+		},
+	}
+}
+
+// 词频统计在其协程中回调
+func gmPopular(c *gmCall) {
+	secs := c.int("seconds")
+	c.m.wordFrequency.GetFrequencyByTime(int(secs), func(meta *wordmeta.Data, e error) {
+		c.async(func() {
+			switch {
+			case e != nil:
+				c.reply("get word frequency failed:" + e.Error())
+			case meta == nil:
+				c.reply(fmt.Sprintf("no words in the last %d seconds", secs))
+			default:
+				c.reply(fmt.Sprintf("most popular word in the last %d seconds: %s", secs, meta.Word))
+			}
+		})
+	})
+}
+
+func gmStats(c *gmCall) {
+	name := c.str("username")
+	p := c.m.playersByName[name]
+	if p == nil {
+		c.reply(fmt.Sprintf("%s is not online", name))
+		return
+	}
+	c.reply(fmt.Sprintf("%s online for %s", name, formatDuration(time.Since(p.LoginTime))))
+}
+
+func gmMute(c *gmCall) {
+	name := c.str("username")
+	until, e := c.m.RoomMute(c.p, name, c.duration("duration"))
+	if e != nil {
+		c.reply(e.Error())
+		return
+	}
+	c.reply(fmt.Sprintf("%s muted until %s", name, until.Format("2006-01-02 15:04:05")))
+}
+
+func gmUnmute(c *gmCall) {
+	name := c.str("username")
+	if _, e := c.m.RoomMute(c.p, name, 0); e != nil {
+		c.reply(e.Error())
+		return
+	}
+	c.reply(fmt.Sprintf("%s unmuted", name))
+}
+
+func gmKick(c *gmCall) {
+	name := c.str("username")
+	if e := c.m.RoomKick(c.p, name, c.str("reason")); e != nil {
+		c.reply(e.Error())
+		return
+	}
+	c.reply(fmt.Sprintf("%s kicked", name))
+}
+
+// 以系统名义发言, 只允许服务器管理员; 内容按房间聊天的策略过滤
+func gmNotice(c *gmCall) {
+	from := c.p.GetUsername()
+	c.r.filter.CheckWith(c.str("content"), c.m.filterPolicy(filterRoomChat), func(res *filter.Result) {
+		text, dropped, e := c.m.applyFilter(&FlaggedMsg{Where: filterRoomChat, From: from, RoomID: c.r.id}, res)
+		switch {
+		case e != nil:
+			c.reply("notice " + e.Error())
+		case dropped:
+			c.reply("notice held for review")
+		default:
+			c.r.notice(text)
+			c.reply("notice posted")
+		}
+	})
+}
+
+// 如1d02h03m04s, 不足一天时省略天数
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	secs := int64(d / time.Second)
+	days, secs := secs/86400, secs%86400
+	h, secs := secs/3600, secs%3600
+	m, s := secs/60, secs%60
+	if days > 0 {
+		return fmt.Sprintf("%dd%02dh%02dm%02ds", days, h, m, s)
+	}
+	return fmt.Sprintf("%02dh%02dm%02ds", h, m, s)
+}
@@ -62,9 +62,23 @@ func handleCS(args []interface{}) {
 	rsp := &pb.CSRspBody{
 		Seq: req.Seq,
 	}
-	f(p, req, rsp)
+	now := time.Now()
+	if e := checkFlood(p, reqId, req, now); e != nil {
+		if !p.kicked {
+			rsp.ErrCode = pb.ERROR_CODE_RATE_LIMITED
+			rsp.ErrMsg = e.Error()
+			p.SendClient(rspID(reqId), rsp, nil)
+		}
+	} else {
+		f(p, req, rsp)
+	}
+
+	p.updateActiveTS(now)
+}
 
-	p.updateActiveTS(time.Now())
+// 应答ID与请求ID相差RSP_BEGIN
+func rspID(reqID pb.CSMsgID) pb.CSMsgID {
+	return reqID - pb.CSMsgID_REQ_BEGIN + pb.CSMsgID_RSP_BEGIN
 }
 
 func registerHandler() {
@@ -72,8 +86,18 @@ func registerHandler() {
 
 	handlerCS(pb.CSMsgID_REQ_LOGIN, reqLogin)
 	handlerCS(pb.CSMsgID_REQ_HEARTBEAT, reqHeartbeat)
+	handlerCS(pb.CSMsgID_REQ_SET_USERNAME, reqSetUsername)
 	handlerCS(pb.CSMsgID_REQ_ROOM_CHAT, reqRoomChat)
 	handlerCS(pb.CSMsgID_REQ_ROOM_LIST, reqRoomList)
 	handlerCS(pb.CSMsgID_REQ_JOIN_ROOM, reqJoinRoom)
 	handlerCS(pb.CSMsgID_REQ_CHAT, reqChat)
+	handlerCS(pb.CSMsgID_REQ_LEAVE_ROOM, reqLeaveRoom)
+	handlerCS(pb.CSMsgID_REQ_ROOM_MEMBERS, reqRoomMembers)
+	handlerCS(pb.CSMsgID_REQ_ROOM_HISTORY, reqRoomHistory)
+	handlerCS(pb.CSMsgID_REQ_SEARCH_HISTORY, reqSearchHistory)
+	handlerCS(pb.CSMsgID_REQ_INBOX_READ, reqInboxRead)
+	handlerCS(pb.CSMsgID_REQ_SET_ROLE, reqSetRole)
+	handlerCS(pb.CSMsgID_REQ_ROOM_MUTE, reqRoomMute)
+	handlerCS(pb.CSMsgID_REQ_ROOM_KICK, reqRoomKick)
+	handlerCS(pb.CSMsgID_REQ_BAN, reqBan)
 }
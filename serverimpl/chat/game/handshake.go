@@ -0,0 +1,56 @@
+package game
+
+import (
+	"cloudcadetest/common/encrypt/aes"
+	"cloudcadetest/common/encrypt/kex"
+	"cloudcadetest/pb"
+)
+
+// 在读协程中处理密钥交换: 应答以明文发出, 之后收发均使用会话密钥
+func (p *Agent) handshake(req *pb.CSReqBody) bool {
+	if req.Handshake == nil {
+		p.LogError("nil Handshake")
+		return false
+	}
+	if p.working {
+		p.LogWarn("duplicate handshake")
+		return false
+	}
+
+	rsp := &pb.CSRspBody{
+		Seq:       req.Seq,
+		Handshake: &pb.CSRspHandshake{},
+	}
+
+	if !CSProcessor.NeedEncrypt() {
+		// 未开启加密, 告知客户端继续使用明文
+		rsp.ErrCode = pb.ERROR_CODE_FAILED
+		rsp.ErrMsg = "encryption disabled"
+		p.working = true
+		return CSProcessor.WriteMsg(p, pb.CSMsgID_RSP_HANDSHAKE, rsp) == nil
+	}
+
+	hello, keys, e := kex.ServerHandshake(Identity, req.Handshake.PublicKey, req.Handshake.Nonce)
+	if e != nil {
+		p.LogWarn("handshake failed:%s", e.Error())
+		return false
+	}
+
+	sess, e := aes.NewSession(keys.ServerToClient, keys.ClientToServer)
+	if e != nil {
+		p.LogError("new session failed:%s", e.Error())
+		return false
+	}
+
+	rsp.Handshake.PublicKey = hello.PublicKey
+	rsp.Handshake.Nonce = hello.Nonce
+	rsp.Handshake.Signature = hello.Signature
+	if e = CSProcessor.WriteMsg(p, pb.CSMsgID_RSP_HANDSHAKE, rsp); e != nil {
+		p.LogWarn("send handshake failed:%s", e.Error())
+		return false
+	}
+
+	p.session = sess
+	p.working = true
+	return true
+}
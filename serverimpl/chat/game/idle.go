@@ -0,0 +1,34 @@
+package game
+
+import (
+	"cloudcadetest/serverimpl/chat/conf"
+	"time"
+)
+
+const idleSweepInterval = time.Second
+
+// 定时检查所有连接, 超时未收到任何消息(包括心跳)的踢下线
+func startIdleSweeper() {
+	if conf.Server.PlayerInteractiveTime <= 0 {
+		return
+	}
+	SM.NewTicker("game.idle", idleSweepInterval, sweepIdle)
+}
+
+func sweepIdle() {
+	now := time.Now()
+	RangeAgentPlayers(func(ip IPlayer) {
+		if p, ok := ip.(*Agent); ok && !p.IsDestroyed() {
+			p.update(now)
+		}
+	})
+}
+
+// 下发给客户端的心跳间隔, 超时前至少有几次心跳
+func heartbeatInterval() int32 {
+	interval := conf.Server.PlayerInteractiveTime / 4
+	if interval < 1 {
+		interval = 1
+	}
+	return int32(interval)
+}
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"cloudcadetest/framework/log"
+	"cloudcadetest/pb"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+const (
+	defaultInboxSize       = 100
+	defaultInboxRecipients = 10000
+)
+
+var errNoRecipient = errors.New("recipient not found")
+
+// 离线玩家的私聊收件箱, 只在主协程中访问
+// 消息在客户端确认已读后才删除, 未确认的在下次登录时重新下发
+type inbox struct {
+	size       int                         // 每个收件人的上限
+	recipients int                         // 收件人数的上限
+	msgs       map[string][]*pb.PrivateMsg // 收件人 -> 未读私聊, 按ID升序
+}
+
+func newInbox(size, recipients int) *inbox {
+	if size <= 0 {
+		size = defaultInboxSize
+	}
+	if recipients <= 0 {
+		recipients = defaultInboxRecipients
+	}
+	return &inbox{
+		size:       size,
+		recipients: recipients,
+		msgs:       map[string][]*pb.PrivateMsg{},
+	}
+}
+
+func (ib *inbox) add(msg *pb.PrivateMsg) error {
+	msgs, ok := ib.msgs[msg.To]
+	if !ok && len(ib.msgs) >= ib.recipients {
+		return errors.New("inbox is full")
+	}
+	if len(msgs) >= ib.size {
+		return fmt.Errorf("inbox of %s is full", msg.To)
+	}
+	ib.msgs[msg.To] = append(msgs, msg)
+	return nil
+}
+
+func (ib *inbox) unread(name string) []*pb.PrivateMsg {
+	return ib.msgs[name]
+}
+
+// 删除ID不大于upToID的消息, 返回剩余条数
+func (ib *inbox) read(name string, upToID int64) int {
+	msgs := ib.msgs[name]
+	i := sort.Search(len(msgs), func(i int) bool { return msgs[i].ID > upToID })
+	if i == len(msgs) {
+		delete(ib.msgs, name)
+		return 0
+	}
+	ib.msgs[name] = append([]*pb.PrivateMsg(nil), msgs[i:]...)
+	return len(msgs) - i
+}
+
+// 名字换了主人时丢弃其未读私聊
+func (ib *inbox) drop(name string) {
+	if n := len(ib.msgs[name]); n > 0 {
+		log.Release("drop %d unread msgs of %s", n, name)
+	}
+	delete(ib.msgs, name)
+}
+
+// 收件人改名, 未读私聊随之转移
+func (ib *inbox) rename(old, name string) {
+	msgs, ok := ib.msgs[old]
+	if !ok {
+		return
+	}
+	delete(ib.msgs, old)
+	msgs = append(ib.msgs[name], msgs...)
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].ID < msgs[j].ID })
+	ib.msgs[name] = msgs
+}
+
+// 按发送者统计未读条数
+func countUnread(msgs []*pb.PrivateMsg) []*pb.InboxUnread {
+	counts := map[string]int32{}
+	for _, msg := range msgs {
+		counts[msg.From]++
+	}
+	unread := make([]*pb.InboxUnread, 0, len(counts))
+	for from, n := range counts {
+		unread = append(unread, &pb.InboxUnread{From: from, Count: n})
+	}
+	sort.Slice(unread, func(i, j int) bool { return unread[i].From < unread[j].From })
+	return unread
+}
+
+func (ib *inbox) save(path string) error {
+	if path == "" {
+		return nil
+	}
+	data, e := json.Marshal(ib.msgs)
+	if e != nil {
+		return e
+	}
+	return writeFile(path, data)
+}
+
+func (ib *inbox) load(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	data, e := ioutil.ReadFile(path)
+	if e != nil {
+		if os.IsNotExist(e) {
+			return nil
+		}
+		return e
+	}
+	return json.Unmarshal(data, &ib.msgs)
+}
@@ -0,0 +1,112 @@
+package game
+
+import (
+	"cloudcadetest/common/word/filter"
+	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/conf"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInbox(t *testing.T) {
+	ib := newInbox(3, 0)
+	for i, from := range []string{"bob", "carol", "bob"} {
+		if e := ib.add(&pb.PrivateMsg{ID: int64(i + 1), From: from, To: "alice"}); e != nil {
+			t.Fatal(e)
+		}
+	}
+	if e := ib.add(&pb.PrivateMsg{ID: 4, From: "bob", To: "alice"}); e == nil {
+		t.Fatal("full inbox should reject")
+	}
+
+	unread := countUnread(ib.unread("alice"))
+	if len(unread) != 2 || unread[0].From != "bob" || unread[0].Count != 2 || unread[1].Count != 1 {
+		t.Fatalf("unread %v", unread)
+	}
+
+	// 保存后重新加载, 未读消息不丢失
+	dir, _ := ioutil.TempDir("", "inbox")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "inbox.json")
+	if e := ib.save(path); e != nil {
+		t.Fatal(e)
+	}
+	ib = newInbox(3, 0)
+	if e := ib.load(path); e != nil {
+		t.Fatal(e)
+	}
+
+	if n := ib.read("alice", 2); n != 1 {
+		t.Fatalf("remain %d", n)
+	}
+	if msgs := ib.unread("alice"); len(msgs) != 1 || msgs[0].ID != 3 {
+		t.Fatalf("unread %v", msgs)
+	}
+	if n := ib.read("alice", 3); n != 0 || len(ib.msgs) != 0 {
+		t.Fatalf("remain %d", n)
+	}
+}
+
+func TestInboxRecipients(t *testing.T) {
+	ib := newInbox(10, 2)
+	for i, to := range []string{"alice", "bob", "alice"} {
+		if e := ib.add(&pb.PrivateMsg{ID: int64(i + 1), From: "carol", To: to}); e != nil {
+			t.Fatal(e)
+		}
+	}
+	if e := ib.add(&pb.PrivateMsg{ID: 4, From: "carol", To: "dave"}); e == nil {
+		t.Fatal("too many recipients should reject")
+	}
+}
+
+// 离线私聊只存给名字有主的收件人, 名字换了主人时丢弃
+func TestChatRecipients(t *testing.T) {
+	gt := newGMTest(t)
+	m := gt.m
+	m.filter = filter.New(testFS(os.DevNull))
+	conf.Server.NameReserveDays = 30
+
+	chat := func(to string) (bool, error) {
+		var (
+			stored bool
+			err    error
+		)
+		if e := m.Chat(m.playersByName["alice"], &pb.CSReqChat{Username: to, Content: "hi"}, func(msg *pb.PrivateMsg, s bool, e error) {
+			stored, err = s, e
+		}); e != nil {
+			return false, e
+		}
+		return stored, err
+	}
+	if _, e := chat("dave"); !errors.Is(e, errNoRecipient) {
+		t.Fatalf("unknown name:%v", e)
+	}
+	if _, e := chat("d a"); !errors.Is(e, errInvalidName) {
+		t.Fatalf("invalid name:%v", e)
+	}
+	if len(m.inbox.msgs) != 0 {
+		t.Fatalf("inbox %v", m.inbox.msgs)
+	}
+
+	m.reserved.reserve("dave", time.Now())
+	if stored, e := chat("dave"); e != nil || !stored {
+		t.Fatalf("reserved name stored %t:%v", stored, e)
+	}
+
+	// 保留过期后其他人以该名字登录, 收不到之前的私聊
+	m.reserved.names["dave"].LastSeen = time.Now().Add(-31 * 24 * time.Hour)
+	if _, e := chat("dave"); !errors.Is(e, errNoRecipient) {
+		t.Fatalf("expired reservation:%v", e)
+	}
+	p := &Agent{conn: testConn{}, fd: 9, LoginTime: time.Now()}
+	if _, e := m.Join(p, "dave", ""); e != nil {
+		t.Fatal(e)
+	}
+	if n := len(m.inbox.unread("dave")); n != 0 {
+		t.Fatalf("new owner got %d msgs", n)
+	}
+}
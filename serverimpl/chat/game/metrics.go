@@ -0,0 +1,28 @@
+package game
+
+import (
+	"cloudcadetest/framework/metrics"
+	"time"
+)
+
+const statsInterval = time.Second
+
+var (
+	roomsGauge   = metrics.NewGauge("cc_rooms", "Rooms held by the room manager.")
+	playersGauge = metrics.NewGauge("cc_players", "Players joined to a room.")
+	parkedGauge  = metrics.NewGauge("cc_sessions_parked", "Disconnected sessions waiting to be resumed.")
+
+	floodCounter  = metrics.NewCounterVec("cc_flood_rejected_total", "Chat messages rejected by flood control.", "action")
+	filterCounter = metrics.NewCounterVec("cc_filter_matched_total", "Content containing sensitive words, by context and policy.", "where", "policy")
+)
+
+// 房间与玩家只在主协程中读写, 定时采样后供指标接口读取
+func (m *Manager) startStats() {
+	SM.NewTicker("game.stats", statsInterval, m.sampleStats)
+}
+
+func (m *Manager) sampleStats() {
+	roomsGauge.Set(float64(len(m.rooms)))
+	playersGauge.Set(float64(len(m.players)))
+	parkedGauge.Set(float64(len(m.sessions)))
+}
@@ -4,9 +4,12 @@ import (
 	"cloudcadetest/common/task"
 	"cloudcadetest/common/word/filter"
 	"cloudcadetest/common/word/frequency"
-	"cloudcadetest/common/word/frequency/wordmeta"
 	"cloudcadetest/framework/log"
 	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/auth"
+	"cloudcadetest/serverimpl/chat/conf"
+	"cloudcadetest/serverimpl/chat/history"
+	"cloudcadetest/serverimpl/chat/search"
 	"container/list"
 	"errors"
 	"fmt"
@@ -14,6 +17,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 type Manager struct {
@@ -26,25 +30,91 @@ type Manager struct {
 	playersByName map[string]*Agent
 	filter        *filter.Filter
 	names         map[string]struct{}
+	reserved      *reservations
+	auth          *auth.Authenticator
+	accounts      *auth.FileBackend // 开启密码认证时的账号文件
+	authTasks     *task.Pool
+	sessions      map[string]*session // 恢复令牌 -> 断线等待恢复的会话
 	wordFrequency *frequency.Frequency
+	gm            *gmRegistry
+	policies      map[string]filter.Policy // 各场景命中敏感词时的处理方式
+	flagged       []*FlaggedMsg            // 最近被记录待审核的内容
+	history       history.Store
+	inbox         *inbox
+	closing       bool // 正在停服, 不再接受新玩家
+
+	// 平滑重启时旧进程保存状态后才加载, 加载前的登录排队等待
+	loaded        bool
+	pendingLogins []func()
+
+	penalties map[string]*penalty  // 刷屏违规记录, 键为penaltyKey
+	mutes     map[string]time.Time // 管理员的全服禁言, 用户名 -> 截止时间
 }
 
 func NewRoomMgr() *Manager {
 	m := &Manager{
-		taskPool:       task.NewTaskPool(SM, 0, 0),
+		taskPool:       task.NewTaskPool(SM, 0, 0).SetName("room"),
 		roomIDBase:     0,
 		rooms:          map[int64]*Room{},
 		names:          map[string]struct{}{},
+		reserved:       newReservations(),
+		authTasks:      task.NewTaskPool(SM, 0, 0).SetName("auth"),
 		players:        map[int64]*Agent{},
 		playersByName:  map[string]*Agent{},
+		sessions:       map[string]*session{},
+		penalties:      map[string]*penalty{},
+		mutes:          map[string]time.Time{},
 		validRooms:     list.New(),
 		filterSkeleton: NewFS(),
 		wordFrequency:  frequency.New(),
 	}
 	m.filter = filter.New(m)
+	m.policies = loadFilterPolicies()
+	m.gm = newGMRegistry(func(name string, f func()) {
+		SM.RunInSkeleton(name, f)
+	})
+	m.auth, m.accounts = openAuth()
+	m.inbox = newInbox(conf.Server.InboxSize, conf.Server.InboxRecipients)
+	m.sampleStats()
+	m.startStats()
+	m.startSessionSweeper()
 	return m
 }
 
+// 加载持久状态后处理排队的登录, 在主协程中调用
+func (m *Manager) load() {
+	m.history = openHistory()
+	if e := m.loadState(conf.Server.RoomStateFile); e != nil {
+		log.Error("load room state failed:%s", e.Error())
+	}
+	if e := bans.load(conf.Server.BanFile); e != nil {
+		log.Error("load bans failed:%s", e.Error())
+	}
+	if e := m.reserved.load(conf.Server.NameFile); e != nil {
+		log.Error("load reserved names failed:%s", e.Error())
+	}
+	if e := m.inbox.load(conf.Server.InboxFile); e != nil {
+		log.Error("load inbox failed:%s", e.Error())
+	}
+	m.loaded = true
+	log.Release("game state loaded, %d queued logins", len(m.pendingLogins))
+
+	pending := m.pendingLogins
+	m.pendingLogins = nil
+	for _, f := range pending {
+		f()
+	}
+}
+
+// 状态加载前的登录排队, 加载后依次处理; 排队已满时返回错误
+func (m *Manager) queueLogin(f func()) error {
+	if len(m.pendingLogins) >= maxPendingLogins {
+		return errors.New("server is starting, try again later")
+	}
+	m.pendingLogins = append(m.pendingLogins, f)
+	return nil
+}
+
 func (m *Manager) newTid() int64 {
 	if m.roomIDBase == math.MaxInt64 {
 		m.roomIDBase = 0
@@ -53,6 +123,15 @@ func (m *Manager) newTid() int64 {
 	return m.roomIDBase
 }
 
+const (
+	maxRooms       = 100
+	maxRoomNameLen = 32
+	maxSearchLen   = 256
+	maxPrivateLen  = 512
+
+	maxPendingLogins = 10000
+)
+
 type RoomState int
 
 const (
@@ -66,9 +145,16 @@ func (m *Manager) push(r *Room) {
 	r.node = n
 }
 
-func (m *Manager) AddRoom() *Room {
+func (m *Manager) AddRoom(name string) *Room {
 	id := m.newTid()
-	r := NewRoom(id)
+	// 新房间不应有记录, 状态文件丢失时清掉同ID的残留
+	if m.history.LastSeq(id) > 0 {
+		if e := m.history.Drop(id); e != nil {
+			log.Error("drop stale history of room %d failed:%s", id, e.Error())
+		}
+	}
+	r := NewRoom(id, m.history)
+	r.name = name
 	m.push(r)
 	m.rooms[id] = r
 	return r
@@ -79,8 +165,14 @@ func (m *Manager) DeleteRoom(id int64) {
 	if !ok {
 		return
 	}
-	m.validRooms.Remove(r.node)
+	if r.node != nil {
+		m.validRooms.Remove(r.node)
+		r.node = nil
+	}
 	delete(m.rooms, id)
+	if e := m.history.Drop(id); e != nil {
+		log.Error("drop history of room %d failed:%s", id, e.Error())
+	}
 }
 
 func (m *Manager) AddRoomTask(roomID int64, f, cb func()) int {
@@ -92,60 +184,189 @@ func (m *Manager) AddRoomTask(roomID int64, f, cb func()) int {
 	return 0
 }
 
-func (m *Manager) Join(p *Agent, username string) (int64, error) {
-	if _, ok := m.names[username]; ok {
-		return -1, errors.New(fmt.Sprintf("duplicate name:%s, %v", username, m.names))
+// 登录并自动加入一个未满的房间, 名字已保留时需带上密钥
+func (m *Manager) Join(p *Agent, username, nameKey string) (int64, error) {
+	if m.closing {
+		return -1, errors.New("server is shutting down")
+	}
+	if _, ok := m.players[p.GetFD()]; ok {
+		return -1, errors.New("already logged in")
+	}
+	if e := m.nameAvailable(p, username, nameKey); e != nil {
+		return -1, e
+	}
+
+	r, e := m.pickRoom()
+	if e != nil {
+		return -1, e
 	}
 
+	// 不是名字的主人时, 之前发给该名字的私聊不能给他
+	if !m.nameOwned(username) {
+		m.inbox.drop(username)
+	}
 	m.names[username] = struct{}{}
+	m.players[p.GetFD()] = p
+	m.playersByName[username] = p
+	m.reserved.touch(username, time.Now())
+	p.SetUsername(username)
+	if sessionGrace() > 0 {
+		p.token = newResumeToken()
+	}
+
+	if e = m.enterRoom(p, r); e != nil {
+		return -1, e
+	}
+	return r.id, nil
+}
+
+// 有未满的房间时选第一个, 否则新建
+func (m *Manager) pickRoom() (*Room, error) {
+	if id := m.validRooms.Front(); id != nil {
+		return m.rooms[id.Value.(int64)], nil
+	}
+	return m.createRoom("")
+}
+
+func (m *Manager) createRoom(name string) (*Room, error) {
+	if utf8.RuneCountInString(name) > maxRoomNameLen {
+		return nil, fmt.Errorf("room name longer than %d", maxRoomNameLen)
+	}
+	if len(m.rooms) >= maxRooms {
+		return nil, errors.New("no room valid")
+	}
+	return m.AddRoom(name), nil
+}
+
+// 新建、按ID或自动选择房间并加入, 已在其他房间时先离开
+func (m *Manager) JoinRoom(p *Agent, roomID int64, createNew bool, name string) (*Room, error) {
+	if m.closing {
+		return nil, errors.New("server is shutting down")
+	}
+	if m.players[p.GetFD()] != p {
+		return nil, errors.New("not logged in")
+	}
 
-	var r *Room
-	id := m.validRooms.Front()
-	if id == nil {
-		if len(m.rooms) < 100 {
-			r = m.AddRoom()
-		} else {
-			return -1, errors.New("no room valid")
+	var (
+		r *Room
+		e error
+	)
+	switch {
+	case createNew:
+		if r, e = m.createRoom(name); e != nil {
+			return nil, e
 		}
-	} else {
-		r = m.rooms[id.Value.(int64)]
+		r.roles[p.GetUsername()] = pb.ROOM_ROLE_ROLE_OWNER
+	case roomID == 0:
+		if r, e = m.pickRoom(); e != nil {
+			return nil, e
+		}
+	default:
+		if r = m.rooms[roomID]; r == nil {
+			return nil, fmt.Errorf("room %d not found", roomID)
+		}
+	}
+
+	if r.id == p.GetRoomID() {
+		return nil, fmt.Errorf("already in room %d", r.id)
+	}
+	if r.IsFull() {
+		return nil, fmt.Errorf("room %d is full", r.id)
+	}
+	if until, ok := r.kickedUntil(p.GetUsername(), time.Now()); ok {
+		return nil, fmt.Errorf("kicked from room %d, retry after %s", r.id, until.Format(time.RFC3339))
+	}
+
+	m.leaveRoom(p, false)
+	if e = m.enterRoom(p, r); e != nil {
+		return nil, e
+	}
+	return r, nil
+}
+
+// 当前房间的成员列表
+func (m *Manager) RoomMembers(p *Agent) (*Room, []*pb.RoomMember, error) {
+	if m.players[p.GetFD()] != p {
+		return nil, nil, errors.New("not logged in")
 	}
+	r := m.rooms[p.GetRoomID()]
+	if r == nil {
+		return nil, nil, errors.New("not in any room")
+	}
+	return r, r.memberList(), nil
+}
+
+// 离开当前房间, 保持在线
+func (m *Manager) LeaveRoom(p *Agent) (int64, error) {
+	if m.players[p.GetFD()] != p {
+		return 0, errors.New("not logged in")
+	}
+	roomID := p.GetRoomID()
+	if m.rooms[roomID] == nil {
+		return 0, errors.New("not in any room")
+	}
+	m.leaveRoom(p, false)
+	return roomID, nil
+}
 
+func (m *Manager) enterRoom(p *Agent, r *Room) error {
 	state := r.Join(p.GetFD())
-	if state == full {
+	if state == invalid {
+		return fmt.Errorf("room %d is full", r.id)
+	}
+	if state == full && r.node != nil {
 		m.validRooms.Remove(r.node)
 		r.node = nil
 	}
 	p.SetRoomID(r.id)
-	m.players[p.GetFD()] = p
-	m.playersByName[username] = p
+	m.notifyEntered(p, r)
+
+	// history messages
+	m.notifyHistoryMsgs(p.GetFD(), 0)
+	return nil
+}
 
+func (m *Manager) notifyEntered(p *Agent, r *Room) {
 	r.broadcast(-1, pb.CSMsgID_NTF_ROOM_MEMBER_ONLINE, &pb.CSNtfBody{RoomMemberOnline: &pb.CSNtfRoomMemberOnline{
 		RoomID:   r.id,
-		Username: username,
+		Username: p.GetUsername(),
 	}})
-
-	// history messages
-	m.notifyHistoryMsgs(p.GetFD())
-
-	return r.id, nil
+	r.notifyJoined(p)
 }
 
-func (m *Manager) Leave(playerFD, roomID int64) error {
-	r, ok := m.rooms[roomID]
-	if !ok {
-		return fmt.Errorf("room %d not found", roomID)
+// 从当前房间移除并通知剩余成员, 房间重新变为可加入
+func (m *Manager) leaveRoom(p *Agent, offline bool) {
+	r := m.rooms[p.GetRoomID()]
+	p.SetRoomID(0)
+	if r == nil {
+		return
+	}
+	if _, ok := r.members[p.GetFD()]; !ok {
+		return
 	}
-	r.Leave(playerFD)
+
+	r.Leave(p.GetFD())
 	if r.node == nil {
 		m.push(r)
 	}
+
+	r.notifyLeft(p.GetUsername(), offline)
+}
+
+// 玩家下线, 可恢复的会话先保留
+func (m *Manager) Leave(playerFD int64) error {
 	p := m.players[playerFD]
 	if p == nil {
 		return errors.New("player not found")
 	}
+	if m.park(p) {
+		return nil
+	}
+	m.leaveRoom(p, true)
 	delete(m.players, playerFD)
 	delete(m.playersByName, p.GetUsername())
+	delete(m.names, p.GetUsername())
+	m.reserved.touch(p.GetUsername(), time.Now())
 
 	return nil
 }
@@ -154,91 +375,59 @@ func (m *Manager) recordWordFrequency(word string) {
 	m.wordFrequency.Add(word)
 }
 
-func (m *Manager) RoomChat(playerFD, roomID int64, content string) {
+func (m *Manager) RoomChat(playerFD, roomID int64, content string) error {
 	p := m.players[playerFD]
 	if p == nil {
-		return
+		return errors.New("player not found")
 	}
 	r := m.rooms[roomID]
 	if r == nil {
-		return
+		return errors.New("room entity not found")
+	}
+	now := time.Now()
+	if until, ok := m.mutedUntil(p, now); ok {
+		return fmt.Errorf("muted until %s", until.Format(time.RFC3339))
+	}
+	if until, ok := r.mutedUntil(p.username, now); ok {
+		return fmt.Errorf("muted in room %d until %s", r.id, until.Format(time.RFC3339))
 	}
 
-	// GM
-	if strings.Index(content, "/") == 0 {
-		m.execGM(content[1:], func(result string) {
-			r.notifyRoomChat(-1, result)
-		})
-	} else {
-		r.filter.Check(content, func(newStr string) {
-			r.AddMsg(p.username, newStr)
-			r.notifyRoomChat(playerFD, newStr)
+	// GM命令的结果只发给执行者, 不计入历史
+	if strings.HasPrefix(content, "/") {
+		m.gm.exec(m, p, r, content[1:], func(result string) {
+			sendRoomChat(p, systemName, result)
 		})
+		return nil
 	}
-}
 
-func (m *Manager) execGM(cmd string, onFinish func(result string)) {
-	ss := strings.Split(cmd, " ")
-	if len(ss) != 2 {
-		onFinish("invalid cmd")
-	}
-
-	cmd = ss[0]
-	arg := ss[1]
-
-	switch cmd {
-	case "popular":
-		secs, e := strconv.Atoi(arg)
-		if e == nil {
-			m.mostFrequentWord(secs, func(meta *wordmeta.Data, e error) {
-				if e != nil {
-					log.Error("get freq failed:%s", e.Error())
-					return
-				}
-				if meta == nil {
-					onFinish("")
-				} else {
-					onFinish(meta.Word)
-				}
-			})
-		}
-	case "stats":
-		p := m.playersByName[arg]
-		if p != nil {
-			formatDur := func(d time.Duration) string {
-				dur := d.Round(time.Hour)
-				day := d / 24
-				h := day - day*24
-				dur -= dur * time.Hour
-				min := d / time.Minute
-				d -= min * time.Minute
-				return fmt.Sprintf("%02d %02d %02d %02d", day, h, min, d)
-			}
-			onFinish(formatDur(time.Now().Sub(p.LoginTime)))
+	from := p.username
+	r.filter.CheckWith(content, m.filterPolicy(filterRoomChat), func(res *filter.Result) {
+		text, dropped, e := m.applyFilter(&FlaggedMsg{Where: filterRoomChat, From: from, RoomID: r.id}, res)
+		switch {
+		case e != nil:
+			sendRoomChat(p, systemName, e.Error())
+		case dropped:
+			// 只回显给发送者, 其他成员与历史中都没有
+			sendRoomChat(p, from, content)
+		default:
+			m.recordWordFrequency(text)
+			r.notifyRoomChat(playerFD, r.AddMsg(from, text))
 		}
-	default:
-		onFinish("non-supported cmd")
-	}
+	})
+	return nil
 }
 
-func (m *Manager) mostFrequentWord(lastNSeconds int, onFinish func(meta *wordmeta.Data, e error)) {
-	m.wordFrequency.GetFrequencyByTime(lastNSeconds, func(meta *wordmeta.Data, e error) {
-		if onFinish == nil {
-			if e != nil {
-				log.Error("get freq failed:%s", e.Error())
-				return
-			}
-			if meta == nil {
-				log.Error("nil meta")
-				return
-			}
-		} else {
-			onFinish(meta, e)
-		}
-	})
+// 只发给p的房间消息, 不计入历史
+func sendRoomChat(p *Agent, from, content string) {
+	p.SendClient(pb.CSMsgID_NTF_ROOM_CHAT, &pb.CSNtfBody{RoomChat: &pb.CSNtfRoomChat{
+		Username: from,
+		Content:  content,
+		Time:     time.Now().UnixNano() / int64(time.Millisecond),
+	}}, nil)
 }
 
-func (m *Manager) notifyHistoryMsgs(playerFD int64) {
+// 下发序号大于sinceSeq的历史消息, sinceSeq为0时只下发最近的一页
+func (m *Manager) notifyHistoryMsgs(playerFD, sinceSeq int64) {
 	p := m.players[playerFD]
 	if p == nil {
 		return
@@ -248,40 +437,71 @@ func (m *Manager) notifyHistoryMsgs(playerFD int64) {
 		return
 	}
 
+	// 整合消息
 	var (
-		ntfID    = pb.CSMsgID_NTF_HISTROY_MSG
-		csNtf    = &pb.CSNtfBody{HistoryMsg: &pb.CSNtfHistoryMsg{}}
-		msgCount = r.historyMsgs.Len()
+		history []*pb.HistoryChat
+		more    bool
 	)
-
-	// 整合消息
-	csNtf.HistoryMsg.History = make([]*pb.HistoryChat, msgCount)
-	n := r.historyMsgs.Front()
-	if n == nil {
+	if sinceSeq == 0 {
+		var e error
+		if history, more, e = r.historyPage(0, 0, historyJoinSize); e != nil {
+			log.Error("read history of room %d failed:%s", r.id, e.Error())
+		}
+	} else {
+		history = r.historySince(sinceSeq)
+	}
+	if len(history) == 0 {
 		return
 	}
-	i := 0
-	for n := r.historyMsgs.Front(); n != nil; n = n.Next() {
-		csNtf.HistoryMsg.History[i] = n.Value.(*pb.HistoryChat)
-		i++
+
+	csNtf := &pb.CSNtfBody{HistoryMsg: &pb.CSNtfHistoryMsg{RoomID: r.id, History: history, More: more}}
+	p.SendClient(pb.CSMsgID_NTF_HISTROY_MSG, csNtf, nil)
+}
+
+// 当前房间的一页历史消息
+func (m *Manager) HistoryPage(p *Agent, req *pb.CSReqRoomHistory) (*pb.CSRspRoomHistory, error) {
+	r := m.rooms[p.GetRoomID()]
+	if r == nil {
+		return nil, errors.New("not in any room")
 	}
 
-	p.SendClient(ntfID, csNtf, nil)
+	history, more, e := r.historyPage(req.BeforeID, req.AfterID, int(req.Limit))
+	if e != nil {
+		log.Error("read history of room %d failed:%s", r.id, e.Error())
+		return nil, errors.New("read history failed")
+	}
+	return &pb.CSRspRoomHistory{RoomID: r.id, History: history, More: more}, nil
 }
 
-func (m *Manager) SetName(p *Agent, name string, onFinish func(passed string)) {
-	if p == nil {
-		return
+// 在房间任务中搜索, 不阻塞主协程, onFinish在主协程中调用
+func (m *Manager) SearchHistory(p *Agent, req *pb.CSReqSearchHistory, onFinish func(*pb.CSRspSearchHistory, error)) error {
+	r := m.rooms[p.GetRoomID()]
+	if r == nil {
+		return errors.New("not in any room")
 	}
-	if _, ok := m.names[name]; ok {
-		return
+	if len(req.Query) > maxSearchLen || len(req.From) > maxSearchLen {
+		return errors.New("query too long")
 	}
 
-	m.filter.Check(name, func(newStr string) {
-		if onFinish != nil {
-			onFinish(newStr)
+	var (
+		q = &search.Query{
+			Text:     req.Query,
+			From:     req.From,
+			BeforeID: req.BeforeID,
+			Limit:    int(req.Limit),
 		}
+		rsp = &pb.CSRspSearchHistory{RoomID: r.id}
+		err error
+	)
+	ret := m.AddRoomTask(r.id, func() {
+		rsp.Results, rsp.More, err = r.index.Search(q)
+	}, func() {
+		onFinish(rsp, err)
 	})
+	if ret < 0 {
+		return errors.New("search failed")
+	}
+	return nil
 }
 
 func (m *Manager) GetRoomList(maxCount int32) []*pb.RoomInfo {
@@ -289,40 +509,115 @@ func (m *Manager) GetRoomList(maxCount int32) []*pb.RoomInfo {
 	for id, r := range m.rooms {
 		ri := &pb.RoomInfo{
 			CurrentMemberNum: int32(len(r.members)),
-			TotalMemberNum:   100,
+			TotalMemberNum:   roomCapacity,
 			RoomID:           id,
+			Name:             r.name,
 		}
 		ret = append(ret, ri)
 	}
 	return ret
 }
 
-func (m *Manager) Chat(p *Agent, chat *pb.CSReqChat) error {
-	otherp := m.playersByName[chat.Username]
-	if otherp == nil {
-		return errors.New("target player not found")
+// 私聊可跨房间, 对方不在线时存入收件箱; 内容只过滤一次, onFinish在主协程中调用
+func (m *Manager) Chat(p *Agent, chat *pb.CSReqChat, onFinish func(msg *pb.PrivateMsg, stored bool, e error)) error {
+	if m.closing {
+		return errors.New("server is shutting down")
+	}
+	if m.players[p.GetFD()] != p {
+		return errors.New("not logged in")
 	}
-	if p.roomID != otherp.roomID {
-		return errors.New("not in one same room")
+	to := chat.Username
+	if to == "" {
+		return errors.New("target player is required")
+	}
+	if to == p.username {
+		return errors.New("cannot chat with yourself")
+	}
+	if e := m.canReceive(to); e != nil {
+		return e
+	}
+	if chat.Content == "" {
+		return errors.New("empty content")
+	}
+	if utf8.RuneCountInString(chat.Content) > maxPrivateLen {
+		return fmt.Errorf("content longer than %d", maxPrivateLen)
+	}
+	if until, ok := m.mutedUntil(p, time.Now()); ok {
+		return fmt.Errorf("muted until %s", until.Format(time.RFC3339))
 	}
 
-	r := m.rooms[p.roomID]
-	if r == nil {
-		return errors.New("room entity not found")
+	from := p.username
+	m.filter.CheckWith(chat.Content, m.filterPolicy(filterPrivateChat), func(res *filter.Result) {
+		text, dropped, e := m.applyFilter(&FlaggedMsg{Where: filterPrivateChat, From: from, To: to}, res)
+		if e != nil {
+			onFinish(nil, false, e)
+			return
+		}
+		msg := &pb.PrivateMsg{
+			ID:      UUID.Get(),
+			From:    from,
+			To:      to,
+			Content: text,
+			Time:    time.Now().UnixNano() / int64(time.Millisecond),
+		}
+		// 被记录的私聊对发送者表现为已送达
+		if dropped {
+			onFinish(msg, false, nil)
+			return
+		}
+		stored, e := m.deliver(msg)
+		onFinish(msg, stored, e)
+	})
+	return nil
+}
+
+// 在线、断线等待恢复或名字有主时可以收私聊, 其余名字以后可能被任何人使用
+func (m *Manager) canReceive(name string) error {
+	if e := validateName(name); e != nil {
+		return e
+	}
+	if _, ok := m.names[name]; ok {
+		return nil
+	}
+	if !m.nameOwned(name) {
+		return fmt.Errorf("%w: %s is offline and has no reserved name or account", errNoRecipient, name)
 	}
+	return nil
+}
 
-	m.AddRoomTask(p.roomID, func() {
-		r.filter.Check(chat.Content, func(newStr string) {
-			otherp.SendClient(pb.CSMsgID_NTF_CHAT, &pb.CSNtfBody{Chat: &pb.CSNtfChat{
-				From:    p.username,
-				Content: newStr,
-			}}, nil)
-		})
-	}, nil)
+// 在线则直接下发, 否则存入收件箱
+func (m *Manager) deliver(msg *pb.PrivateMsg) (bool, error) {
+	if other := m.playersByName[msg.To]; other != nil {
+		other.SendClient(pb.CSMsgID_NTF_CHAT, &pb.CSNtfBody{Chat: &pb.CSNtfChat{
+			From:    msg.From,
+			Content: msg.Content,
+			MsgID:   msg.ID,
+			Time:    msg.Time,
+		}}, nil)
+		return false, nil
+	}
+	// 过滤期间对方的会话可能已过期
+	if e := m.canReceive(msg.To); e != nil {
+		return false, e
+	}
+	return true, m.inbox.add(msg)
+}
 
-	otherp.SendClient(pb.CSMsgID_NTF_CHAT, &pb.CSNtfBody{Chat: &pb.CSNtfChat{
-		From:    p.username,
-		Content: chat.Content,
+// 登录或恢复会话后下发收件箱中的未读私聊
+func (m *Manager) notifyInbox(p *Agent) {
+	msgs := m.inbox.unread(p.GetUsername())
+	if len(msgs) == 0 {
+		return
+	}
+	p.SendClient(pb.CSMsgID_NTF_INBOX, &pb.CSNtfBody{Inbox: &pb.CSNtfInbox{
+		Msgs:   msgs,
+		Unread: countUnread(msgs),
 	}}, nil)
-	return nil
+}
+
+func (m *Manager) InboxRead(p *Agent, upToID int64) (int, error) {
+	if m.players[p.GetFD()] != p {
+		return 0, errors.New("not logged in")
+	}
+	return m.inbox.read(p.GetUsername(), upToID), nil
 }
@@ -0,0 +1,333 @@
+package game
+
+import (
+	"cloudcadetest/framework/log"
+	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/auth"
+	"cloudcadetest/serverimpl/chat/conf"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// 房间角色、禁言、踢出与服务器封禁, 均在主协程中调用
+// 角色与禁言按用户名记录, 离开房间或下线后仍有效
+
+const roomKickBlock = 5 * time.Minute // 被踢出房间后不能重新加入的时长
+
+var (
+	errNoPermission = errors.New("permission denied")
+	errBanned       = errors.New("banned")
+)
+
+// 错误对应的应答码
+func errCode(e error) pb.ERROR_CODE {
+	switch {
+	case errors.Is(e, errNoPermission):
+		return pb.ERROR_CODE_NO_PERMISSION
+	case errors.Is(e, errBanned):
+		return pb.ERROR_CODE_BANNED
+	case errors.Is(e, errInvalidName):
+		return pb.ERROR_CODE_INVALID_NAME
+	case errors.Is(e, errNameTaken):
+		return pb.ERROR_CODE_NAME_TAKEN
+	case errors.Is(e, auth.ErrNoCredentials):
+		return pb.ERROR_CODE_AUTH_REQUIRED
+	case errors.Is(e, auth.ErrBadCredentials), errors.Is(e, auth.ErrBadToken):
+		return pb.ERROR_CODE_AUTH_FAILED
+	case errors.Is(e, auth.ErrTokenExpired):
+		return pb.ERROR_CODE_TOKEN_EXPIRED
+	case errors.Is(e, auth.ErrLocked):
+		return pb.ERROR_CODE_ACCOUNT_LOCKED
+	case errors.Is(e, errRejected):
+		return pb.ERROR_CODE_CONTENT_REJECTED
+	}
+	return pb.ERROR_CODE_FAILED
+}
+
+func isAdmin(name string) bool {
+	for _, n := range conf.Server.AdminNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// 服务器管理员在所有房间都是ROLE_ADMIN, r为nil时只判断是否为管理员
+func roleOf(r *Room, name string) pb.ROOM_ROLE {
+	if isAdmin(name) {
+		return pb.ROOM_ROLE_ROLE_ADMIN
+	}
+	if r == nil {
+		return pb.ROOM_ROLE_ROLE_MEMBER
+	}
+	return r.roles[name]
+}
+
+// 全服禁言(管理员禁言或刷屏禁言)的截止时间, 取较晚的
+func (m *Manager) mutedUntil(p *Agent, now time.Time) (time.Time, bool) {
+	until, muted := m.floodMutedUntil(p, now)
+	if admin := m.mutes[p.GetUsername()]; now.Before(admin) && admin.After(until) {
+		until, muted = admin, true
+	}
+	return until, muted
+}
+
+// 用户名或IP被封禁时返回errBanned
+func checkBan(p *Agent, username string) error {
+	now := time.Now()
+	b := bans.name(username, now)
+	if b == nil {
+		b = bans.ip(p.IP(), now)
+	}
+	if b == nil {
+		return nil
+	}
+
+	e := errBanned
+	if !b.Until.IsZero() {
+		e = fmt.Errorf("%w until %s", e, b.Until.Format(time.RFC3339))
+	}
+	if b.Reason != "" {
+		e = fmt.Errorf("%w: %s", e, b.Reason)
+	}
+	return e
+}
+
+// 过期的记录顺便删除
+func (r *Room) mutedUntil(name string, now time.Time) (time.Time, bool) {
+	until, ok := r.mutes[name]
+	if !ok {
+		return time.Time{}, false
+	}
+	if !now.Before(until) {
+		delete(r.mutes, name)
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+func (r *Room) kickedUntil(name string, now time.Time) (time.Time, bool) {
+	until, ok := r.kicked[name]
+	if !ok {
+		return time.Time{}, false
+	}
+	if !now.Before(until) {
+		delete(r.kicked, name)
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+func (r *Room) notifyRole(name string, role pb.ROOM_ROLE, by string) {
+	r.broadcast(-1, pb.CSMsgID_NTF_ROOM_ROLE, &pb.CSNtfBody{RoomRole: &pb.CSNtfRoomRole{
+		RoomID:   r.id,
+		Username: name,
+		Role:     role,
+		By:       by,
+	}})
+}
+
+// until为零值表示解除
+func (r *Room) notifyMute(name string, until time.Time, by string) {
+	var ms int64
+	if !until.IsZero() {
+		ms = until.UnixNano() / int64(time.Millisecond)
+	}
+	r.broadcast(-1, pb.CSMsgID_NTF_ROOM_MUTE, &pb.CSNtfBody{RoomMute: &pb.CSNtfRoomMute{
+		RoomID:   r.id,
+		Username: name,
+		Until:    ms,
+		By:       by,
+	}})
+}
+
+// 在操作者当前房间管理name, 操作者的角色需不低于need且高于对方
+func (m *Manager) moderate(p *Agent, name string, need pb.ROOM_ROLE) (*Room, error) {
+	if m.players[p.GetFD()] != p {
+		return nil, errors.New("not logged in")
+	}
+	r := m.rooms[p.GetRoomID()]
+	if r == nil {
+		return nil, errors.New("not in any room")
+	}
+	if name == "" {
+		return nil, errors.New("username is required")
+	}
+	if name == p.GetUsername() {
+		return nil, errors.New("cannot moderate yourself")
+	}
+
+	role := roleOf(r, p.GetUsername())
+	if role < need || role <= roleOf(r, name) {
+		return nil, errNoPermission
+	}
+	return r, nil
+}
+
+// 在房间中的在线成员
+func (m *Manager) roomMember(r *Room, name string) *Agent {
+	other := m.playersByName[name]
+	if other == nil || other.GetRoomID() != r.id {
+		return nil
+	}
+	return other
+}
+
+// 任免管理员, 设为房主时原房主成为管理员
+func (m *Manager) SetRole(p *Agent, name string, role pb.ROOM_ROLE) error {
+	switch role {
+	case pb.ROOM_ROLE_ROLE_MEMBER, pb.ROOM_ROLE_ROLE_MODERATOR, pb.ROOM_ROLE_ROLE_OWNER:
+	default:
+		return fmt.Errorf("invalid role %s", role)
+	}
+	r, e := m.moderate(p, name, pb.ROOM_ROLE_ROLE_OWNER)
+	if e != nil {
+		return e
+	}
+	if r.roles[name] == role {
+		return fmt.Errorf("%s is already %s", name, role)
+	}
+	// 只有房间中的成员可以被任命, 撤销不限
+	if role != pb.ROOM_ROLE_ROLE_MEMBER && m.roomMember(r, name) == nil {
+		return fmt.Errorf("%s is not in room %d", name, r.id)
+	}
+
+	by := p.GetUsername()
+	if role == pb.ROOM_ROLE_ROLE_OWNER {
+		for n, old := range r.roles {
+			if old == pb.ROOM_ROLE_ROLE_OWNER {
+				r.roles[n] = pb.ROOM_ROLE_ROLE_MODERATOR
+				r.notifyRole(n, pb.ROOM_ROLE_ROLE_MODERATOR, by)
+			}
+		}
+	}
+	if role == pb.ROOM_ROLE_ROLE_MEMBER {
+		delete(r.roles, name)
+	} else {
+		r.roles[name] = role
+	}
+	r.notifyRole(name, role, by)
+	p.LogRelease("set role of %s to %s", name, role)
+	return nil
+}
+
+// 在当前房间禁言, d不大于0时解除; 返回禁言截止时间
+func (m *Manager) RoomMute(p *Agent, name string, d time.Duration) (time.Time, error) {
+	r, e := m.moderate(p, name, pb.ROOM_ROLE_ROLE_MODERATOR)
+	if e != nil {
+		return time.Time{}, e
+	}
+
+	var until time.Time
+	if d <= 0 {
+		if _, ok := r.mutedUntil(name, time.Now()); !ok {
+			return time.Time{}, fmt.Errorf("%s is not muted", name)
+		}
+		delete(r.mutes, name)
+	} else {
+		until = time.Now().Add(d)
+		r.mutes[name] = until
+	}
+	r.notifyMute(name, until, p.GetUsername())
+	p.LogRelease("mute %s in room %d until %s", name, r.id, until)
+	return until, nil
+}
+
+// 移出当前房间, 对方保持在线, roomKickBlock内不能重新加入
+func (m *Manager) RoomKick(p *Agent, name, reason string) error {
+	r, e := m.moderate(p, name, pb.ROOM_ROLE_ROLE_MODERATOR)
+	if e != nil {
+		return e
+	}
+	other := m.roomMember(r, name)
+	if other == nil {
+		return fmt.Errorf("%s is not in room %d", name, r.id)
+	}
+
+	r.kicked[name] = time.Now().Add(roomKickBlock)
+	other.SendClient(pb.CSMsgID_NTF_ROOM_KICKED, &pb.CSNtfBody{RoomKicked: &pb.CSNtfRoomKicked{
+		RoomID: r.id,
+		By:     p.GetUsername(),
+		Reason: reason,
+	}}, nil)
+	m.leaveRoom(other, false)
+	p.LogRelease("kick %s from room %d:%s", name, r.id, reason)
+	return nil
+}
+
+// 客户端发起的封禁, 只有服务器管理员可以操作
+func (m *Manager) BanBy(p *Agent, req *pb.CSReqBan) error {
+	if m.players[p.GetFD()] != p {
+		return errors.New("not logged in")
+	}
+	if roleOf(nil, p.GetUsername()) != pb.ROOM_ROLE_ROLE_ADMIN {
+		return errNoPermission
+	}
+	if req.Lift {
+		return m.Unban(req.Username, req.IP)
+	}
+
+	ip := req.IP
+	if req.WithIP {
+		other := m.playersByName[req.Username]
+		if other == nil {
+			return fmt.Errorf("player %s not found", req.Username)
+		}
+		ip = other.IP()
+	}
+	if req.Username == p.GetUsername() || ip == p.IP() {
+		return errors.New("cannot ban yourself")
+	}
+	if isAdmin(req.Username) {
+		return errNoPermission
+	}
+	return m.Ban(req.Username, ip, time.Duration(req.Seconds)*time.Second, req.Reason, p.GetUsername())
+}
+
+// name与ip至少有一个, d不大于0时永久封禁; 匹配的在线玩家被踢下线
+func (m *Manager) Ban(name, ip string, d time.Duration, reason, by string) error {
+	if name == "" && ip == "" {
+		return errors.New("name or ip is required")
+	}
+	if ip != "" && net.ParseIP(ip) == nil {
+		return fmt.Errorf("invalid ip %s", ip)
+	}
+
+	b := &ban{Reason: reason, By: by}
+	if d > 0 {
+		b.Until = time.Now().Add(d)
+	}
+	bans.add(name, ip, b)
+	if e := bans.save(conf.Server.BanFile); e != nil {
+		log.Error("save bans failed:%s", e.Error())
+	}
+	log.Release("%s banned name[%s] ip[%s] for %s:%s", by, name, ip, d, reason)
+
+	if reason == "" {
+		reason = "banned"
+	}
+	for _, p := range m.players {
+		if (name != "" && p.GetUsername() == name) || (ip != "" && p.IP() == ip) {
+			p.Kick(pb.KICK_REASON_KICK_BANNED, reason)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) Unban(name, ip string) error {
+	if !bans.lift(name, ip) {
+		return errors.New("ban not found")
+	}
+	if e := bans.save(conf.Server.BanFile); e != nil {
+		log.Error("save bans failed:%s", e.Error())
+	}
+	log.Release("unbanned name[%s] ip[%s]", name, ip)
+	return nil
+}
+
+func (m *Manager) Bans() []BanView {
+	return bans.list(time.Now())
+}
@@ -0,0 +1,291 @@
+package game
+
+import (
+	"cloudcadetest/common/word/filter"
+	"cloudcadetest/framework/log"
+	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/conf"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	minNameLen = 2
+	maxNameLen = 16
+)
+
+var (
+	errInvalidName = errors.New("invalid name")
+	errNameTaken   = errors.New("name taken")
+)
+
+// 保留的用户名, 凭保留时下发的密钥登录, 长时间未登录后释放
+type reservation struct {
+	KeyHash  string    `json:"key_hash"` // 密钥的sha256
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// 只在主协程中访问
+type reservations struct {
+	names map[string]*reservation
+}
+
+func newReservations() *reservations {
+	return &reservations{names: map[string]*reservation{}}
+}
+
+func nameReserveTime() time.Duration {
+	return time.Duration(conf.Server.NameReserveDays) * 24 * time.Hour
+}
+
+// 长度2~16, 只允许字母、数字、下划线和连字符
+func validateName(name string) error {
+	if n := utf8.RuneCountInString(name); n < minNameLen || n > maxNameLen {
+		return fmt.Errorf("%w: length should be in [%d, %d]", errInvalidName, minNameLen, maxNameLen)
+	}
+	for _, c := range name {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' && c != '-' {
+			return fmt.Errorf("%w: %q not allowed", errInvalidName, c)
+		}
+	}
+	if strings.EqualFold(name, systemName) {
+		return fmt.Errorf("%w: %s is reserved", errNameTaken, name)
+	}
+	return nil
+}
+
+func hashNameKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// 保留中的名字需要匹配的密钥, 过期的保留在此时释放
+func (rs *reservations) check(name, key string, now time.Time) error {
+	res := rs.names[name]
+	if res == nil {
+		return nil
+	}
+	if nameReserveTime() <= 0 || now.Sub(res.LastSeen) > nameReserveTime() {
+		delete(rs.names, name)
+		return nil
+	}
+	if key == "" || subtle.ConstantTimeCompare([]byte(hashNameKey(key)), []byte(res.KeyHash)) != 1 {
+		return fmt.Errorf("%w: %s is reserved", errNameTaken, name)
+	}
+	return nil
+}
+
+// 返回新的密钥, 已保留时替换原密钥
+func (rs *reservations) reserve(name string, now time.Time) (string, error) {
+	if nameReserveTime() <= 0 {
+		return "", errors.New("name reservation is disabled")
+	}
+	key := newResumeToken()
+	if key == "" {
+		return "", errors.New("gen name key failed")
+	}
+	rs.names[name] = &reservation{KeyHash: hashNameKey(key), LastSeen: now}
+	return key, nil
+}
+
+// 保留是否仍然有效, 不释放过期的保留
+func (rs *reservations) held(name string, now time.Time) bool {
+	res := rs.names[name]
+	return res != nil && nameReserveTime() > 0 && now.Sub(res.LastSeen) <= nameReserveTime()
+}
+
+func (rs *reservations) touch(name string, now time.Time) {
+	if res := rs.names[name]; res != nil {
+		res.LastSeen = now
+	}
+}
+
+func (rs *reservations) release(name string) {
+	delete(rs.names, name)
+}
+
+func (rs *reservations) save(path string) error {
+	if path == "" {
+		return nil
+	}
+	data, e := json.Marshal(rs.names)
+	if e != nil {
+		return e
+	}
+	return writeFile(path, data)
+}
+
+func (rs *reservations) load(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	data, e := ioutil.ReadFile(path)
+	if e != nil {
+		if os.IsNotExist(e) {
+			return nil
+		}
+		return e
+	}
+	return json.Unmarshal(data, &rs.names)
+}
+
+func (m *Manager) saveReservations() {
+	if e := m.reserved.save(conf.Server.NameFile); e != nil {
+		log.Error("save reserved names failed:%s", e.Error())
+	}
+}
+
+// p以外的玩家是否可以使用该名字
+func (m *Manager) nameAvailable(p *Agent, name, key string) error {
+	if _, ok := m.names[name]; ok {
+		return fmt.Errorf("%w: %s is in use", errNameTaken, name)
+	}
+	if e := checkBan(p, name); e != nil {
+		return e
+	}
+	// 开启认证后名字归账号所有, 不再需要保留; 未开启时无法确认身份, 管理员的名字不可用
+	if m.auth.Enabled() {
+		return nil
+	}
+	if isAdmin(name) {
+		return fmt.Errorf("%w: %s is reserved for admins", errNameTaken, name)
+	}
+	return m.reserved.check(name, key, time.Now())
+}
+
+// 名字有账号或有效的保留时只有其主人能以该名字登录; 只用令牌认证时无法列出账号, 开启认证即视为有主
+func (m *Manager) nameOwned(name string) bool {
+	if m.auth.Enabled() {
+		return m.accounts == nil || m.accounts.Has(name)
+	}
+	return m.reserved.held(name, time.Now())
+}
+
+// 校验长度与字符后过滤敏感词, 含敏感词的拒绝并说明命中的词; onFinish在主协程中调用
+func (m *Manager) checkName(name string, onFinish func(e error)) {
+	if e := validateName(name); e != nil {
+		onFinish(e)
+		return
+	}
+	m.filter.CheckWith(name, m.filterPolicy(filterName), func(res *filter.Result) {
+		if !res.Dirty() {
+			onFinish(nil)
+			return
+		}
+		// 名字不能替换后使用, 任何策略都拒绝, 策略为flag时另外记录
+		m.applyFilter(&FlaggedMsg{Where: filterName, From: name}, res)
+		onFinish(fmt.Errorf("%w: contains %s", errInvalidName, strings.Join(res.Words(), ", ")))
+	})
+}
+
+// 改名并通知所在房间, reserve时同时保留新名字; 名字为当前名字时只保留
+func (m *Manager) SetName(p *Agent, name string, reserve bool, onFinish func(key string, e error)) {
+	if m.players[p.GetFD()] != p {
+		onFinish("", errors.New("not logged in"))
+		return
+	}
+	if m.auth.Enabled() {
+		onFinish("", errors.New("names are bound to accounts"))
+		return
+	}
+	if name == p.GetUsername() {
+		if !reserve {
+			onFinish("", fmt.Errorf("already named %s", name))
+			return
+		}
+		key, e := m.reserved.reserve(name, time.Now())
+		if e == nil {
+			m.saveReservations()
+			p.LogRelease("name reserved")
+		}
+		onFinish(key, e)
+		return
+	}
+	if reserve && nameReserveTime() <= 0 {
+		onFinish("", errors.New("name reservation is disabled"))
+		return
+	}
+	if e := m.nameAvailable(p, name, ""); e != nil {
+		onFinish("", e)
+		return
+	}
+
+	m.checkName(name, func(e error) {
+		// 过滤期间名字可能已被占用或玩家已离开
+		if e == nil && m.players[p.GetFD()] != p {
+			e = errors.New("not logged in")
+		}
+		if e == nil {
+			e = m.nameAvailable(p, name, "")
+		}
+		if e != nil {
+			onFinish("", e)
+			return
+		}
+
+		m.rename(p, name)
+		var key string
+		if reserve {
+			key, e = m.reserved.reserve(name, time.Now())
+		}
+		m.saveReservations()
+		onFinish(key, e)
+	})
+}
+
+// 按名字记录的状态随之转移, 原名字的保留释放
+func (m *Manager) rename(p *Agent, name string) {
+	old := p.GetUsername()
+	delete(m.names, old)
+	delete(m.playersByName, old)
+	m.names[name] = struct{}{}
+	m.playersByName[name] = p
+	oldKey := penaltyKey(p)
+	p.SetUsername(name)
+	if f, ok := m.penalties[oldKey]; ok {
+		delete(m.penalties, oldKey)
+		m.penalties[penaltyKey(p)] = f
+	}
+	if until, ok := m.mutes[old]; ok {
+		delete(m.mutes, old)
+		m.mutes[name] = until
+	}
+	m.reserved.release(old)
+	m.inbox.drop(name)
+	m.inbox.rename(old, name)
+
+	for _, r := range m.rooms {
+		if role, ok := r.roles[old]; ok {
+			delete(r.roles, old)
+			r.roles[name] = role
+		}
+		if until, ok := r.mutes[old]; ok {
+			delete(r.mutes, old)
+			r.mutes[name] = until
+		}
+		if until, ok := r.kicked[old]; ok {
+			delete(r.kicked, old)
+			r.kicked[name] = until
+		}
+	}
+
+	if r := m.rooms[p.GetRoomID()]; r != nil {
+		r.broadcast(-1, pb.CSMsgID_NTF_RENAME, &pb.CSNtfBody{Rename: &pb.CSNtfRename{
+			RoomID:  r.id,
+			OldName: old,
+			NewName: name,
+		}})
+	}
+	p.LogRelease("renamed from %s", old)
+}
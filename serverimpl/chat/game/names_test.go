@@ -0,0 +1,230 @@
+package game
+
+import (
+	"cloudcadetest/common/word/filter"
+	"cloudcadetest/framework/module"
+	"cloudcadetest/framework/network"
+	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/auth"
+	"cloudcadetest/serverimpl/chat/conf"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// 只提供远端地址的连接
+type testConn struct {
+	network.IConn
+}
+
+func (c testConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234}
+}
+
+// 没有任务池, 过滤在调用方协程中完成
+type testFS string
+
+func (fs testFS) GetServerModule() *module.ServerMod { return nil }
+func (fs testFS) GetID() int64                       { return 0 }
+func (fs testFS) GetWordListFilePath() string        { return string(fs) }
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"al", "test_123", "bob-2", "张三", "abcdefghijklmnop"} {
+		if e := validateName(name); e != nil {
+			t.Errorf("%s:%v", name, e)
+		}
+	}
+	for _, name := range []string{"", "a", "abcdefghijklmnopq", "a b", "a\tb", "bob!", "System"} {
+		if validateName(name) == nil {
+			t.Errorf("%q should be invalid", name)
+		}
+	}
+}
+
+func TestReservations(t *testing.T) {
+	newGMTest(t)
+	rs := newReservations()
+	now := time.Now()
+	if _, e := rs.reserve("alice", now); e == nil {
+		t.Fatal("reservation should be disabled")
+	}
+
+	conf.Server.NameReserveDays = 1
+	key, e := rs.reserve("alice", now)
+	if e != nil || key == "" {
+		t.Fatal(e)
+	}
+	if e = rs.check("alice", "", now); !errors.Is(e, errNameTaken) {
+		t.Fatalf("no key:%v", e)
+	}
+	if e = rs.check("alice", key+"x", now); !errors.Is(e, errNameTaken) {
+		t.Fatalf("wrong key:%v", e)
+	}
+	if e = rs.check("alice", key, now); e != nil {
+		t.Fatal(e)
+	}
+
+	// 保存后重新加载, 超过保留期未登录的释放
+	dir, _ := ioutil.TempDir("", "names")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "names.json")
+	if e = rs.save(path); e != nil {
+		t.Fatal(e)
+	}
+	rs = newReservations()
+	if e = rs.load(path); e != nil {
+		t.Fatal(e)
+	}
+	if e = rs.check("alice", key, now); e != nil {
+		t.Fatal(e)
+	}
+	rs.touch("alice", now.Add(-23*time.Hour))
+	if rs.check("alice", "", now) == nil {
+		t.Fatal("still reserved within a day")
+	}
+	rs.touch("alice", now.Add(-25*time.Hour))
+	if e = rs.check("alice", "", now); e != nil || len(rs.names) != 0 {
+		t.Fatalf("expired reservation:%v", e)
+	}
+}
+
+func TestSetName(t *testing.T) {
+	gt := newGMTest(t)
+	m := gt.m
+	conf.Server.NameReserveDays = 30
+	conf.Server.AdminNames = []string{"root"}
+
+	dir, _ := ioutil.TempDir("", "names")
+	defer os.RemoveAll(dir)
+	words := filepath.Join(dir, "list.txt")
+	ioutil.WriteFile(words, []byte("shit\n"), 0644)
+	m.filter = filter.New(testFS(words))
+
+	setName := func(name, to string, reserve bool) (string, error) {
+		var (
+			key string
+			err error
+			n   int
+		)
+		m.SetName(m.playersByName[name], to, reserve, func(k string, e error) {
+			key, err = k, e
+			n++
+		})
+		if n != 1 {
+			t.Fatalf("%s -> %s finished %d times", name, to, n)
+		}
+		return key, err
+	}
+
+	for to, want := range map[string]error{
+		"bob":      errNameTaken,
+		"root":     errNameTaken,
+		"system":   errNameTaken,
+		"c":        errInvalidName,
+		"car ol":   errInvalidName,
+		"bullshit": errInvalidName,
+	} {
+		if _, e := setName("carol", to, false); !errors.Is(e, want) {
+			t.Errorf("carol -> %s:%v", to, e)
+		}
+	}
+	if _, e := setName("carol", "carol", false); e == nil {
+		t.Error("same name should fail")
+	}
+
+	// 角色、收件箱随名字转移
+	m.inbox.add(&pb.PrivateMsg{ID: 1, From: "bob", To: "alice"})
+	key, e := setName("alice", "alicia", true)
+	if e != nil || key == "" {
+		t.Fatalf("rename:%v", e)
+	}
+	alicia := m.playersByName["alicia"]
+	if alicia == nil || alicia.GetUsername() != "alicia" || m.playersByName["alice"] != nil {
+		t.Fatal("player not renamed")
+	}
+	if _, ok := m.names["alice"]; ok {
+		t.Fatal("old name should be released")
+	}
+	if gt.r.roles["alicia"] != pb.ROOM_ROLE_ROLE_OWNER || len(m.inbox.unread("alicia")) != 1 {
+		t.Fatal("role or inbox not moved")
+	}
+
+	// 保留的名字下线后需凭密钥登录
+	if e = m.Leave(alicia.GetFD()); e != nil {
+		t.Fatal(e)
+	}
+	p := &Agent{conn: testConn{}, fd: 10}
+	if _, e = m.Join(p, "alicia", ""); !errors.Is(e, errNameTaken) {
+		t.Fatalf("join reserved name:%v", e)
+	}
+	if _, e = m.Join(p, "alicia", key); e != nil {
+		t.Fatal(e)
+	}
+
+	// 改名后原名字的保留释放
+	if _, e = setName("alicia", "alice", false); e != nil {
+		t.Fatal(e)
+	}
+	if len(m.reserved.names) != 0 {
+		t.Fatal("reservation of old name should be released")
+	}
+}
+
+// 开启认证后名字归账号所有, 不能改名, 保留不再生效
+func TestSetNameWithAuth(t *testing.T) {
+	gt := newGMTest(t)
+	m := gt.m
+	secret := []byte("secret")
+	m.auth = auth.New(nil, auth.NewTokenBackend(secret))
+	m.reserved.names["dave"] = &reservation{KeyHash: hashNameKey("key"), LastSeen: time.Now()}
+	conf.Server.NameReserveDays = 30
+
+	var err error
+	m.SetName(m.playersByName["carol"], "carl", false, func(k string, e error) { err = e })
+	if err == nil {
+		t.Fatal("rename should be disabled")
+	}
+
+	token := auth.Sign(secret, "dave", time.Now().Add(time.Minute))
+	m.authenticate("dave", "127.0.0.1", auth.Credentials{Token: token}, func(e error) { err = e })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = m.Join(&Agent{conn: testConn{}, fd: 10}, "dave", ""); err != nil {
+		t.Fatal(err)
+	}
+	m.authenticate("dave", "127.0.0.1", auth.Credentials{}, func(e error) { err = e })
+	if errCode(err) != pb.ERROR_CODE_AUTH_REQUIRED {
+		t.Fatalf("no credentials:%v", err)
+	}
+}
+
+// 未开启认证时不能以管理员的名字登录, 开启后凭账号认证登录
+func TestAdminNameLogin(t *testing.T) {
+	gt := newGMTest(t)
+	m := gt.m
+	conf.Server.AdminNames = []string{"root"}
+
+	p := &Agent{conn: testConn{}, fd: 10}
+	if _, e := m.Join(p, "root", ""); !errors.Is(e, errNameTaken) {
+		t.Fatalf("unauthenticated admin login:%v", e)
+	}
+	if m.playersByName["root"] != nil || roleOf(gt.r, p.GetUsername()) == pb.ROOM_ROLE_ROLE_ADMIN {
+		t.Fatal("admin rights granted without auth")
+	}
+
+	secret := []byte("secret")
+	m.auth = auth.New(nil, auth.NewTokenBackend(secret))
+	var err error
+	m.authenticate("root", "127.0.0.1", auth.Credentials{Token: auth.Sign(secret, "root", time.Now().Add(time.Minute))}, func(e error) { err = e })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = m.Join(p, "root", ""); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,16 +1,20 @@
 package game
 
 import (
-	"bytes"
+	"cloudcadetest/common/compress"
 	"cloudcadetest/common/encrypt/aes"
 	"cloudcadetest/framework/agent"
 	"cloudcadetest/framework/log"
+	"cloudcadetest/framework/msg/codec"
+	"cloudcadetest/framework/msg/cs"
 	"cloudcadetest/framework/network"
 	"cloudcadetest/pb"
 	"cloudcadetest/serverimpl/chat/conf"
 	"errors"
 	"fmt"
 	"github.com/golang/protobuf/proto"
+	"net"
+	"sync/atomic"
 	"time"
 )
 
@@ -21,13 +25,19 @@ type Agent struct {
 
 	username   string
 	LoginTime  time.Time
-	activeTime time.Time //活跃时间
-	destroyed  bool      //已销毁标志
-	encryptKey aes.Key
-	working    bool //标识连接状态(false 等待客户端发送第一个包 true 收到客户端第一个包后进入工作模式)
+	activeTime time.Time      //活跃时间
+	destroyed  bool           //已销毁标志
+	session    *aes.Session   //握手后建立的会话密钥
+	dec        *codec.Decoder //消息解码, 记录客户端使用的帧格式
+	compressor int32          //登录时协商的压缩算法, 登录前不压缩
+	flood      *floodState    //聊天限流与违规记录, 首次聊天时创建
+	token      string         //断线恢复令牌, 为空则断线后不保留会话
+	outbox     atomic.Value   //*cs.Outbox, 下行序号与重传缓冲, 断线恢复时沿用原会话的
+	kicked     bool           //已下发踢线通知, 等待断开
+	working    bool           //标识连接状态(false 等待客户端发送第一个包 true 收到客户端第一个包后进入工作模式)
 }
 
-func NewPlayer(conn *network.TCPConn) agent.Agent {
+func NewPlayer(conn network.IConn) agent.Agent {
 	if conn == nil {
 		log.Error("conn is nil")
 		return nil
@@ -40,6 +50,10 @@ func NewPlayer(conn *network.TCPConn) agent.Agent {
 		activeTime: now,
 		LoginTime:  now,
 	}
+	p.dec = CSProcessor.NewDecoder(func() *aes.Session { return p.session })
+	if conf.Server.ResendBufferSize > 0 {
+		p.outbox.Store(cs.NewOutbox(conf.Server.ResendBufferSize))
+	}
 
 	SM.RunInSkeleton("gate.new.agent", func() {
 		AddAgentPlayer(p)
@@ -78,8 +92,25 @@ func (p *Agent) updateActiveTS(t time.Time) {
 	p.activeTime = t
 }
 
-func (p *Agent) GetEncKey() *aes.Key {
-	return &p.encryptKey
+func (p *Agent) GetSession() *aes.Session {
+	return p.session
+}
+
+func (p *Agent) GetFrameVersion() codec.FrameVersion {
+	return p.dec.Version()
+}
+
+func (p *Agent) GetCompressor() compress.ID {
+	return compress.ID(atomic.LoadInt32(&p.compressor))
+}
+
+func (p *Agent) SetCompressor(c compress.ID) {
+	atomic.StoreInt32(&p.compressor, int32(c))
+}
+
+func (p *Agent) GetOutbox() *cs.Outbox {
+	ob, _ := p.outbox.Load().(*cs.Outbox)
+	return ob
 }
 
 func (p *Agent) GetUsername() string {
@@ -108,18 +139,63 @@ func (p *Agent) LogError(format string, args ...interface{}) {
 
 func (p *Agent) OnClose(code uint) {
 	SM.RunInSkeleton("gate.p.close", func() {
+		if p.destroyed {
+			return
+		}
 		p.LogRelease("player being destroyed:%d", code)
 		p.Destroy()
 	})
 }
 
+// 下发踢线通知, 写出后再断开连接
+func (p *Agent) Kick(reason pb.KICK_REASON, msg string) {
+	if p.destroyed || p.kicked {
+		return
+	}
+	p.kicked = true
+	// 管理员踢出、刷屏或封禁后不可恢复
+	switch reason {
+	case pb.KICK_REASON_KICK_BY_ADMIN, pb.KICK_REASON_KICK_FLOOD, pb.KICK_REASON_KICK_BANNED:
+		p.token = ""
+	}
+
+	ntf := &pb.CSNtfBody{Kick: &pb.CSNtfKick{
+		Reason: reason,
+		Msg:    msg,
+	}}
+	RoomMgr.AddRoomTask(
+		p.roomID,
+		func() {
+			if e := CSProcessor.WriteMsg(p, pb.CSMsgID_NTF_KICK, ntf); e != nil {
+				p.LogWarn("send kick failed:%s", e.Error())
+			}
+		},
+		func() {
+			if !p.destroyed {
+				p.LogRelease("kicked:%s %s", reason, msg)
+				p.Destroy()
+			}
+		},
+	)
+}
+
 func (p *Agent) Addr() string {
 	return p.conn.RemoteAddr().String()
 }
 
+// 不含端口的远端地址
+func (p *Agent) IP() string {
+	addr := p.Addr()
+	host, _, e := net.SplitHostPort(addr)
+	if e != nil {
+		return addr
+	}
+	return host
+}
+
 func (p *Agent) Destroy() {
-	if e := RoomMgr.Leave(p.fd, p.roomID); e != nil {
-		p.LogWarn("player got no room")
+	if e := RoomMgr.Leave(p.fd); e != nil {
+		p.LogWarn("leave failed:%s", e.Error())
 	}
 	p.conn.Close()
 	p.destroyed = true
@@ -129,7 +205,6 @@ func (p *Agent) Destroy() {
 }
 
 func (p *Agent) readTask() {
-	recvBuffer := new(bytes.Buffer)
 	onceBuffer := make([]byte, 4096)
 	for {
 		msgHandler := func(msgID pb.CSMsgID, bodyBuf []byte) bool {
@@ -139,13 +214,26 @@ func (p *Agent) readTask() {
 				p.LogError("DealMsg %s Unmarshal fail[%s]", msgID, err.Error())
 				return false
 			}
-			p.LogRelease(" ->Recv [%s][%s]", msgID, reqBody)
+			p.LogRelease(" ->Recv [%s][%s]", msgID, redacted(reqBody))
+
+			// 握手在读协程内完成, 后续消息需使用新的会话密钥解密
+			if msgID == pb.CSMsgID_REQ_HANDSHAKE {
+				return p.handshake(reqBody)
+			}
+			if !p.working {
+				if CSProcessor.NeedEncrypt() {
+					p.LogWarn("%s before handshake", msgID)
+					return false
+				}
+				p.working = true
+			}
+
 			SM.RPCServer.Go(msgID, p, msgID, reqBody)
 
 			return true
 		}
 
-		if err := CSProcessor.DealMsgExt(p.conn, p, &p.encryptKey, recvBuffer, onceBuffer, msgHandler); err != nil {
+		if err := CSProcessor.DealMsgExt(p.conn, p, p.dec, onceBuffer, msgHandler); err != nil {
 			p.LogWarn("DealMsg failed[%s]", err.Error())
 			break
 		}
@@ -154,13 +242,28 @@ func (p *Agent) readTask() {
 	p.OnClose(uint(999))
 }
 
-func (p *Agent) update() {
+// 日志中不记录密码、令牌与名字密钥, 只标出是否带了
+func redacted(req *pb.CSReqBody) *pb.CSReqBody {
+	if req.Login == nil {
+		return req
+	}
+	ret := proto.Clone(req).(*pb.CSReqBody)
+	for _, s := range []*string{&ret.Login.Password, &ret.Login.AuthToken, &ret.Login.NameKey, &ret.Login.ResumeToken} {
+		if *s != "" {
+			*s = "***"
+		}
+	}
+	return ret
+}
+
+func (p *Agent) update(now time.Time) {
+	if p.kicked {
+		return
+	}
 	//不活跃踢线
-	nowUnix := time.Now().Unix()
-	if nowUnix-p.activeTime.Unix() > int64(conf.Server.PlayerInteractiveTime) {
-		p.OnClose(1)
+	if now.Sub(p.activeTime) > time.Duration(conf.Server.PlayerInteractiveTime)*time.Second {
 		p.LogWarn("inactive player [%s]", p.Addr())
-		return
+		p.Kick(pb.KICK_REASON_KICK_IDLE, "idle timeout")
 	}
 }
 
@@ -181,7 +284,7 @@ func (p *Agent) SendClient(id pb.CSMsgID, message interface{}, onFinish func(err
 	ret := RoomMgr.AddRoomTask(
 		p.roomID,
 		func() {
-			if e := CSProcessor.WriteMsg(p.conn, id, message, &p.encryptKey); e != nil {
+			if e := CSProcessor.WriteMsg(p, id, message); e != nil {
 				p.LogWarn("send msg:%s failed:%s", id, e.Error())
 			}
 		}, nil,
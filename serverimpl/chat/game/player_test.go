@@ -0,0 +1,24 @@
+package game
+
+import (
+	"cloudcadetest/pb"
+	"strings"
+	"testing"
+)
+
+// 登录请求的凭证与密钥不进日志, 原请求不受影响
+func TestRedacted(t *testing.T) {
+	req := &pb.CSReqBody{Login: &pb.CSReqLogin{Username: "alice", Password: "pw-secret", AuthToken: "tk-secret",
+		NameKey: "key-secret", ResumeToken: "resume-secret"}}
+	s := redacted(req).String()
+	if strings.Contains(s, "secret") || !strings.Contains(s, "alice") {
+		t.Fatalf("logged %s", s)
+	}
+	if req.Login.Password != "pw-secret" || req.Login.ResumeToken != "resume-secret" {
+		t.Fatal("request modified")
+	}
+	chat := &pb.CSReqBody{RoomChat: &pb.CSReqRoomChat{Content: "hi"}}
+	if redacted(chat) != chat {
+		t.Fatal("other requests should be logged as is")
+	}
+}
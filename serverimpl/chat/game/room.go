@@ -1,73 +1,230 @@
 package game
 
 import (
+	"cloudcadetest/common/compress"
+	"cloudcadetest/common/ratelimit"
 	"cloudcadetest/common/word/filter"
 	"cloudcadetest/framework/log"
 	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/conf"
+	"cloudcadetest/serverimpl/chat/history"
+	"cloudcadetest/serverimpl/chat/search"
 	"container/list"
+	"sort"
 	"time"
 )
 
+const (
+	roomCapacity    = 100
+	historyJoinSize = 20  // 加入房间时下发的历史消息条数, 更早的由客户端按需拉取
+	historyPageSize = 50  // 分页拉取的默认条数, 也是断线恢复时补发的上限
+	historyPageMax  = 100 // 分页拉取的上限
+)
+
 type Room struct {
 	*filterSkeleton
-	id          int64
-	node        *list.Element
-	members     map[int64]struct{}
-	historyMsgs *list.List
-	filter      *filter.Filter
+	id      int64
+	name    string
+	node    *list.Element
+	members map[int64]time.Time // fd -> 加入时间, 包括断线等待恢复的成员
+	history history.Store
+	index   *search.Index
+	seq     int64 // 最近一条历史消息的序号
+	lastID  int64 // 最近一条历史消息的ID
+	filter  *filter.Filter
+	limit   *ratelimit.Bucket // 房间聊天限流, 为nil则不限
+
+	// 按用户名记录, 离开房间后仍有效
+	roles  map[string]pb.ROOM_ROLE // 只记录房主与管理员
+	mutes  map[string]time.Time    // 禁言截止时间
+	kicked map[string]time.Time    // 被踢出后在此之前不能重新加入
 }
 
-func NewRoom(id int64) *Room {
+func NewRoom(id int64, store history.Store) *Room {
 	r := &Room{
 		id:             id,
-		historyMsgs:    list.New(),
-		members:        map[int64]struct{}{},
+		history:        store,
+		index:          search.NewIndex(conf.Server.HistoryRetain),
+		limit:          newRoomLimit(),
+		members:        map[int64]time.Time{},
+		roles:          map[string]pb.ROOM_ROLE{},
+		mutes:          map[string]time.Time{},
+		kicked:         map[string]time.Time{},
 		filterSkeleton: NewFS(),
 	}
 	r.filter = filter.New(r)
+	if last, e := store.Before(id, 0, 1); e != nil {
+		log.Error("read history of room %d failed:%s", id, e.Error())
+	} else if len(last) > 0 {
+		r.seq, r.lastID = last[0].Seq, last[0].Id
+	}
+	r.buildIndex()
 
 	return r
 }
 
-func (r *Room) AddMsg(fromUsername, msg string) int {
-	msgCnt := r.historyMsgs.Len()
-	// 超过上限，移除最早的一条消息
-	if msgCnt >= 50 {
-		head := r.historyMsgs.Front()
-		r.historyMsgs.Remove(head)
-		msgCnt -= 1
+// 用已保存的聊天记录建立索引
+func (r *Room) buildIndex() {
+	var after int64
+	for {
+		msgs, e := r.history.Read(r.id, after, 256)
+		if e != nil {
+			log.Error("read history of room %d failed:%s", r.id, e.Error())
+			return
+		}
+		if len(msgs) == 0 {
+			return
+		}
+		for _, hc := range msgs {
+			r.index.Add(hc)
+		}
+		after = msgs[len(msgs)-1].Seq
 	}
+}
 
-	r.historyMsgs.PushBack(&pb.HistoryChat{
+// 写入存储失败时消息仍会广播
+func (r *Room) AddMsg(fromUsername, msg string) *pb.HistoryChat {
+	// 时钟回拨时uuid可能变小, 保证房间内消息ID递增
+	id := UUID.Get()
+	if id <= r.lastID {
+		id = r.lastID + 1
+	}
+	r.seq++
+	r.lastID = id
+
+	hc := &pb.HistoryChat{
 		From:    fromUsername,
 		Content: msg,
-		Dt:      time.Now().String(),
-	})
-	return msgCnt + 1
+		Seq:     r.seq,
+		Id:      id,
+		Time:    time.Now().UnixNano() / int64(time.Millisecond),
+	}
+	if e := r.history.Append(r.id, hc); e != nil {
+		log.Error("append history of room %d failed:%s", r.id, e.Error())
+	}
+	r.index.Add(hc)
+	return hc
+}
+
+// 序号大于seq的历史消息
+func (r *Room) historySince(seq int64) []*pb.HistoryChat {
+	msgs, e := r.history.Read(r.id, seq, historyPageSize)
+	if e != nil {
+		log.Error("read history of room %d failed:%s", r.id, e.Error())
+	}
+	return msgs
 }
 
-func (r *Room) GetHistoryMsgs() *list.List {
-	return r.historyMsgs
+// 按消息ID分页, 多取一条用于判断该方向上是否还有消息
+func (r *Room) historyPage(beforeID, afterID int64, limit int) ([]*pb.HistoryChat, bool, error) {
+	if limit <= 0 {
+		limit = historyPageSize
+	} else if limit > historyPageMax {
+		limit = historyPageMax
+	}
+
+	if beforeID == 0 && afterID > 0 {
+		msgs, e := r.history.After(r.id, afterID, limit+1)
+		if len(msgs) > limit {
+			return msgs[:limit], true, e
+		}
+		return msgs, false, e
+	}
+	msgs, e := r.history.Before(r.id, beforeID, limit+1)
+	if len(msgs) > limit {
+		return msgs[1:], true, e
+	}
+	return msgs, false, e
 }
 
 func (r *Room) Join(playerFD int64) RoomState {
 	l := len(r.members)
-	if l >= 100 {
+	if l >= roomCapacity {
 		return invalid
 	}
 
-	r.members[playerFD] = struct{}{}
-	if l+1 == 100 {
+	r.members[playerFD] = time.Now()
+	if l+1 == roomCapacity {
 		return full
 	}
 	return valid
 }
 
+func (r *Room) IsFull() bool {
+	return len(r.members) >= roomCapacity
+}
+
+func (r *Room) GetName() string {
+	return r.name
+}
+
 func (r *Room) Leave(playerFD int64) {
 	delete(r.members, playerFD)
 }
 
-func (r *Room) notifyRoomChat(playerFD int64, content string) {
+func (r *Room) member(fd int64) *pb.RoomMember {
+	p := RoomMgr.players[fd]
+	if p == nil {
+		return nil
+	}
+	return &pb.RoomMember{
+		Username: p.GetUsername(),
+		JoinTime: r.members[fd].UnixNano() / int64(time.Millisecond),
+		Role:     roleOf(r, p.GetUsername()),
+	}
+}
+
+// 成员列表, 按加入时间排序
+func (r *Room) memberList() []*pb.RoomMember {
+	members := make([]*pb.RoomMember, 0, len(r.members))
+	for fd := range r.members {
+		if m := r.member(fd); m != nil {
+			members = append(members, m)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].JoinTime != members[j].JoinTime {
+			return members[i].JoinTime < members[j].JoinTime
+		}
+		return members[i].Username < members[j].Username
+	})
+	return members
+}
+
+// 新成员收到完整列表, 其他成员收到增量
+func (r *Room) notifyJoined(p *Agent) {
+	r.broadcast(p.GetFD(), pb.CSMsgID_NTF_ROOM_PRESENCE, &pb.CSNtfBody{RoomPresence: &pb.CSNtfRoomPresence{
+		RoomID: r.id,
+		Joined: []*pb.RoomMember{r.member(p.GetFD())},
+	}})
+	p.SendClient(pb.CSMsgID_NTF_ROOM_PRESENCE, &pb.CSNtfBody{RoomPresence: &pb.CSNtfRoomPresence{
+		RoomID:   r.id,
+		Snapshot: true,
+		Joined:   r.memberList(),
+	}}, nil)
+}
+
+// 成员已移出房间后调用, offline表示断线
+func (r *Room) notifyLeft(username string, offline bool) {
+	if offline {
+		r.broadcast(-1, pb.CSMsgID_NTF_ROOM_MEMBER_OFFLINE, &pb.CSNtfBody{RoomMemberOffline: &pb.CSNtfRoomMemberOffline{
+			RoomID:   r.id,
+			Username: username,
+		}})
+	} else {
+		r.broadcast(-1, pb.CSMsgID_NTF_ROOM_MEMBER_LEAVE, &pb.CSNtfBody{RoomMemberLeave: &pb.CSNtfRoomMemberLeave{
+			RoomID:   r.id,
+			Username: username,
+		}})
+	}
+	r.broadcast(-1, pb.CSMsgID_NTF_ROOM_PRESENCE, &pb.CSNtfBody{RoomPresence: &pb.CSNtfRoomPresence{
+		RoomID: r.id,
+		Left:   []string{username},
+	}})
+}
+
+// 不计入历史的消息hc的序号和ID为0
+func (r *Room) notifyRoomChat(playerFD int64, hc *pb.HistoryChat) {
 	username := "N/A"
 	p := RoomMgr.players[playerFD]
 	if p != nil {
@@ -80,36 +237,67 @@ func (r *Room) notifyRoomChat(playerFD int64, content string) {
 		msgID = pb.CSMsgID_NTF_ROOM_CHAT
 		csNtf = &pb.CSNtfBody{RoomChat: &pb.CSNtfRoomChat{
 			Username: username,
-			Content:  content,
+			Content:  hc.Content,
+			Seq:      hc.Seq,
+			MsgID:    hc.Id,
+			Time:     hc.Time,
 		}}
 	)
 
 	r.broadcast(-1, msgID, csNtf)
 }
 
+// 系统公告, 以系统名义发言并计入历史消息
+func (r *Room) notice(content string) {
+	hc := r.AddMsg(systemName, content)
+	r.broadcast(-1, pb.CSMsgID_NTF_ROOM_CHAT, &pb.CSNtfBody{RoomChat: &pb.CSNtfRoomChat{
+		Username: systemName,
+		Content:  content,
+		Seq:      hc.Seq,
+		MsgID:    hc.Id,
+		Time:     hc.Time,
+	}})
+}
+
+// 在主协程中取当前成员, 与聊天消息同在房间任务中按顺序发出
 func (r *Room) broadcast(playerFD int64, msgID pb.CSMsgID, csNtf *pb.CSNtfBody) {
+	members := make([]*Agent, 0, len(r.members))
+	for fd := range r.members {
+		if fd == playerFD {
+			continue
+		}
+		if mem := RoomMgr.players[fd]; mem != nil {
+			members = append(members, mem)
+		}
+	}
+
 	RoomMgr.AddRoomTask(
 		r.id,
 		func() {
-			compressedData, isCompressed, er := CSProcessor.CompressMsg(msgID, csNtf)
+			data, er := CSProcessor.MarshalMsg(msgID, csNtf)
 			if er != nil {
-				log.Error("compress msg failed, id:%s, er:%s", msgID, er.Error())
+				log.Error("marshal msg failed, id:%s, er:%s", msgID, er.Error())
 				return
 			}
 
-			for fd := range r.members {
-				if fd == playerFD {
-					continue
-				}
-				mem := RoomMgr.players[fd]
-				if mem == nil {
-					continue
+			// 同一算法只压缩一次
+			type compressed struct {
+				data  []byte
+				codec compress.ID
+			}
+			cache := map[compress.ID]compressed{}
+
+			for _, mem := range members {
+				z, ok := cache[mem.GetCompressor()]
+				if !ok {
+					z.data, z.codec = CSProcessor.GetCompressData(msgID, mem.GetCompressor(), data)
+					cache[mem.GetCompressor()] = z
 				}
-				er = CSProcessor.Write2Socket(mem.GetConn(), msgID, compressedData, isCompressed, mem.GetEncKey())
+				er = CSProcessor.Write2Socket(mem, msgID, z.data, z.codec)
 				if er != nil {
-					log.Error("broadcast failed, room:%d, msg:%s, p:%d", r.id, msgID, fd)
+					log.Error("broadcast failed, room:%d, msg:%s, p:%d", r.id, msgID, mem.GetFD())
 				} else {
-					log.Release("send %s to client:%s, msg:%s, compressed:%t", msgID, mem.username, csNtf, isCompressed)
+					log.Release("send %s to client:%s, msg:%s, codec:%s", msgID, mem.username, csNtf, compress.Name(z.codec))
 				}
 			}
 		}, nil,
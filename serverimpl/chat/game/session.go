@@ -0,0 +1,165 @@
+package game
+
+import (
+	"cloudcadetest/framework/log"
+	"cloudcadetest/framework/msg/cs"
+	"cloudcadetest/serverimpl/chat/conf"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+const sessionSweepInterval = time.Second
+
+// 断线后保留的会话, 宽限期内可凭令牌恢复原身份、名字与房间座位
+type session struct {
+	fd        int64
+	username  string
+	roomID    int64
+	loginTime time.Time
+	flood     *floodState
+	outbox    *cs.Outbox
+	expireAt  time.Time
+}
+
+func sessionGrace() time.Duration {
+	return time.Duration(conf.Server.SessionGraceTime) * time.Second
+}
+
+func newResumeToken() string {
+	b := make([]byte, 16)
+	if _, e := rand.Read(b); e != nil {
+		log.Error("gen resume token failed:%s", e.Error())
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+func (m *Manager) startSessionSweeper() {
+	if sessionGrace() <= 0 {
+		return
+	}
+	SM.NewTicker("game.session", sessionSweepInterval, func() {
+		m.expireSessions(time.Now())
+	})
+}
+
+// 玩家断线时保留会话, 名字和房间座位在宽限期内不释放
+func (m *Manager) park(p *Agent) bool {
+	if p.token == "" || m.closing || sessionGrace() <= 0 {
+		return false
+	}
+
+	now := time.Now()
+	m.sessions[p.token] = &session{
+		fd:        p.GetFD(),
+		username:  p.GetUsername(),
+		roomID:    p.GetRoomID(),
+		loginTime: p.LoginTime,
+		flood:     p.flood,
+		outbox:    p.GetOutbox(),
+		expireAt:  now.Add(sessionGrace()),
+	}
+	delete(m.players, p.GetFD())
+	delete(m.playersByName, p.GetUsername())
+
+	if r := m.rooms[p.GetRoomID()]; r != nil {
+		r.notifyLeft(p.GetUsername(), true)
+	}
+	p.LogRelease("session parked until %s", now.Add(sessionGrace()).Format(time.RFC3339))
+	return true
+}
+
+// 恢复断线前的会话, 原连接未断开时将其替换; 返回恢复后所在的房间
+// ack之后的下行消息先于恢复后的新消息重发
+func (m *Manager) Resume(p *Agent, username, token string, ack, lastSeq int64) (int64, error) {
+	if m.closing {
+		return -1, errors.New("server is shutting down")
+	}
+	if _, ok := m.players[p.GetFD()]; ok {
+		return -1, errors.New("already logged in")
+	}
+	if e := checkBan(p, username); e != nil {
+		return -1, e
+	}
+	if old := m.playersByName[username]; old != nil && old.token == token {
+		old.LogRelease("replaced by resumed session")
+		old.Destroy()
+	}
+
+	s := m.sessions[token]
+	if s == nil || s.username != username {
+		return -1, errors.New("session expired")
+	}
+	delete(m.sessions, token)
+
+	// 沿用原来的fd作为身份
+	DelAgentPlayer(p.GetFD())
+	p.fd = s.fd
+	AddAgentPlayer(p)
+
+	p.SetUsername(s.username)
+	p.LoginTime = s.loginTime
+	p.flood = s.flood
+	p.token = newResumeToken()
+	if s.outbox != nil {
+		p.outbox.Store(s.outbox)
+		if e := CSProcessor.Resend(p, ack, true); e != nil {
+			p.LogWarn("resend after resume failed:%s", e.Error())
+		}
+	}
+	m.players[p.GetFD()] = p
+	m.playersByName[s.username] = p
+
+	// 房间已关闭时重新选择房间
+	r := m.rooms[s.roomID]
+	if r == nil {
+		return m.rejoin(p)
+	}
+	if _, ok := r.members[p.GetFD()]; !ok {
+		return m.rejoin(p)
+	}
+
+	p.SetRoomID(r.id)
+	m.notifyEntered(p, r)
+	m.notifyHistoryMsgs(p.GetFD(), lastSeq)
+	p.LogRelease("session resumed, last seq:%d", lastSeq)
+	return r.id, nil
+}
+
+func (m *Manager) rejoin(p *Agent) (int64, error) {
+	r, e := m.pickRoom()
+	if e != nil {
+		return -1, e
+	}
+	if e = m.enterRoom(p, r); e != nil {
+		return -1, e
+	}
+	return r.id, nil
+}
+
+// 释放超过宽限期的会话
+func (m *Manager) expireSessions(now time.Time) {
+	for token, s := range m.sessions {
+		if now.Before(s.expireAt) {
+			continue
+		}
+		delete(m.sessions, token)
+		delete(m.names, s.username)
+		m.reserved.touch(s.username, now)
+		if !m.nameOwned(s.username) {
+			m.inbox.drop(s.username)
+		}
+
+		if r := m.rooms[s.roomID]; r != nil {
+			if _, ok := r.members[s.fd]; ok {
+				r.Leave(s.fd)
+				if r.node == nil {
+					m.push(r)
+				}
+			}
+		}
+		log.Release("session of %s[%d] expired", s.username, s.fd)
+	}
+}
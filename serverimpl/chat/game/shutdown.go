@@ -0,0 +1,77 @@
+package game
+
+import (
+	"cloudcadetest/framework/log"
+	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/conf"
+	"context"
+	"time"
+)
+
+// 通知所有玩家并保存房间状态, 然后等待房间任务与连接写队列排空
+func Shutdown(ctx context.Context) {
+	done := make(chan struct{})
+	SM.RunInSkeleton("game.shutdown", func() {
+		RoomMgr.closeAll()
+		close(done)
+	})
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Warn("game shutdown: skeleton busy")
+		return
+	}
+
+	t := time.NewTicker(50 * time.Millisecond)
+	defer t.Stop()
+	for {
+		tasks, writes := RoomMgr.taskPool.Len(), GetAgentPendingWrites()
+		if tasks == 0 && writes == 0 {
+			log.Release("game shutdown: all agents drained")
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Warn("game shutdown: drain timeout, pending tasks:%d writes:%d", tasks, writes)
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (m *Manager) closeAll() {
+	m.closing = true
+
+	for id, r := range m.rooms {
+		r.broadcast(-1, pb.CSMsgID_NTF_ROOM_CLOSED, &pb.CSNtfBody{RoomClosed: &pb.CSNtfRoomClosed{
+			RoomID: id,
+		}})
+	}
+
+	kick := &pb.CSNtfBody{Kick: &pb.CSNtfKick{
+		Reason: pb.KICK_REASON_KICK_SERVER_SHUTDOWN,
+		Msg:    "server is shutting down",
+	}}
+	RangeAgentPlayers(func(ip IPlayer) {
+		if p, ok := ip.(*Agent); ok && !p.IsDestroyed() {
+			p.SendClient(pb.CSMsgID_NTF_KICK, kick, nil)
+		}
+	})
+
+	// 未加载时不能保存, 否则会覆盖旧进程保存的状态
+	if !m.loaded {
+		return
+	}
+	if e := m.saveState(conf.Server.RoomStateFile); e != nil {
+		log.Error("save room state failed:%s", e.Error())
+	}
+	if e := m.inbox.save(conf.Server.InboxFile); e != nil {
+		log.Error("save inbox failed:%s", e.Error())
+	}
+	m.saveReservations()
+	if e := m.history.Close(); e != nil {
+		log.Error("close history store failed:%s", e.Error())
+	}
+}
@@ -0,0 +1,146 @@
+package game
+
+import (
+	"cloudcadetest/framework/log"
+	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/conf"
+	"cloudcadetest/serverimpl/chat/history"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+type roomState struct {
+	ID      int64             `json:"id"`
+	Name    string            `json:"name,omitempty"`
+	History []*pb.HistoryChat `json:"history,omitempty"` // 旧版本保存的历史消息, 现在由history.Store保存
+
+	Roles map[string]pb.ROOM_ROLE `json:"roles,omitempty"`
+	Mutes map[string]time.Time    `json:"mutes,omitempty"`
+}
+
+func (m *Manager) saveState(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	now := time.Now()
+	states := make([]*roomState, 0, len(m.rooms))
+	for id, r := range m.rooms {
+		s := &roomState{ID: id, Name: r.name, Roles: r.roles, Mutes: map[string]time.Time{}}
+		for name := range r.mutes {
+			if until, ok := r.mutedUntil(name, now); ok {
+				s.Mutes[name] = until
+			}
+		}
+		states = append(states, s)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].ID < states[j].ID
+	})
+
+	data, e := json.Marshal(states)
+	if e != nil {
+		return e
+	}
+	return writeFile(path, data)
+}
+
+// 先写临时文件再替换, 避免写一半时崩溃导致状态文件损坏
+func writeFile(path string, data []byte) error {
+	if e := os.MkdirAll(filepath.Dir(path), 0755); e != nil {
+		return e
+	}
+	tmp := path + ".tmp"
+	if e := ioutil.WriteFile(tmp, data, 0644); e != nil {
+		return e
+	}
+	return os.Rename(tmp, path)
+}
+
+func (m *Manager) loadState(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	data, e := ioutil.ReadFile(path)
+	if e != nil {
+		if os.IsNotExist(e) {
+			return nil
+		}
+		return e
+	}
+
+	var states []*roomState
+	if e = json.Unmarshal(data, &states); e != nil {
+		return e
+	}
+
+	for _, s := range states {
+		if _, ok := m.rooms[s.ID]; ok {
+			continue
+		}
+		// 导入旧版本状态文件中的历史消息
+		if m.history.LastSeq(s.ID) == 0 {
+			for _, h := range s.History {
+				legacyHistory(h)
+				if e = m.history.Append(s.ID, h); e != nil {
+					log.Error("import history of room %d failed:%s", s.ID, e.Error())
+					break
+				}
+			}
+		}
+		r := NewRoom(s.ID, m.history)
+		r.name = s.Name
+		for name, role := range s.Roles {
+			r.roles[name] = role
+		}
+		for name, until := range s.Mutes {
+			r.mutes[name] = until
+		}
+		m.push(r)
+		m.rooms[s.ID] = r
+		if s.ID > m.roomIDBase {
+			m.roomIDBase = s.ID
+		}
+	}
+
+	return nil
+}
+
+// 旧记录没有消息ID, 时间为time.Time.String()的格式
+func legacyHistory(h *pb.HistoryChat) {
+	if h.Id == 0 {
+		h.Id = UUID.Get()
+	}
+	if h.Time == 0 && h.Dt != "" {
+		dt := h.Dt
+		if i := strings.Index(dt, " m="); i >= 0 {
+			dt = dt[:i]
+		}
+		if t, e := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", dt); e == nil {
+			h.Time = t.UnixNano() / int64(time.Millisecond)
+		}
+	}
+}
+
+// 打开聊天记录存储, 失败时退回到内存存储
+func openHistory() history.Store {
+	opts := history.Options{Retain: conf.Server.HistoryRetain}
+	sync, e := history.ParseSyncPolicy(conf.Server.HistoryFsync)
+	if e != nil {
+		log.Error("%s, use default", e.Error())
+	}
+	opts.Sync = sync
+
+	store, e := history.Open(conf.Server.HistoryDir, opts)
+	if e != nil {
+		log.Error("open history store %s failed:%s, keep history in memory", conf.Server.HistoryDir, e.Error())
+		return history.NewMemStore(opts.Retain)
+	}
+	return store
+}
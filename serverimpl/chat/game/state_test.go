@@ -0,0 +1,39 @@
+package game
+
+import (
+	"cloudcadetest/serverimpl/chat/conf"
+	"container/list"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 平滑重启时加载前停服不能覆盖旧进程保存的状态, 加载后再处理排队的登录
+func TestLoadAfterHandoff(t *testing.T) {
+	gt := newGMTest(t)
+	dir, _ := ioutil.TempDir("", "state")
+	defer os.RemoveAll(dir)
+	conf.Server.RoomStateFile = filepath.Join(dir, "rooms.json")
+	if e := gt.m.saveState(conf.Server.RoomStateFile); e != nil {
+		t.Fatal(e)
+	}
+
+	m := &Manager{rooms: map[int64]*Room{}, validRooms: list.New(), reserved: newReservations(), inbox: newInbox(0, 0)}
+	var logins []int
+	for i := 0; i < 2; i++ {
+		i := i
+		if e := m.queueLogin(func() { logins = append(logins, len(m.rooms)+i) }); e != nil {
+			t.Fatal(e)
+		}
+	}
+	m.closeAll()
+	m.closing = false
+	m.load()
+	if len(m.rooms) != 1 || m.rooms[1].roles["alice"] == 0 {
+		t.Fatalf("state overwritten before load, rooms %d", len(m.rooms))
+	}
+	if len(logins) != 2 || logins[0] != 1 || logins[1] != 2 || len(m.pendingLogins) != 0 {
+		t.Fatalf("queued logins %v", logins)
+	}
+}
@@ -0,0 +1,108 @@
+package game
+
+import (
+	"cloudcadetest/common/containers/trie"
+	"cloudcadetest/common/word/filter"
+	"cloudcadetest/framework/log"
+	"cloudcadetest/serverimpl/chat/conf"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 按场景处理命中敏感词的内容: 用户名拒绝, 房间聊天替换, 私聊静默丢弃并记录
+
+const (
+	filterName        = "name"
+	filterRoomChat    = "room_chat"
+	filterPrivateChat = "private_chat"
+
+	maxFlagged = 1000
+)
+
+var errRejected = errors.New("content rejected")
+
+var defaultPolicies = map[string]filter.Policy{
+	filterName:        filter.Reject,
+	filterRoomChat:    filter.Mask,
+	filterPrivateChat: filter.Flag,
+}
+
+// 被记录待审核的内容
+type FlaggedMsg struct {
+	Time    time.Time    `json:"time"`
+	Where   string       `json:"where"`
+	From    string       `json:"from"`
+	To      string       `json:"to,omitempty"`
+	RoomID  int64        `json:"room_id,omitempty"`
+	Content string       `json:"content"`
+	Matches []trie.Match `json:"matches"`
+}
+
+// 配置有误的场景使用默认策略
+func loadFilterPolicies() map[string]filter.Policy {
+	policies := make(map[string]filter.Policy, len(defaultPolicies))
+	for where, p := range defaultPolicies {
+		policies[where] = p
+	}
+	for where, s := range conf.Server.FilterPolicies {
+		if _, ok := defaultPolicies[where]; !ok {
+			log.Error("unknown filter context %s", where)
+			continue
+		}
+		p, e := filter.ParsePolicy(s)
+		if e != nil {
+			log.Error("%s for %s, use %s", e.Error(), where, defaultPolicies[where])
+			continue
+		}
+		policies[where] = p
+	}
+	return policies
+}
+
+func (m *Manager) filterPolicy(where string) filter.Policy {
+	if p, ok := m.policies[where]; ok {
+		return p
+	}
+	return defaultPolicies[where]
+}
+
+// 按过滤结果的策略处理: 返回可使用的文本; 拒绝时返回错误; 记录时dropped为true, 内容不应再下发
+func (m *Manager) applyFilter(msg *FlaggedMsg, res *filter.Result) (text string, dropped bool, e error) {
+	if !res.Dirty() {
+		return res.Text, false, nil
+	}
+	filterCounter.With(msg.Where, res.Policy.String()).Inc()
+
+	switch res.Policy {
+	case filter.Reject:
+		return "", true, fmt.Errorf("%w: contains %s", errRejected, strings.Join(res.Words(), ", "))
+	case filter.Flag:
+		msg.Time = time.Now()
+		msg.Content = res.Text
+		msg.Matches = res.Matches
+		m.flag(msg)
+		return "", true, nil
+	}
+	return res.Text, false, nil
+}
+
+// 只保留最近的maxFlagged条
+func (m *Manager) flag(msg *FlaggedMsg) {
+	log.Release("flagged %s from %s: %q", msg.Where, msg.From, msg.Content)
+	if len(m.flagged) >= maxFlagged {
+		copy(m.flagged, m.flagged[1:])
+		m.flagged = m.flagged[:len(m.flagged)-1]
+	}
+	m.flagged = append(m.flagged, msg)
+}
+
+// 最近记录的内容, 新的在前
+func (m *Manager) Flagged() []*FlaggedMsg {
+	ret := make([]*FlaggedMsg, 0, len(m.flagged))
+	for i := len(m.flagged) - 1; i >= 0; i-- {
+		ret = append(ret, m.flagged[i])
+	}
+	return ret
+}
@@ -0,0 +1,96 @@
+package game
+
+import (
+	"cloudcadetest/common/word/filter"
+	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/conf"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFilterPolicies(t *testing.T) {
+	gt := newGMTest(t)
+	m, r := gt.m, gt.r
+
+	dir, _ := ioutil.TempDir("", "policy")
+	defer os.RemoveAll(dir)
+	words := filepath.Join(dir, "list.txt")
+	ioutil.WriteFile(words, []byte("shit\tabuse\n坏蛋\n"), 0644)
+	m.filter = filter.New(testFS(words))
+	r.filter = filter.New(testFS(words))
+
+	conf.Server.FilterPolicies = map[string]string{filterRoomChat: "reject", filterPrivateChat: "oops", "unknown": "mask"}
+	m.policies = loadFilterPolicies()
+	if m.filterPolicy(filterName) != filter.Reject || m.filterPolicy(filterRoomChat) != filter.Reject ||
+		m.filterPolicy(filterPrivateChat) != filter.Flag {
+		t.Fatalf("policies %v", m.policies)
+	}
+
+	lastChat := func() string {
+		msgs, _ := m.history.Before(r.id, 0, 1)
+		if len(msgs) == 0 {
+			return ""
+		}
+		return msgs[0].Content
+	}
+	roomChat := func(policy filter.Policy, content string) {
+		m.policies[filterRoomChat] = policy
+		if e := m.RoomChat(m.playersByName["carol"].fd, r.id, content); e != nil {
+			t.Fatal(e)
+		}
+	}
+	roomChat(filter.Mask, "you shit")
+	if got := lastChat(); got != "you ****" {
+		t.Fatalf("masked %q", got)
+	}
+	roomChat(filter.Reject, "shit again")
+	roomChat(filter.Flag, "坏蛋 shit")
+	if got := lastChat(); got != "you ****" {
+		t.Fatalf("rejected or flagged chat stored %q", got)
+	}
+	if len(m.flagged) != 1 || m.flagged[0].RoomID != r.id || len(m.flagged[0].Matches) != 2 ||
+		m.flagged[0].Matches[1].Category != "abuse" {
+		t.Fatalf("flagged %+v", m.flagged)
+	}
+
+	// 私聊给保留了名字的离线玩家, 送达的进入收件箱
+	conf.Server.NameReserveDays = 30
+	m.reserved.reserve("dave", time.Now())
+	chat := func(policy filter.Policy, content string) (*pb.PrivateMsg, error) {
+		m.policies[filterPrivateChat] = policy
+		var (
+			msg *pb.PrivateMsg
+			err error
+		)
+		if e := m.Chat(m.playersByName["alice"], &pb.CSReqChat{Username: "dave", Content: content}, func(pm *pb.PrivateMsg, stored bool, e error) {
+			msg, err = pm, e
+		}); e != nil {
+			t.Fatal(e)
+		}
+		return msg, err
+	}
+	if _, e := chat(filter.Reject, "shit"); !errors.Is(e, errRejected) || errCode(e) != pb.ERROR_CODE_CONTENT_REJECTED {
+		t.Fatalf("reject:%v", e)
+	}
+	if msg, e := chat(filter.Flag, "shit"); e != nil || msg == nil {
+		t.Fatalf("flag should look delivered:%v", e)
+	}
+	if n := len(m.inbox.unread("dave")); n != 0 {
+		t.Fatalf("flagged msg delivered, inbox %d", n)
+	}
+	if msg, e := chat(filter.Mask, "shit"); e != nil || msg.Content != "****" {
+		t.Fatalf("mask:%v %v", msg, e)
+	}
+	if n := len(m.inbox.unread("dave")); n != 1 {
+		t.Fatalf("inbox %d", n)
+	}
+
+	flagged := m.Flagged()
+	if len(flagged) != 2 || flagged[0].Where != filterPrivateChat || flagged[0].To != "dave" || flagged[1].Where != filterRoomChat {
+		t.Fatalf("flagged %+v", flagged)
+	}
+}
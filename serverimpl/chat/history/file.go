@@ -0,0 +1,348 @@
+package history
+
+import (
+	"cloudcadetest/framework/log"
+	"cloudcadetest/pb"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 本地文件存储, 每个房间一个目录, 目录下为按序号分段的只追加日志
+// 新建段时压缩: 保留的条数足够时删除最早的整段
+type FileStore struct {
+	sync.Mutex
+	dir    string
+	opts   Options
+	rooms  map[int64]*roomLog
+	closed bool
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+type roomLog struct {
+	dir   string
+	segs  []*segment
+	dirty bool // 有未刷盘的写入
+}
+
+func OpenFileStore(dir string, opts Options) (*FileStore, error) {
+	opts.setDefaults()
+	if e := os.MkdirAll(dir, 0755); e != nil {
+		return nil, e
+	}
+
+	s := &FileStore{
+		dir:   dir,
+		opts:  opts,
+		rooms: map[int64]*roomLog{},
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	if opts.Sync == SyncInterval {
+		go s.syncLoop()
+	} else {
+		close(s.done)
+	}
+	return s, nil
+}
+
+func (s *FileStore) syncLoop() {
+	defer close(s.done)
+
+	t := time.NewTicker(s.opts.SyncInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-t.C:
+			if e := s.Sync(); e != nil {
+				log.Error("sync history failed:%s", e.Error())
+			}
+		}
+	}
+}
+
+// 首次访问时打开并修复房间的日志, 调用方需持有锁
+func (s *FileStore) room(roomID int64, create bool) (*roomLog, error) {
+	if s.closed {
+		return nil, errors.New("history store closed")
+	}
+	if rl, ok := s.rooms[roomID]; ok {
+		return rl, nil
+	}
+
+	dir := filepath.Join(s.dir, strconv.FormatInt(roomID, 10))
+	if _, e := os.Stat(dir); os.IsNotExist(e) && !create {
+		return nil, nil
+	}
+	if e := os.MkdirAll(dir, 0755); e != nil {
+		return nil, e
+	}
+	bases, e := listSegments(dir)
+	if e != nil {
+		return nil, e
+	}
+
+	rl := &roomLog{dir: dir}
+	for _, base := range bases {
+		seg, e := openSegment(dir, base)
+		if e != nil {
+			rl.close()
+			return nil, e
+		}
+		rl.segs = append(rl.segs, seg)
+	}
+	s.rooms[roomID] = rl
+	return rl, nil
+}
+
+func (s *FileStore) Append(roomID int64, msg *pb.HistoryChat) error {
+	s.Lock()
+	defer s.Unlock()
+
+	rl, e := s.room(roomID, true)
+	if e != nil {
+		return e
+	}
+	if last, ok := rl.last(); ok && (msg.Seq <= last.seq || msg.Id <= last.id) {
+		return errors.New("seq or id not increasing")
+	}
+
+	seg := rl.active()
+	if seg == nil || seg.size >= s.opts.SegmentSize {
+		if seg, e = rl.roll(msg.Seq); e != nil {
+			return e
+		}
+		rl.compact(s.opts.Retain)
+	}
+	if e = seg.append(msg); e != nil {
+		return e
+	}
+
+	if s.opts.Sync == SyncAlways {
+		return seg.sync()
+	}
+	rl.dirty = true
+	return nil
+}
+
+func (s *FileStore) Read(roomID, afterSeq int64, limit int) ([]*pb.HistoryChat, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	rl, e := s.room(roomID, false)
+	if e != nil || rl == nil {
+		return nil, e
+	}
+	return rl.read(rl.search(func(ent entry) bool { return ent.seq > afterSeq }), limit)
+}
+
+func (s *FileStore) Before(roomID, beforeID int64, limit int) ([]*pb.HistoryChat, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	rl, e := s.room(roomID, false)
+	if e != nil || rl == nil {
+		return nil, e
+	}
+	end := rl.len()
+	if beforeID > 0 {
+		end = rl.search(func(ent entry) bool { return ent.id >= beforeID })
+	}
+	from := end - limit
+	if from < 0 {
+		from = 0
+	}
+	return rl.read(from, end-from)
+}
+
+func (s *FileStore) After(roomID, afterID int64, limit int) ([]*pb.HistoryChat, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	rl, e := s.room(roomID, false)
+	if e != nil || rl == nil {
+		return nil, e
+	}
+	return rl.read(rl.search(func(ent entry) bool { return ent.id > afterID }), limit)
+}
+
+func (s *FileStore) LastSeq(roomID int64) int64 {
+	s.Lock()
+	defer s.Unlock()
+
+	rl, e := s.room(roomID, false)
+	if e != nil {
+		log.Error("open history of room %d failed:%s", roomID, e.Error())
+	}
+	if rl == nil {
+		return 0
+	}
+	last, _ := rl.last()
+	return last.seq
+}
+
+func (s *FileStore) Len(roomID int64) int {
+	s.Lock()
+	defer s.Unlock()
+
+	rl, _ := s.room(roomID, false)
+	if rl == nil {
+		return 0
+	}
+	return rl.len()
+}
+
+func (s *FileStore) Drop(roomID int64) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if rl, ok := s.rooms[roomID]; ok {
+		rl.close()
+		delete(s.rooms, roomID)
+	}
+	return os.RemoveAll(filepath.Join(s.dir, strconv.FormatInt(roomID, 10)))
+}
+
+func (s *FileStore) Sync() error {
+	s.Lock()
+	defer s.Unlock()
+
+	var first error
+	for _, rl := range s.rooms {
+		if !rl.dirty {
+			continue
+		}
+		if seg := rl.active(); seg != nil {
+			if e := seg.sync(); e != nil && first == nil {
+				first = e
+				continue
+			}
+		}
+		rl.dirty = false
+	}
+	return first
+}
+
+func (s *FileStore) Close() error {
+	s.Lock()
+	if s.closed {
+		s.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.Unlock()
+
+	close(s.stop)
+	<-s.done
+	e := s.Sync()
+
+	s.Lock()
+	defer s.Unlock()
+	for _, rl := range s.rooms {
+		rl.close()
+	}
+	s.rooms = map[int64]*roomLog{}
+	return e
+}
+
+func (rl *roomLog) active() *segment {
+	if len(rl.segs) == 0 {
+		return nil
+	}
+	return rl.segs[len(rl.segs)-1]
+}
+
+// 新建段前先刷旧段, 之后只有最后一段会被写入
+func (rl *roomLog) roll(base int64) (*segment, error) {
+	if seg := rl.active(); seg != nil {
+		if e := seg.sync(); e != nil {
+			return nil, e
+		}
+	}
+	seg, e := openSegment(rl.dir, base)
+	if e != nil {
+		return nil, e
+	}
+	rl.segs = append(rl.segs, seg)
+	return seg, nil
+}
+
+// 去掉最早的整段后仍保留至少retain条时删除该段
+func (rl *roomLog) compact(retain int) {
+	for len(rl.segs) > 1 && rl.len()-len(rl.segs[0].entries) >= retain {
+		if e := rl.segs[0].remove(rl.dir); e != nil {
+			log.Error("remove history segment failed:%s", e.Error())
+		}
+		rl.segs[0] = nil
+		rl.segs = rl.segs[1:]
+	}
+}
+
+func (rl *roomLog) last() (entry, bool) {
+	for i := len(rl.segs) - 1; i >= 0; i-- {
+		if ent, ok := rl.segs[i].last(); ok {
+			return ent, true
+		}
+	}
+	return entry{}, false
+}
+
+func (rl *roomLog) len() int {
+	n := 0
+	for _, seg := range rl.segs {
+		n += len(seg.entries)
+	}
+	return n
+}
+
+// 第一条满足f的记录的位置, 位置按所有段连续编号
+// 序号和消息ID都递增, f对记录的结果应为先false后true
+func (rl *roomLog) search(f func(entry) bool) int {
+	pos := 0
+	for _, seg := range rl.segs {
+		entries := seg.entries
+		i := sort.Search(len(entries), func(i int) bool { return f(entries[i]) })
+		if i < len(entries) {
+			return pos + i
+		}
+		pos += len(entries)
+	}
+	return pos
+}
+
+// 从位置from开始读取最多limit条
+func (rl *roomLog) read(from, limit int) ([]*pb.HistoryChat, error) {
+	var msgs []*pb.HistoryChat
+	for _, seg := range rl.segs {
+		if len(msgs) >= limit {
+			break
+		}
+		if from >= len(seg.entries) {
+			from -= len(seg.entries)
+			continue
+		}
+		for ; from < len(seg.entries) && len(msgs) < limit; from++ {
+			msg, e := seg.read(from)
+			if e != nil {
+				return msgs, e
+			}
+			msgs = append(msgs, msg)
+		}
+		from = 0
+	}
+	return msgs, nil
+}
+
+func (rl *roomLog) close() {
+	for _, seg := range rl.segs {
+		seg.close()
+	}
+	rl.segs = nil
+}
@@ -0,0 +1,247 @@
+package history
+
+import (
+	"cloudcadetest/pb"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func testMsg(seq int64) *pb.HistoryChat {
+	return &pb.HistoryChat{
+		From:    "test_" + strconv.FormatInt(seq%7, 10),
+		Content: strings.Repeat("chat ", int(seq%5)+1),
+		Seq:     seq,
+		Id:      seq * 10,
+		Time:    seq * 1000,
+	}
+}
+
+func openTestStore(t *testing.T, dir string, opts Options) *FileStore {
+	s, e := OpenFileStore(dir, opts)
+	if e != nil {
+		t.Fatal(e)
+	}
+	return s
+}
+
+func appendRange(t *testing.T, s Store, roomID, from, to int64) {
+	for seq := from; seq <= to; seq++ {
+		if e := s.Append(roomID, testMsg(seq)); e != nil {
+			t.Fatalf("append %d:%v", seq, e)
+		}
+	}
+}
+
+// 读出的序号应为[from, to]且内容完整
+func checkSeqs(t *testing.T, msgs []*pb.HistoryChat, from, to int64) {
+	if int64(len(msgs)) != to-from+1 {
+		t.Fatalf("got %d msgs, want [%d, %d]", len(msgs), from, to)
+	}
+	for i, m := range msgs {
+		want := testMsg(from + int64(i))
+		if m.Seq != want.Seq || m.Id != want.Id || m.From != want.From || m.Content != want.Content {
+			t.Fatalf("msg %d got %v want %v", i, m, want)
+		}
+	}
+}
+
+func segmentFiles(t *testing.T, dir string, roomID int64, suffix string) []string {
+	files, e := filepath.Glob(filepath.Join(dir, strconv.FormatInt(roomID, 10), "*"+suffix))
+	if e != nil {
+		t.Fatal(e)
+	}
+	return files
+}
+
+func TestFileStore_ReadAndReopen(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "history")
+	defer os.RemoveAll(dir)
+
+	s := openTestStore(t, dir, Options{SegmentSize: 256, Retain: 1000})
+	appendRange(t, s, 1, 1, 100)
+	appendRange(t, s, 2, 1, 3)
+	if e := s.Append(1, testMsg(100)); e == nil {
+		t.Fatal("seq not increasing should be rejected")
+	}
+	if len(segmentFiles(t, dir, 1, logSuffix)) < 2 {
+		t.Fatal("segments should roll")
+	}
+	s.Close()
+
+	s = openTestStore(t, dir, Options{SegmentSize: 256, Retain: 1000})
+	defer s.Close()
+	if s.LastSeq(1) != 100 || s.Len(1) != 100 || s.LastSeq(2) != 3 || s.LastSeq(3) != 0 {
+		t.Fatalf("last seq %d len %d", s.LastSeq(1), s.Len(1))
+	}
+
+	msgs, e := s.Read(1, 42, 20)
+	if e != nil {
+		t.Fatal(e)
+	}
+	checkSeqs(t, msgs, 43, 62)
+	msgs, _ = s.Before(1, 0, 10)
+	checkSeqs(t, msgs, 91, 100)
+	msgs, _ = s.Read(1, 100, 10)
+	checkSeqs(t, msgs, 1, 0)
+
+	appendRange(t, s, 1, 101, 110)
+	msgs, _ = s.Before(1, 0, 15)
+	checkSeqs(t, msgs, 96, 110)
+
+	if e = s.Drop(2); e != nil {
+		t.Fatal(e)
+	}
+	if s.LastSeq(2) != 0 {
+		t.Fatal("dropped room still has history")
+	}
+}
+
+// 保留条数足够时删除最早的整段
+func TestFileStore_Compact(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "history")
+	defer os.RemoveAll(dir)
+
+	s := openTestStore(t, dir, Options{SegmentSize: 200, Retain: 30})
+	defer s.Close()
+	appendRange(t, s, 1, 1, 300)
+
+	n := s.Len(1)
+	if n < 30 || n >= 300 {
+		t.Fatalf("kept %d msgs", n)
+	}
+	msgs, _ := s.Before(1, 0, 30)
+	checkSeqs(t, msgs, 271, 300)
+	msgs, _ = s.Read(1, 0, 1)
+	if len(msgs) != 1 || msgs[0].Seq != 300-int64(n)+1 {
+		t.Fatalf("oldest kept %v, len %d", msgs, n)
+	}
+}
+
+// 模拟写到一半时崩溃: 截断log尾部、追加垃圾数据、索引缺失或落后
+func TestFileStore_CrashRecovery(t *testing.T) {
+	type testCase struct {
+		name    string
+		damage  func(log, idx string, size int64)
+		lastSeq int64
+	}
+	const total = 20
+	cases := []testCase{
+		{"torn_record", func(log, idx string, size int64) {
+			os.Truncate(log, size-3)
+		}, total - 1},
+		{"torn_head", func(log, idx string, size int64) {
+			f, _ := os.OpenFile(log, os.O_WRONLY|os.O_APPEND, 0644)
+			f.Write([]byte{0, 0, 0})
+			f.Close()
+		}, total},
+		{"garbage_tail", func(log, idx string, size int64) {
+			f, _ := os.OpenFile(log, os.O_WRONLY|os.O_APPEND, 0644)
+			f.Write([]byte{0, 0, 0, 9, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+			f.Close()
+		}, total},
+		{"index_lost", func(log, idx string, size int64) {
+			os.Remove(idx)
+		}, total},
+		{"index_behind", func(log, idx string, size int64) {
+			os.Truncate(idx, indexEntrySize*5+7)
+		}, total},
+		{"index_ahead_of_torn_log", func(log, idx string, size int64) {
+			os.Truncate(log, size-20)
+		}, total - 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, _ := ioutil.TempDir("", "history")
+			defer os.RemoveAll(dir)
+
+			s := openTestStore(t, dir, Options{Sync: SyncNone})
+			appendRange(t, s, 1, 1, total)
+			s.Close()
+
+			logs, idxs := segmentFiles(t, dir, 1, logSuffix), segmentFiles(t, dir, 1, idxSuffix)
+			info, e := os.Stat(logs[0])
+			if e != nil {
+				t.Fatal(e)
+			}
+			tc.damage(logs[0], idxs[0], info.Size())
+
+			s = openTestStore(t, dir, Options{Sync: SyncNone})
+			if got := s.LastSeq(1); got != tc.lastSeq {
+				t.Fatalf("recovered last seq %d want %d", got, tc.lastSeq)
+			}
+			msgs, e := s.Read(1, 0, total)
+			if e != nil {
+				t.Fatal(e)
+			}
+			checkSeqs(t, msgs, 1, tc.lastSeq)
+
+			// 修复后可继续追加, 重新打开后数据一致
+			appendRange(t, s, 1, tc.lastSeq+1, total+5)
+			s.Close()
+			s = openTestStore(t, dir, Options{Sync: SyncNone})
+			defer s.Close()
+			msgs, _ = s.Read(1, 0, total+5)
+			checkSeqs(t, msgs, 1, total+5)
+		})
+	}
+}
+
+func TestMemStore(t *testing.T) {
+	s := NewMemStore(10)
+	appendRange(t, s, 1, 1, 25)
+	if s.Len(1) != 10 || s.LastSeq(1) != 25 {
+		t.Fatalf("len %d last %d", s.Len(1), s.LastSeq(1))
+	}
+	msgs, _ := s.Read(1, 0, 3)
+	checkSeqs(t, msgs, 16, 18)
+	msgs, _ = s.Before(1, 0, 4)
+	checkSeqs(t, msgs, 22, 25)
+	msgs, _ = s.Before(1, 200, 3)
+	checkSeqs(t, msgs, 17, 19)
+	msgs, _ = s.After(1, 235, 10)
+	checkSeqs(t, msgs, 24, 25)
+}
+
+// 按消息ID向前、向后翻页, 页可跨越多个段
+func TestFileStore_Page(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "history")
+	defer os.RemoveAll(dir)
+
+	s := openTestStore(t, dir, Options{SegmentSize: 128})
+	defer s.Close()
+	appendRange(t, s, 1, 1, 50)
+	if e := s.Append(1, &pb.HistoryChat{Seq: 51, Id: 500}); e == nil {
+		t.Fatal("id not increasing should be rejected")
+	}
+
+	var before int64
+	for to := int64(50); to > 0; to -= 8 {
+		msgs, e := s.Before(1, before, 8)
+		if e != nil {
+			t.Fatal(e)
+		}
+		from := to - 7
+		if from < 1 {
+			from = 1
+		}
+		checkSeqs(t, msgs, from, to)
+		before = msgs[0].Id
+	}
+	if msgs, _ := s.Before(1, before, 8); len(msgs) != 0 {
+		t.Fatalf("got %d msgs before the oldest", len(msgs))
+	}
+
+	msgs, _ := s.After(1, 0, 8)
+	checkSeqs(t, msgs, 1, 8)
+	msgs, _ = s.After(1, 205, 8) // 不存在的ID从下一条开始
+	checkSeqs(t, msgs, 21, 28)
+	msgs, _ = s.Before(1, 205, 3)
+	checkSeqs(t, msgs, 18, 20)
+	msgs, _ = s.After(1, 500, 8)
+	checkSeqs(t, msgs, 1, 0)
+}
@@ -0,0 +1,82 @@
+package history
+
+import (
+	"cloudcadetest/pb"
+	"fmt"
+	"time"
+)
+
+// 房间聊天记录的存储, 序号和消息ID由房间分配且都单调递增
+// 实现需可在多个协程中使用, 读出的消息均按序号升序
+type Store interface {
+	Append(roomID int64, msg *pb.HistoryChat) error
+	// 序号大于afterSeq的最多limit条
+	Read(roomID, afterSeq int64, limit int) ([]*pb.HistoryChat, error)
+	// 消息ID小于beforeID的最近limit条, beforeID为0时取最新的limit条
+	Before(roomID, beforeID int64, limit int) ([]*pb.HistoryChat, error)
+	// 消息ID大于afterID的最多limit条
+	After(roomID, afterID int64, limit int) ([]*pb.HistoryChat, error)
+	// 最后一条的序号, 没有记录时为0
+	LastSeq(roomID int64) int64
+	// 当前保存的条数
+	Len(roomID int64) int
+	// 删除房间的全部记录
+	Drop(roomID int64) error
+	Sync() error
+	Close() error
+}
+
+// 刷盘策略
+type SyncPolicy int
+
+const (
+	SyncInterval SyncPolicy = iota // 定时刷盘, 崩溃时可能丢失最近一个周期的消息
+	SyncAlways                     // 每条消息写入后刷盘
+	SyncNone                       // 交给操作系统
+)
+
+func ParseSyncPolicy(s string) (SyncPolicy, error) {
+	switch s {
+	case "", "interval":
+		return SyncInterval, nil
+	case "always":
+		return SyncAlways, nil
+	case "none":
+		return SyncNone, nil
+	}
+	return 0, fmt.Errorf("unknown sync policy %s", s)
+}
+
+const (
+	defaultRetain       = 1000
+	defaultSegmentSize  = 4 << 20
+	defaultSyncInterval = time.Second
+)
+
+type Options struct {
+	Retain       int   // 每个房间至少保留的条数, 更早的记录压缩掉
+	SegmentSize  int64 // 单个段文件的大小上限, 写满后新建
+	Sync         SyncPolicy
+	SyncInterval time.Duration
+}
+
+func (o *Options) setDefaults() {
+	if o.Retain <= 0 {
+		o.Retain = defaultRetain
+	}
+	if o.SegmentSize <= 0 {
+		o.SegmentSize = defaultSegmentSize
+	}
+	if o.SyncInterval <= 0 {
+		o.SyncInterval = defaultSyncInterval
+	}
+}
+
+// dir为空时只保存在内存中
+func Open(dir string, opts Options) (Store, error) {
+	opts.setDefaults()
+	if dir == "" {
+		return NewMemStore(opts.Retain), nil
+	}
+	return OpenFileStore(dir, opts)
+}
@@ -0,0 +1,118 @@
+package history
+
+import (
+	"cloudcadetest/pb"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// 只保存在内存中, 每个房间保留最近retain条
+type MemStore struct {
+	sync.Mutex
+	retain int
+	rooms  map[int64][]*pb.HistoryChat
+}
+
+func NewMemStore(retain int) *MemStore {
+	if retain <= 0 {
+		retain = defaultRetain
+	}
+	return &MemStore{
+		retain: retain,
+		rooms:  map[int64][]*pb.HistoryChat{},
+	}
+}
+
+func (s *MemStore) Append(roomID int64, msg *pb.HistoryChat) error {
+	s.Lock()
+	defer s.Unlock()
+
+	msgs := s.rooms[roomID]
+	if n := len(msgs); n > 0 && (msg.Seq <= msgs[n-1].Seq || msg.Id <= msgs[n-1].Id) {
+		return errors.New("seq or id not increasing")
+	}
+	if len(msgs) >= s.retain {
+		msgs[0] = nil
+		msgs = msgs[1:]
+	}
+	s.rooms[roomID] = append(msgs, msg)
+	return nil
+}
+
+func (s *MemStore) Read(roomID, afterSeq int64, limit int) ([]*pb.HistoryChat, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	msgs := s.rooms[roomID]
+	i := sort.Search(len(msgs), func(i int) bool { return msgs[i].Seq > afterSeq })
+	return window(msgs, i, limit), nil
+}
+
+func (s *MemStore) Before(roomID, beforeID int64, limit int) ([]*pb.HistoryChat, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	msgs := s.rooms[roomID]
+	end := len(msgs)
+	if beforeID > 0 {
+		end = sort.Search(len(msgs), func(i int) bool { return msgs[i].Id >= beforeID })
+	}
+	i := end - limit
+	if i < 0 {
+		i = 0
+	}
+	return window(msgs[:end], i, limit), nil
+}
+
+func (s *MemStore) After(roomID, afterID int64, limit int) ([]*pb.HistoryChat, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	msgs := s.rooms[roomID]
+	i := sort.Search(len(msgs), func(i int) bool { return msgs[i].Id > afterID })
+	return window(msgs, i, limit), nil
+}
+
+func (s *MemStore) LastSeq(roomID int64) int64 {
+	s.Lock()
+	defer s.Unlock()
+
+	if msgs := s.rooms[roomID]; len(msgs) > 0 {
+		return msgs[len(msgs)-1].Seq
+	}
+	return 0
+}
+
+func (s *MemStore) Len(roomID int64) int {
+	s.Lock()
+	defer s.Unlock()
+	return len(s.rooms[roomID])
+}
+
+func (s *MemStore) Drop(roomID int64) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.rooms, roomID)
+	return nil
+}
+
+func (s *MemStore) Sync() error {
+	return nil
+}
+
+func (s *MemStore) Close() error {
+	return nil
+}
+
+// 从i开始最多limit条的副本
+func window(msgs []*pb.HistoryChat, i, limit int) []*pb.HistoryChat {
+	end := len(msgs)
+	if i+limit < end {
+		end = i + limit
+	}
+	if i >= end {
+		return nil
+	}
+	return append([]*pb.HistoryChat(nil), msgs[i:end]...)
+}
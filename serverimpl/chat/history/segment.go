@@ -0,0 +1,280 @@
+package history
+
+import (
+	"bytes"
+	"cloudcadetest/pb"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 段文件格式
+// log: | len(4) | crc32c(4) | HistoryChat | ..., 只在末尾追加
+// idx: | seq(8) | id(8) | offset(8) | ..., 每条记录一项, 写在log之后
+// 文件名为段内第一条记录的序号
+// 崩溃后log末尾可能有写了一半的记录, idx可能落后于log或有半项, 打开时修复
+
+const (
+	recordHeadSize = 8
+	indexEntrySize = 24
+	maxRecordSize  = 1 << 20
+
+	logSuffix = ".log"
+	idxSuffix = ".idx"
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+var errCorrupt = errors.New("corrupt record")
+
+type entry struct {
+	seq int64
+	id  int64
+	off int64
+}
+
+type segment struct {
+	base    int64
+	log     *os.File
+	idx     *os.File
+	entries []entry
+	size    int64 // log中有效数据的长度
+}
+
+func segmentName(dir string, base int64, suffix string) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", base, suffix))
+}
+
+// 目录中已有的段, 按序号升序
+func listSegments(dir string) ([]int64, error) {
+	names, e := filepath.Glob(filepath.Join(dir, "*"+logSuffix))
+	if e != nil {
+		return nil, e
+	}
+
+	bases := make([]int64, 0, len(names))
+	for _, name := range names {
+		base, e := strconv.ParseInt(strings.TrimSuffix(filepath.Base(name), logSuffix), 10, 64)
+		if e != nil {
+			continue
+		}
+		bases = append(bases, base)
+	}
+	sort.Slice(bases, func(i, j int) bool { return bases[i] < bases[j] })
+	return bases, nil
+}
+
+// 打开段并修复崩溃造成的不完整数据
+func openSegment(dir string, base int64) (*segment, error) {
+	log, e := os.OpenFile(segmentName(dir, base, logSuffix), os.O_RDWR|os.O_CREATE, 0644)
+	if e != nil {
+		return nil, e
+	}
+	idx, e := os.OpenFile(segmentName(dir, base, idxSuffix), os.O_RDWR|os.O_CREATE, 0644)
+	if e != nil {
+		log.Close()
+		return nil, e
+	}
+
+	s := &segment{base: base, log: log, idx: idx}
+	if e = s.recover(); e != nil {
+		s.close()
+		return nil, e
+	}
+	return s, nil
+}
+
+func (s *segment) recover() error {
+	logInfo, e := s.log.Stat()
+	if e != nil {
+		return e
+	}
+	logSize := logInfo.Size()
+
+	// 先信任索引, 从最后一项往前找到完整的记录
+	data, e := readAll(s.idx)
+	if e != nil {
+		return e
+	}
+	for i := 0; i+indexEntrySize <= len(data); i += indexEntrySize {
+		ent := entry{
+			seq: int64(binary.BigEndian.Uint64(data[i:])),
+			id:  int64(binary.BigEndian.Uint64(data[i+8:])),
+			off: int64(binary.BigEndian.Uint64(data[i+16:])),
+		}
+		if n := len(s.entries); n > 0 && !s.entries[n-1].before(ent) {
+			break
+		}
+		if ent.off >= logSize {
+			break
+		}
+		s.entries = append(s.entries, ent)
+	}
+	for len(s.entries) > 0 {
+		last := s.entries[len(s.entries)-1]
+		msg, n, e := readRecord(s.log, last.off)
+		if e == nil && msg.Seq == last.seq && msg.Id == last.id {
+			s.size = last.off + n
+			break
+		}
+		s.entries = s.entries[:len(s.entries)-1]
+	}
+
+	// 再顺序扫描索引之后的记录
+	for s.size < logSize {
+		msg, n, e := readRecord(s.log, s.size)
+		if e != nil {
+			break
+		}
+		ent := entry{seq: msg.Seq, id: msg.Id, off: s.size}
+		if l := len(s.entries); l > 0 && !s.entries[l-1].before(ent) {
+			break
+		}
+		s.entries = append(s.entries, ent)
+		s.size += n
+	}
+
+	// 丢弃不完整的尾部并重写索引
+	if s.size < logSize {
+		if e = s.log.Truncate(s.size); e != nil {
+			return e
+		}
+	}
+	buf := make([]byte, 0, len(s.entries)*indexEntrySize)
+	for _, ent := range s.entries {
+		buf = appendEntry(buf, ent)
+	}
+	if !bytes.Equal(data, buf) {
+		if e = s.idx.Truncate(0); e != nil {
+			return e
+		}
+		if _, e = s.idx.WriteAt(buf, 0); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func (s *segment) append(msg *pb.HistoryChat) error {
+	payload, e := proto.Marshal(msg)
+	if e != nil {
+		return e
+	}
+	if len(payload) > maxRecordSize {
+		return fmt.Errorf("record too large %d", len(payload))
+	}
+
+	rec := make([]byte, recordHeadSize, recordHeadSize+len(payload))
+	binary.BigEndian.PutUint32(rec, uint32(len(payload)))
+	binary.BigEndian.PutUint32(rec[4:], crc32.Checksum(payload, crcTable))
+	rec = append(rec, payload...)
+
+	if _, e = s.log.WriteAt(rec, s.size); e != nil {
+		// 写了一半的记录留给下次打开时修复, 这里回退到写之前的长度
+		s.log.Truncate(s.size)
+		return e
+	}
+	ent := entry{seq: msg.Seq, id: msg.Id, off: s.size}
+	if _, e = s.idx.WriteAt(appendEntry(nil, ent), int64(len(s.entries))*indexEntrySize); e != nil {
+		return e
+	}
+	s.entries = append(s.entries, ent)
+	s.size += int64(len(rec))
+	return nil
+}
+
+func (s *segment) read(i int) (*pb.HistoryChat, error) {
+	msg, _, e := readRecord(s.log, s.entries[i].off)
+	return msg, e
+}
+
+func (s *segment) last() (entry, bool) {
+	if len(s.entries) == 0 {
+		return entry{}, false
+	}
+	return s.entries[len(s.entries)-1], true
+}
+
+// 序号、消息ID和偏移都应递增
+func (ent entry) before(next entry) bool {
+	return ent.seq < next.seq && ent.id < next.id && ent.off < next.off
+}
+
+// 先刷log再刷idx, idx落后时可由log恢复
+func (s *segment) sync() error {
+	if e := s.log.Sync(); e != nil {
+		return e
+	}
+	return s.idx.Sync()
+}
+
+func (s *segment) close() error {
+	e1 := s.log.Close()
+	e2 := s.idx.Close()
+	if e1 != nil {
+		return e1
+	}
+	return e2
+}
+
+func (s *segment) remove(dir string) error {
+	s.close()
+	if e := os.Remove(segmentName(dir, s.base, logSuffix)); e != nil {
+		return e
+	}
+	return os.Remove(segmentName(dir, s.base, idxSuffix))
+}
+
+// 读取off处的记录, 返回记录及其占用的长度
+func readRecord(r io.ReaderAt, off int64) (*pb.HistoryChat, int64, error) {
+	var head [recordHeadSize]byte
+	if _, e := r.ReadAt(head[:], off); e != nil {
+		return nil, 0, errCorrupt
+	}
+	l := binary.BigEndian.Uint32(head[:])
+	if l > maxRecordSize {
+		return nil, 0, errCorrupt
+	}
+
+	payload := make([]byte, l)
+	if _, e := r.ReadAt(payload, off+recordHeadSize); e != nil {
+		return nil, 0, errCorrupt
+	}
+	if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(head[4:]) {
+		return nil, 0, errCorrupt
+	}
+
+	msg := &pb.HistoryChat{}
+	if e := proto.Unmarshal(payload, msg); e != nil || msg.Seq <= 0 {
+		return nil, 0, errCorrupt
+	}
+	return msg, recordHeadSize + int64(l), nil
+}
+
+func appendEntry(buf []byte, ent entry) []byte {
+	var b [indexEntrySize]byte
+	binary.BigEndian.PutUint64(b[:], uint64(ent.seq))
+	binary.BigEndian.PutUint64(b[8:], uint64(ent.id))
+	binary.BigEndian.PutUint64(b[16:], uint64(ent.off))
+	return append(buf, b[:]...)
+}
+
+func readAll(f *os.File) ([]byte, error) {
+	info, e := f.Stat()
+	if e != nil {
+		return nil, e
+	}
+	data := make([]byte, info.Size())
+	if _, e = f.ReadAt(data, 0); e != nil && e != io.EOF {
+		return nil, e
+	}
+	return data, nil
+}
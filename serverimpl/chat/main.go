@@ -6,6 +6,7 @@ import (
 	"cloudcadetest/modconf"
 	"cloudcadetest/serverimpl/chat/conf"
 	"cloudcadetest/serverimpl/chat/game"
+	"cloudcadetest/serverimpl/chat/modules/admin"
 	"cloudcadetest/serverimpl/chat/modules/playergate"
 	"cloudcadetest/serverimpl/chat/modules/self"
 	"fmt"
@@ -34,11 +35,16 @@ func main() {
 		LogChanNum:   100000,
 		RollSize:     200,
 		EnableStdOut: false,
+		DrainTimeout: conf.Server.DrainTimeout,
 	})
 
+	gate := playergate.New(game.NewPlayer)
+	gate.FuncAllowIP = game.AllowIP
+
 	s.Run([]module.IModule{
-		playergate.New(game.NewPlayer),
+		gate,
 		self.Mod,
+		admin.New(),
 	})
 }
 
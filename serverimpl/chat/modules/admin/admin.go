@@ -0,0 +1,84 @@
+package admin
+
+import (
+	"cloudcadetest/framework/log"
+	"cloudcadetest/framework/metrics"
+	"cloudcadetest/framework/network"
+	"cloudcadetest/serverimpl/chat/conf"
+	"net"
+	"net/http"
+	"time"
+)
+
+// 写超时需大于接口在主协程中的等待时间apiTimeout
+const (
+	httpTimeout     = 10 * time.Second
+	httpIdleTimeout = 60 * time.Second
+)
+
+// 管理端口, 提供Prometheus格式的指标与运维接口
+type Admin struct {
+	Addr   string
+	Token  string // 运维接口的令牌, 为空则不开放
+	mux    *http.ServeMux
+	ln     net.Listener
+	server *http.Server
+}
+
+func New() *Admin {
+	a := &Admin{
+		Addr:  conf.Server.AdminAddr,
+		Token: conf.Server.AdminToken,
+		mux:   http.NewServeMux(),
+	}
+	a.mux.Handle("/metrics", metrics.Handler())
+	if a.Token != "" {
+		a.registerAPI()
+	}
+	return a
+}
+
+func (a *Admin) Run(closeSig chan bool) {
+	if a.Addr != "" {
+		ln, err := network.Listen(a.Addr)
+		if err != nil {
+			log.Error("admin listen[%s] failed:%s", a.Addr, err.Error())
+		} else {
+			log.Release("listen admin http[%v], api enabled:%t", a.Addr, a.Token != "")
+			a.ln = ln
+			a.server = &http.Server{
+				Handler:      a.mux,
+				ReadTimeout:  httpTimeout,
+				WriteTimeout: httpTimeout,
+				IdleTimeout:  httpIdleTimeout,
+			}
+			go a.serve()
+		}
+	}
+
+	<-closeSig
+}
+
+func (a *Admin) serve() {
+	if e := a.server.Serve(a.ln); e != nil && e != http.ErrServerClosed {
+		log.Error("admin http serve failed:%s", e.Error())
+	}
+}
+
+func (a *Admin) OnInit() {
+
+}
+
+// 停服排空期间仍可查看指标, 最后再关闭
+func (a *Admin) OnDestroy() {
+	if a.server == nil {
+		return
+	}
+	if e := network.CloseListener(a.Addr, a.ln); e != nil {
+		log.Error("close admin listener failed:%s", e.Error())
+	}
+	if e := a.server.Close(); e != nil {
+		log.Error("close admin http failed:%s", e.Error())
+	}
+	log.Release("admin destroyed")
+}
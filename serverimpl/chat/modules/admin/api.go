@@ -0,0 +1,358 @@
+package admin
+
+import (
+	"cloudcadetest/framework/log"
+	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/game"
+	"cloudcadetest/serverimpl/chat/search"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 需要鉴权的运维接口, 修改类操作都切到主协程中执行
+
+const (
+	apiTimeout  = 3 * time.Second
+	maxBodySize = 64 << 10
+)
+
+type statusError struct {
+	code int
+	err  error
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func badRequest(err error) error {
+	return &statusError{code: http.StatusBadRequest, err: err}
+}
+
+func notFound(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &statusError{code: http.StatusNotFound, err: err}
+}
+
+type apiFunc func(r *http.Request) (interface{}, error)
+
+func (a *Admin) registerAPI() {
+	a.handle("/api/rooms", http.MethodGet, a.listRooms)
+	a.handle("/api/search", http.MethodGet, a.search)
+	a.handle("/api/kick", http.MethodPost, a.kick)
+	a.handle("/api/mute", http.MethodPost, a.mute)
+	a.handle("/api/bans", http.MethodGet, a.listBans)
+	a.handle("/api/ban", http.MethodPost, a.ban)
+	a.handle("/api/unban", http.MethodPost, a.unban)
+	a.handle("/api/account", http.MethodPost, a.setAccount)
+	a.handle("/api/notice", http.MethodPost, a.notice)
+	a.handle("/api/room/close", http.MethodPost, a.closeRoom)
+	a.handle("/api/loglevel", http.MethodPost, a.setLogLevel)
+	a.handle("/api/words/reload", http.MethodPost, a.reloadWords)
+	a.handle("/api/flagged", http.MethodGet, a.listFlagged)
+}
+
+func (a *Admin) handle(path, method string, f apiFunc) {
+	a.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		if !a.authorized(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			return
+		}
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+
+		if method != http.MethodGet {
+			log.Release("admin %s %s from %s", method, path, r.RemoteAddr)
+		}
+
+		ret, err := f(r)
+		if err != nil {
+			code := http.StatusInternalServerError
+			var se *statusError
+			if errors.As(err, &se) {
+				code = se.code
+			} else if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, game.ErrLoading) {
+				code = http.StatusServiceUnavailable
+			}
+			writeJSON(w, code, map[string]string{"error": err.Error()})
+			return
+		}
+		if ret == nil {
+			ret = map[string]bool{"ok": true}
+		}
+		writeJSON(w, http.StatusOK, ret)
+	})
+}
+
+func (a *Admin) authorized(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+	token := strings.TrimPrefix(auth, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(a.Token)) == 1
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if e := json.NewEncoder(w).Encode(v); e != nil {
+		log.Warn("admin write response failed:%s", e.Error())
+	}
+}
+
+func decode(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if e := dec.Decode(v); e != nil {
+		return badRequest(e)
+	}
+	return nil
+}
+
+// 在主协程中执行f, 超时则返回错误
+func runInSkeleton(r *http.Request, name string, f func() error) error {
+	ctx, cancel := context.WithTimeout(r.Context(), apiTimeout)
+	defer cancel()
+
+	var err error
+	if e := game.RunSync(ctx, name, func() { err = f() }); e != nil {
+		return e
+	}
+	return err
+}
+
+func (a *Admin) listRooms(r *http.Request) (interface{}, error) {
+	var rooms []game.RoomView
+	err := runInSkeleton(r, "admin.rooms", func() error {
+		rooms = game.RoomMgr.Rooms()
+		return nil
+	})
+	return rooms, err
+}
+
+type searchRsp struct {
+	Results []*pb.HistoryChat `json:"results"`
+	More    bool              `json:"more"`
+}
+
+// GET /api/search?room_id=1&q=...&from=...&before=...&limit=...
+func (a *Admin) search(r *http.Request) (interface{}, error) {
+	args := r.URL.Query()
+	roomID, e := strconv.ParseInt(args.Get("room_id"), 10, 64)
+	if e != nil {
+		return nil, badRequest(fmt.Errorf("invalid room_id %q", args.Get("room_id")))
+	}
+	q := &search.Query{Text: args.Get("q"), From: args.Get("from")}
+	if s := args.Get("before"); s != "" {
+		if q.BeforeID, e = strconv.ParseInt(s, 10, 64); e != nil {
+			return nil, badRequest(fmt.Errorf("invalid before %q", s))
+		}
+	}
+	if s := args.Get("limit"); s != "" {
+		if q.Limit, e = strconv.Atoi(s); e != nil {
+			return nil, badRequest(fmt.Errorf("invalid limit %q", s))
+		}
+	}
+
+	var rsp searchRsp
+	err := runInSkeleton(r, "admin.search", func() error {
+		var e error
+		rsp.Results, rsp.More, e = game.RoomMgr.Search(roomID, q)
+		if e == search.ErrEmptyQuery {
+			return badRequest(e)
+		}
+		return notFound(e)
+	})
+	return rsp, err
+}
+
+type kickReq struct {
+	Name string `json:"name"`
+	Msg  string `json:"msg"`
+}
+
+func (a *Admin) kick(r *http.Request) (interface{}, error) {
+	var req kickReq
+	if e := decode(r, &req); e != nil {
+		return nil, e
+	}
+	if req.Name == "" {
+		return nil, badRequest(errors.New("name is required"))
+	}
+	if req.Msg == "" {
+		req.Msg = "kicked by admin"
+	}
+	return nil, runInSkeleton(r, "admin.kick", func() error {
+		return notFound(game.RoomMgr.Kick(req.Name, req.Msg))
+	})
+}
+
+type muteReq struct {
+	Name    string `json:"name"`
+	Seconds int64  `json:"seconds"` // 0表示解除禁言
+}
+
+func (a *Admin) mute(r *http.Request) (interface{}, error) {
+	var req muteReq
+	if e := decode(r, &req); e != nil {
+		return nil, e
+	}
+	if req.Name == "" {
+		return nil, badRequest(errors.New("name is required"))
+	}
+	if req.Seconds < 0 {
+		return nil, badRequest(fmt.Errorf("invalid seconds %d", req.Seconds))
+	}
+	return nil, runInSkeleton(r, "admin.mute", func() error {
+		return notFound(game.RoomMgr.Mute(req.Name, time.Duration(req.Seconds)*time.Second))
+	})
+}
+
+func (a *Admin) listBans(r *http.Request) (interface{}, error) {
+	var views []game.BanView
+	err := runInSkeleton(r, "admin.bans", func() error {
+		views = game.RoomMgr.Bans()
+		return nil
+	})
+	return views, err
+}
+
+func (a *Admin) listFlagged(r *http.Request) (interface{}, error) {
+	var msgs []*game.FlaggedMsg
+	err := runInSkeleton(r, "admin.flagged", func() error {
+		msgs = game.RoomMgr.Flagged()
+		return nil
+	})
+	return msgs, err
+}
+
+type banReq struct {
+	Name    string `json:"name"`
+	IP      string `json:"ip"`
+	Seconds int64  `json:"seconds"` // 0表示永久
+	Reason  string `json:"reason"`
+}
+
+func (a *Admin) ban(r *http.Request) (interface{}, error) {
+	var req banReq
+	if e := decode(r, &req); e != nil {
+		return nil, e
+	}
+	if req.Name == "" && req.IP == "" {
+		return nil, badRequest(errors.New("name or ip is required"))
+	}
+	if req.Seconds < 0 {
+		return nil, badRequest(fmt.Errorf("invalid seconds %d", req.Seconds))
+	}
+	return nil, runInSkeleton(r, "admin.ban", func() error {
+		if e := game.RoomMgr.Ban(req.Name, req.IP, time.Duration(req.Seconds)*time.Second, req.Reason, "admin"); e != nil {
+			return badRequest(e)
+		}
+		return nil
+	})
+}
+
+type unbanReq struct {
+	Name string `json:"name"`
+	IP   string `json:"ip"`
+}
+
+func (a *Admin) unban(r *http.Request) (interface{}, error) {
+	var req unbanReq
+	if e := decode(r, &req); e != nil {
+		return nil, e
+	}
+	return nil, runInSkeleton(r, "admin.unban", func() error {
+		return notFound(game.RoomMgr.Unban(req.Name, req.IP))
+	})
+}
+
+type accountReq struct {
+	Name     string `json:"name"`
+	Password string `json:"password"` // 为空时删除账号
+}
+
+// 账号文件可在多个协程中使用, 密码哈希较慢, 不切到主协程
+func (a *Admin) setAccount(r *http.Request) (interface{}, error) {
+	var req accountReq
+	if e := decode(r, &req); e != nil {
+		return nil, e
+	}
+	if req.Name == "" {
+		return nil, badRequest(errors.New("name is required"))
+	}
+	if e := game.RoomMgr.SetPassword(req.Name, req.Password); e != nil {
+		return nil, badRequest(e)
+	}
+	return nil, nil
+}
+
+type noticeReq struct {
+	RoomID  int64  `json:"room_id"` // 0表示所有房间
+	Content string `json:"content"`
+}
+
+func (a *Admin) notice(r *http.Request) (interface{}, error) {
+	var req noticeReq
+	if e := decode(r, &req); e != nil {
+		return nil, e
+	}
+	if req.Content == "" {
+		return nil, badRequest(errors.New("content is required"))
+	}
+	return nil, runInSkeleton(r, "admin.notice", func() error {
+		return notFound(game.RoomMgr.Notice(req.RoomID, req.Content))
+	})
+}
+
+type closeRoomReq struct {
+	RoomID int64 `json:"room_id"`
+}
+
+func (a *Admin) closeRoom(r *http.Request) (interface{}, error) {
+	var req closeRoomReq
+	if e := decode(r, &req); e != nil {
+		return nil, e
+	}
+	return nil, runInSkeleton(r, "admin.close.room", func() error {
+		return notFound(game.RoomMgr.CloseRoom(req.RoomID))
+	})
+}
+
+type logLevelReq struct {
+	Level string `json:"level"`
+}
+
+func (a *Admin) setLogLevel(r *http.Request) (interface{}, error) {
+	var req logLevelReq
+	if e := decode(r, &req); e != nil {
+		return nil, e
+	}
+	if !log.IsValidLevel(req.Level) {
+		return nil, badRequest(fmt.Errorf("unknown level %s", req.Level))
+	}
+	return nil, runInSkeleton(r, "admin.loglevel", func() error {
+		log.SetLogLevel(req.Level)
+		return nil
+	})
+}
+
+func (a *Admin) reloadWords(r *http.Request) (interface{}, error) {
+	return nil, runInSkeleton(r, "admin.words.reload", func() error {
+		return game.RoomMgr.ReloadWords()
+	})
+}
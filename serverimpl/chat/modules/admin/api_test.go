@@ -0,0 +1,293 @@
+package admin
+
+import (
+	"cloudcadetest/common/compress"
+	"cloudcadetest/framework/log"
+	"cloudcadetest/framework/module"
+	"cloudcadetest/framework/msg/codec"
+	"cloudcadetest/framework/rpc"
+	"cloudcadetest/pb"
+	"cloudcadetest/serverimpl/chat/conf"
+	"cloudcadetest/serverimpl/chat/game"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+)
+
+func TestHandleAuth(t *testing.T) {
+	a := &Admin{Token: "secret", mux: http.NewServeMux()}
+	a.handle("/api/test", http.MethodPost, func(r *http.Request) (interface{}, error) {
+		return nil, notFound(errors.New("missing"))
+	})
+
+	cases := []struct {
+		method string
+		auth   string
+		code   int
+	}{
+		{http.MethodPost, "", http.StatusUnauthorized},
+		{http.MethodPost, "Bearer wrong", http.StatusUnauthorized},
+		{http.MethodPost, "secret", http.StatusUnauthorized},
+		{http.MethodGet, "Bearer secret", http.StatusMethodNotAllowed},
+		{http.MethodPost, "Bearer secret", http.StatusNotFound},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, "/api/test", nil)
+		if c.auth != "" {
+			req.Header.Set("Authorization", c.auth)
+		}
+		w := httptest.NewRecorder()
+		a.mux.ServeHTTP(w, req)
+		if w.Code != c.code {
+			t.Errorf("%s %q: got %d, want %d", c.method, c.auth, w.Code, c.code)
+		}
+	}
+}
+
+// 模拟玩家连接: 读取客户端写入的帧, 记录服务端下发的消息
+type testConn struct {
+	ip     string
+	in     chan []byte
+	out    chan []byte
+	closed chan struct{}
+	once   sync.Once
+}
+
+func (c *testConn) Read(b []byte) (int, error) {
+	select {
+	case data := <-c.in:
+		return copy(b, data), nil
+	case <-c.closed:
+		return 0, io.EOF
+	}
+}
+
+func (c *testConn) Write(b []byte) error {
+	select {
+	case c.out <- b:
+		return nil
+	default:
+		return errors.New("full write channel")
+	}
+}
+
+func (c *testConn) ReadFull([]byte) error { return errors.New("not implemented") }
+func (c *testConn) LocalAddr() net.Addr   { return nil }
+func (c *testConn) RemoteAddr() net.Addr  { return &net.TCPAddr{IP: net.ParseIP(c.ip), Port: 1234} }
+func (c *testConn) Close()                { c.once.Do(func() { close(c.closed) }) }
+func (c *testConn) Destroy()              { c.Close() }
+func (c *testConn) WriteTask()            {}
+func (c *testConn) WriteQueueLen() int    { return 0 }
+
+type testClient struct {
+	t      *testing.T
+	conn   *testConn
+	dec    *codec.Decoder
+	roomID int64
+}
+
+var (
+	gameOnce sync.Once
+	wordFile string
+)
+
+// 以内存中的状态启动聊天模块, 整个测试进程只能启动一次
+func startGame(t *testing.T) {
+	gameOnce.Do(func() {
+		dir, _ := ioutil.TempDir("", "admin")
+		wordFile = filepath.Join(dir, "list.txt")
+		ioutil.WriteFile(wordFile, []byte("shit\n"), 0644)
+		if e := os.Chdir(dir); e != nil {
+			t.Fatal(e)
+		}
+		logger, e := log.New("release", dir, "test", 100, 10)
+		if e != nil {
+			t.Fatal(e)
+		}
+		log.Export(logger)
+
+		conf.Server = &conf.ServerCfg{HistoryRetain: 100, AdminToken: "secret"}
+		sm := &module.ServerMod{GoLen: 100, TimerDispatcherLen: 100, RPCServer: rpc.NewServer(100).SetName("test")}
+		sm.Init()
+		ready := make(chan struct{})
+		close(ready)
+		game.Init(sm, ready)
+		go sm.Run(make(chan bool))
+	})
+}
+
+func login(t *testing.T, name, ip string) *testClient {
+	c := &testClient{
+		t:    t,
+		conn: &testConn{ip: ip, in: make(chan []byte, 10), out: make(chan []byte, 100), closed: make(chan struct{})},
+		dec:  codec.NewDecoder(1<<20, true),
+	}
+	go game.NewPlayer(c.conn)
+	c.send(pb.CSMsgID_REQ_LOGIN, &pb.CSReqBody{Login: &pb.CSReqLogin{Username: name}})
+	rsp := c.next(pb.CSMsgID_RSP_LOGIN).(*pb.CSRspBody)
+	if rsp.ErrCode != pb.ERROR_CODE_SUCCESS {
+		t.Fatalf("login %s:%s", name, rsp.ErrMsg)
+	}
+	c.roomID = rsp.Login.RoomID
+	return c
+}
+
+func (c *testClient) send(id pb.CSMsgID, req *pb.CSReqBody) {
+	data, e := codec.NewEncoder(0).EncodeMsg(codec.FrameV1, id, req, compress.None, nil)
+	if e != nil {
+		c.t.Fatal(e)
+	}
+	c.conn.in <- data
+}
+
+// 跳过其它消息, 等待下一条id消息
+func (c *testClient) next(id pb.CSMsgID) proto.Message {
+	timeout := time.After(3 * time.Second)
+	for {
+		for {
+			f, e := c.dec.Next()
+			if e != nil {
+				c.t.Fatal(e)
+			}
+			if f == nil {
+				break
+			}
+			if f.MsgID != id {
+				continue
+			}
+			msg := codec.NewBody(id)
+			if e = proto.Unmarshal(f.Body, msg); e != nil {
+				c.t.Fatal(e)
+			}
+			return msg
+		}
+		select {
+		case data := <-c.conn.out:
+			c.dec.Write(data)
+		case <-timeout:
+			c.t.Fatalf("no %s", id)
+			return nil
+		}
+	}
+}
+
+func (c *testClient) roomChat(content string) *pb.CSRspBody {
+	c.send(pb.CSMsgID_REQ_ROOM_CHAT, &pb.CSReqBody{RoomChat: &pb.CSReqRoomChat{Content: content}})
+	return c.next(pb.CSMsgID_RSP_ROOM_CHAT).(*pb.CSRspBody)
+}
+
+// 各运维接口作用于真实的房间管理, 检查应答与效果
+func TestHandlers(t *testing.T) {
+	startGame(t)
+	a := New()
+	alice, bob := login(t, "alice", "10.0.0.1"), login(t, "bob", "10.0.0.2")
+
+	cases := []struct {
+		name   string
+		path   string
+		body   string
+		code   int
+		rsp    string
+		effect func(t *testing.T)
+	}{
+		{"kick unknown", "/api/kick", `{"name":"nobody"}`, http.StatusNotFound, `{"error":"player nobody not found"}`, nil},
+		{"kick", "/api/kick", `{"name":"bob","msg":"bye"}`, http.StatusOK, `{"ok":true}`, func(t *testing.T) {
+			ntf := bob.next(pb.CSMsgID_NTF_KICK).(*pb.CSNtfBody)
+			if ntf.Kick.Reason != pb.KICK_REASON_KICK_BY_ADMIN || ntf.Kick.Msg != "bye" {
+				t.Fatalf("kick %v", ntf.Kick)
+			}
+			<-bob.conn.closed
+		}},
+		{"mute bad request", "/api/mute", `{"name":"alice","seconds":-1}`, http.StatusBadRequest, `{"error":"invalid seconds -1"}`, nil},
+		{"mute", "/api/mute", `{"name":"alice","seconds":600}`, http.StatusOK, `{"ok":true}`, func(t *testing.T) {
+			if rsp := alice.roomChat("hi"); !strings.HasPrefix(rsp.ErrMsg, "muted until") {
+				t.Fatalf("muted chat:%v", rsp)
+			}
+			// 重新登录后仍然禁言
+			alice.conn.Close()
+			time.Sleep(100 * time.Millisecond)
+			alice = login(t, "alice", "10.0.0.1")
+			if rsp := alice.roomChat("hi"); !strings.HasPrefix(rsp.ErrMsg, "muted until") {
+				t.Fatalf("mute lost on reconnect:%v", rsp)
+			}
+		}},
+		{"unmute", "/api/mute", `{"name":"alice","seconds":0}`, http.StatusOK, `{"ok":true}`, func(t *testing.T) {
+			if ntf := chatNtf(t, alice, "hi"); ntf.Content != "hi" {
+				t.Fatalf("unmuted chat:%v", ntf)
+			}
+		}},
+		{"unmute again", "/api/mute", `{"name":"alice","seconds":0}`, http.StatusNotFound, `{"error":"alice is not muted"}`, nil},
+		{"notice", "/api/notice", `{"room_id":0,"content":"maintenance at 10pm"}`, http.StatusOK, `{"ok":true}`, func(t *testing.T) {
+			ntf := alice.next(pb.CSMsgID_NTF_ROOM_CHAT).(*pb.CSNtfBody)
+			if ntf.RoomChat.Username != "system" || ntf.RoomChat.Content != "maintenance at 10pm" {
+				t.Fatalf("notice %v", ntf.RoomChat)
+			}
+		}},
+		{"notice unknown room", "/api/notice", `{"room_id":999,"content":"x"}`, http.StatusNotFound, `{"error":"room 999 not found"}`, nil},
+		{"notice empty", "/api/notice", `{"room_id":0}`, http.StatusBadRequest, `{"error":"content is required"}`, nil},
+		{"reload words", "/api/words/reload", ``, http.StatusOK, `{"ok":true}`, func(t *testing.T) {
+			if ntf := chatNtf(t, alice, "darn it"); ntf.Content != "**** it" {
+				t.Fatalf("reloaded word not masked:%q", ntf.Content)
+			}
+		}},
+		{"loglevel bad request", "/api/loglevel", `{"level":"loud"}`, http.StatusBadRequest, `{"error":"unknown level loud"}`, nil},
+		{"loglevel", "/api/loglevel", `{"level":"debug"}`, http.StatusOK, `{"ok":true}`, func(t *testing.T) {
+			if log.GetLogLevel() != log.DebugLevel {
+				t.Fatalf("log level %d", log.GetLogLevel())
+			}
+		}},
+		{"close room unknown", "/api/room/close", `{"room_id":999}`, http.StatusNotFound, `{"error":"room 999 not found"}`, nil},
+		{"close room", "/api/room/close", `{"room_id":1}`, http.StatusOK, `{"ok":true}`, func(t *testing.T) {
+			if ntf := alice.next(pb.CSMsgID_NTF_ROOM_CLOSED).(*pb.CSNtfBody); ntf.RoomClosed.RoomID != 1 {
+				t.Fatalf("closed %v", ntf.RoomClosed)
+			}
+			if rooms := get(t, a, "/api/rooms"); rooms != "null" && rooms != "[]" {
+				t.Fatalf("rooms after close %s", rooms)
+			}
+		}},
+	}
+	for _, c := range cases {
+		if c.name == "reload words" {
+			ioutil.WriteFile(wordFile, []byte("shit\ndarn\n"), 0644)
+		}
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, c.path, strings.NewReader(c.body))
+		req.Header.Set("Authorization", "Bearer secret")
+		a.mux.ServeHTTP(w, req)
+		if w.Code != c.code || strings.TrimSpace(w.Body.String()) != c.rsp {
+			t.Fatalf("%s: %d %s, want %d %s", c.name, w.Code, w.Body.String(), c.code, c.rsp)
+		}
+		if c.effect != nil {
+			c.effect(t)
+		}
+	}
+}
+
+func chatNtf(t *testing.T, c *testClient, content string) *pb.CSNtfRoomChat {
+	if rsp := c.roomChat(content); rsp.ErrCode != pb.ERROR_CODE_SUCCESS {
+		t.Fatalf("chat %q:%v", content, rsp)
+	}
+	return c.next(pb.CSMsgID_NTF_ROOM_CHAT).(*pb.CSNtfBody).RoomChat
+}
+
+func get(t *testing.T, a *Admin, path string) string {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	a.mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s: %d %s", path, w.Code, w.Body.String())
+	}
+	return strings.TrimSpace(w.Body.String())
+}
@@ -5,41 +5,78 @@ import (
 	"cloudcadetest/framework/log"
 	"cloudcadetest/framework/network"
 	"cloudcadetest/serverimpl/chat/conf"
+	"context"
 )
 
-type NewAgentFunc func(*network.TCPConn) agent.Agent
+type NewAgentFunc func(network.IConn) agent.Agent
 
 type Gate struct {
 	TCPAddr          string
+	WSAddr           string
 	FuncMaxConnNum   func() int
+	FuncAllowIP      func(ip string) bool // 封禁检查, 为nil时不检查
 	PendingWriteNum  int
+	MaxMsgLen        uint32
 	ConnNumPerSecond int32 // 每秒限定的连接数
+	CertFile         string
+	KeyFile          string
+	ClientCAFile     string // 双向认证
 	NewAgent         NewAgentFunc
 	tcpServer        *network.TCPServer
+	wsServer         *network.WSServer
+
+	// TCP与WS共用, 连接数上限与每秒连接数对两者合计
+	limiter *network.AcceptLimiter
 }
 
 func New(newAgent NewAgentFunc) *Gate {
 	return &Gate{
 		TCPAddr:          "0.0.0.0:3066",
+		WSAddr:           conf.Server.WSAddr,
 		FuncMaxConnNum:   func() int { return conf.Server.MaxConnNum },
 		PendingWriteNum:  conf.Server.GatePendingWriteNum,
+		MaxMsgLen:        conf.Server.WSMaxMsgLen,
 		ConnNumPerSecond: conf.Server.ConnNumPerSecond,
+		CertFile:         conf.Server.TLSCertFile,
+		KeyFile:          conf.Server.TLSKeyFile,
+		ClientCAFile:     conf.Server.TLSClientCAFile,
 		NewAgent:         newAgent,
 	}
 }
 
 func (gate *Gate) Run(closeSig chan bool) {
 	log.Release("starting gate module %s", gate.TCPAddr)
+	gate.limiter = network.NewAcceptLimiter(gate.FuncAllowIP, gate.FuncMaxConnNum, gate.ConnNumPerSecond)
 	if gate.TCPAddr != "" {
 		gate.tcpServer = new(network.TCPServer)
 		gate.tcpServer.Addr = gate.TCPAddr
-		gate.tcpServer.FuncMaxConnNum = gate.FuncMaxConnNum
+		gate.tcpServer.Limiter = gate.limiter
 		gate.tcpServer.PendingWriteNum = gate.PendingWriteNum
-		gate.tcpServer.NewAgent = gate.NewAgent
-		gate.tcpServer.ConnNumberPerSecond = gate.ConnNumPerSecond
+		gate.tcpServer.NewAgent = func(conn *network.TCPConn) agent.Agent {
+			return gate.NewAgent(conn)
+		}
+		gate.tcpServer.CertFile = gate.CertFile
+		gate.tcpServer.KeyFile = gate.KeyFile
+		gate.tcpServer.ClientCAFile = gate.ClientCAFile
 		gate.tcpServer.Start()
 	}
 
+	if gate.WSAddr != "" {
+		log.Release("starting ws gate %s", gate.WSAddr)
+		gate.wsServer = new(network.WSServer)
+		gate.wsServer.Addr = gate.WSAddr
+		gate.wsServer.Limiter = gate.limiter
+		gate.wsServer.PendingWriteNum = gate.PendingWriteNum
+		gate.wsServer.MaxMsgLen = gate.MaxMsgLen
+		gate.wsServer.CertFile = gate.CertFile
+		gate.wsServer.KeyFile = gate.KeyFile
+		gate.wsServer.ClientCAFile = gate.ClientCAFile
+		gate.wsServer.NewAgent = func(conn *network.WSConn) agent.Agent {
+			return gate.NewAgent(conn)
+		}
+		gate.wsServer.Start()
+	}
+
 	<-closeSig
 }
 
@@ -47,10 +84,24 @@ func (gate *Gate) OnInit() {
 
 }
 
+// 退出时先停止接受新连接, 已有连接待chat模块排空后在OnDestroy中关闭
+func (gate *Gate) OnShutdown(ctx context.Context) {
+	if gate.tcpServer != nil {
+		gate.tcpServer.StopAccept()
+	}
+	if gate.wsServer != nil {
+		gate.wsServer.StopAccept()
+	}
+	log.Release("gate stopped accepting")
+}
+
 func (gate *Gate) OnDestroy() {
 	if gate.tcpServer != nil {
 		gate.tcpServer.Close()
 	}
+	if gate.wsServer != nil {
+		gate.wsServer.Close()
+	}
 	log.Release("gate destroyed")
 }
 
@@ -1,10 +1,12 @@
 package self
 
 import (
+	"cloudcadetest/framework/factory"
 	"cloudcadetest/framework/log"
 	"cloudcadetest/framework/module"
 	"cloudcadetest/framework/rpc"
 	"cloudcadetest/serverimpl/chat/game"
+	"context"
 )
 
 var Mod = new(mod)
@@ -17,12 +19,16 @@ func (m *mod) OnInit() {
 	sm := &module.ServerMod{
 		GoLen:              10000,
 		TimerDispatcherLen: 10000,
-		RPCServer:          rpc.NewServer(10000),
+		RPCServer:          rpc.NewServer(10000).SetName("self"),
 	}
 	sm.Init()
 	Mod.ServerMod = sm
 
-	game.Init(sm)
+	game.Init(sm, factory.HandedOver())
+}
+
+func (m *mod) OnShutdown(ctx context.Context) {
+	game.Shutdown(ctx)
 }
 
 func (m *mod) OnDestroy() {
@@ -0,0 +1,219 @@
+package search
+
+import (
+	"cloudcadetest/common/word/tokenizer"
+	"cloudcadetest/pb"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 单个房间聊天记录的倒排索引, 随消息增量更新
+// 只保留最近的一部分消息, 超过上限的两倍时删掉最早的一批
+
+const (
+	defaultMaxDocs = 1000
+	defaultLimit   = 20
+	maxLimit       = 50
+)
+
+var ErrEmptyQuery = errors.New("empty query")
+
+type posting struct {
+	doc int     // 文档号, 按加入顺序递增
+	pos []int32 // 词在消息中的位置
+}
+
+type Index struct {
+	sync.RWMutex
+	max   int
+	base  int                  // docs[0]的文档号
+	docs  []*pb.HistoryChat    // 按消息ID升序
+	terms map[string][]posting // 按文档号升序
+}
+
+type Query struct {
+	Text     string // 空格分隔的各部分都需出现, 双引号内为短语; 中日韩文字连续出现才算匹配
+	From     string // 只搜索该用户的发言, 为空则不限
+	BeforeID int64  // 只返回消息ID小于BeforeID的结果, 用于翻页
+	Limit    int
+}
+
+func NewIndex(max int) *Index {
+	if max <= 0 {
+		max = defaultMaxDocs
+	}
+	return &Index{
+		max:   max,
+		terms: map[string][]posting{},
+	}
+}
+
+func (idx *Index) Add(msg *pb.HistoryChat) {
+	idx.Lock()
+	defer idx.Unlock()
+
+	doc := idx.base + len(idx.docs)
+	idx.docs = append(idx.docs, msg)
+
+	positions := map[string][]int32{}
+	for _, tok := range tokenizer.Tokenize(msg.Content) {
+		positions[tok.Term] = append(positions[tok.Term], int32(tok.Pos))
+	}
+	for term, pos := range positions {
+		idx.terms[term] = append(idx.terms[term], posting{doc: doc, pos: pos})
+	}
+
+	if len(idx.docs) > 2*idx.max {
+		idx.trim(len(idx.docs) - idx.max)
+	}
+}
+
+func (idx *Index) Len() int {
+	idx.RLock()
+	defer idx.RUnlock()
+	return len(idx.docs)
+}
+
+// 删掉最早的n条
+func (idx *Index) trim(n int) {
+	idx.base += n
+	idx.docs = append([]*pb.HistoryChat(nil), idx.docs[n:]...)
+	for term, ps := range idx.terms {
+		i := sort.Search(len(ps), func(i int) bool { return ps[i].doc >= idx.base })
+		if i == len(ps) {
+			delete(idx.terms, term)
+		} else if i > 0 {
+			idx.terms[term] = append([]posting(nil), ps[i:]...)
+		}
+	}
+}
+
+// 按消息ID降序返回, 最新的在前; more表示还有更早的结果
+func (idx *Index) Search(q *Query) (results []*pb.HistoryChat, more bool, err error) {
+	clauses := parse(q.Text)
+	if len(clauses) == 0 && q.From == "" {
+		return nil, false, ErrEmptyQuery
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	} else if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	idx.RLock()
+	defer idx.RUnlock()
+
+	last := idx.base + len(idx.docs) - 1
+	if q.BeforeID > 0 {
+		last = idx.base + sort.Search(len(idx.docs), func(i int) bool { return idx.docs[i].Id >= q.BeforeID }) - 1
+	}
+
+	match := func(doc int) bool {
+		if doc > last {
+			return false
+		}
+		msg := idx.docs[doc-idx.base]
+		if q.From != "" && msg.From != q.From {
+			return false
+		}
+		for _, c := range clauses {
+			if !idx.matchPhrase(doc, c) {
+				return false
+			}
+		}
+		if len(results) == limit {
+			more = true
+			return true
+		}
+		results = append(results, msg)
+		return false
+	}
+
+	// 从最短的倒排表中取候选, 只按发送者过滤时遍历全部消息
+	if ps, ok := idx.shortest(clauses); ok {
+		for i := len(ps) - 1; i >= 0; i-- {
+			if match(ps[i].doc) {
+				break
+			}
+		}
+	} else {
+		for doc := last; doc >= idx.base; doc-- {
+			if match(doc) {
+				break
+			}
+		}
+	}
+	return results, more, nil
+}
+
+func (idx *Index) shortest(clauses [][]tokenizer.Token) ([]posting, bool) {
+	var (
+		shortest []posting
+		found    bool
+	)
+	for _, c := range clauses {
+		for _, tok := range c {
+			ps := idx.terms[tok.Term]
+			if !found || len(ps) < len(shortest) {
+				shortest, found = ps, true
+			}
+		}
+	}
+	return shortest, found
+}
+
+func (idx *Index) positions(term string, doc int) []int32 {
+	ps := idx.terms[term]
+	i := sort.Search(len(ps), func(i int) bool { return ps[i].doc >= doc })
+	if i < len(ps) && ps[i].doc == doc {
+		return ps[i].pos
+	}
+	return nil
+}
+
+// 短语中的词按查询时的相对位置出现
+func (idx *Index) matchPhrase(doc int, phrase []tokenizer.Token) bool {
+	first := phrase[0]
+	for _, p := range idx.positions(first.Term, doc) {
+		matched := true
+		for _, tok := range phrase[1:] {
+			if !containsPos(idx.positions(tok.Term, doc), p+int32(tok.Pos-first.Pos)) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPos(pos []int32, p int32) bool {
+	i := sort.Search(len(pos), func(i int) bool { return pos[i] >= p })
+	return i < len(pos) && pos[i] == p
+}
+
+// 双引号内为一个短语, 其余按空白分开, 各部分分别切分
+func parse(text string) [][]tokenizer.Token {
+	var clauses [][]tokenizer.Token
+	add := func(s string) {
+		if toks := tokenizer.Query(s); len(toks) > 0 {
+			clauses = append(clauses, toks)
+		}
+	}
+
+	for i, part := range strings.Split(text, `"`) {
+		if i%2 == 1 {
+			add(part)
+			continue
+		}
+		for _, f := range strings.Fields(part) {
+			add(f)
+		}
+	}
+	return clauses
+}
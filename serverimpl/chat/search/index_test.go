@@ -0,0 +1,132 @@
+package search
+
+import (
+	"cloudcadetest/pb"
+	"testing"
+)
+
+func newTestIndex(max int, msgs ...string) *Index {
+	idx := NewIndex(max)
+	for i, content := range msgs {
+		from := "alice"
+		if i%2 == 1 {
+			from = "bob"
+		}
+		idx.Add(&pb.HistoryChat{From: from, Content: content, Seq: int64(i + 1), Id: int64(i+1) * 10})
+	}
+	return idx
+}
+
+func ids(msgs []*pb.HistoryChat) []int64 {
+	ret := make([]int64, 0, len(msgs))
+	for _, m := range msgs {
+		ret = append(ret, m.Id)
+	}
+	return ret
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestIndex_Search(t *testing.T) {
+	idx := newTestIndex(100,
+		"Hello world",        // 10 alice
+		"world peace, hello", // 20 bob
+		"今天天气很好",             // 30 alice
+		"明天天气不好说",            // 40 bob
+		"我在学Go语言",            // 50 alice
+		"hello 天气",           // 60 bob
+	)
+
+	cases := []struct {
+		q    Query
+		want []int64
+	}{
+		{Query{Text: "hello"}, []int64{60, 20, 10}},
+		{Query{Text: "HELLO World"}, []int64{20, 10}},
+		{Query{Text: `"hello world"`}, []int64{10}},
+		{Query{Text: `"world hello"`}, nil},
+		{Query{Text: "天气"}, []int64{60, 40, 30}},
+		{Query{Text: "天气很好"}, []int64{30}},
+		{Query{Text: "天气 好"}, []int64{40, 30}},
+		{Query{Text: "go语言"}, []int64{50}},
+		{Query{Text: "语"}, []int64{50}},
+		{Query{Text: "天气", From: "bob"}, []int64{60, 40}},
+		{Query{From: "alice"}, []int64{50, 30, 10}},
+		{Query{Text: "hello", BeforeID: 60}, []int64{20, 10}},
+		{Query{Text: "missing"}, nil},
+	}
+	for _, c := range cases {
+		got, _, e := idx.Search(&c.q)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if !equalIDs(ids(got), c.want) {
+			t.Errorf("search %+v got %v want %v", c.q, ids(got), c.want)
+		}
+	}
+
+	if _, _, e := idx.Search(&Query{Text: " ,. "}); e != ErrEmptyQuery {
+		t.Errorf("empty query got %v", e)
+	}
+}
+
+func TestIndex_Page(t *testing.T) {
+	msgs := make([]string, 25)
+	for i := range msgs {
+		msgs[i] = "ping 测试"
+	}
+	idx := newTestIndex(100, msgs...)
+
+	var (
+		before int64
+		got    []int64
+	)
+	for {
+		page, more, _ := idx.Search(&Query{Text: "测试", BeforeID: before, Limit: 10})
+		got = append(got, ids(page)...)
+		if !more {
+			break
+		}
+		before = page[len(page)-1].Id
+	}
+	if len(got) != 25 || got[0] != 250 || got[24] != 10 {
+		t.Fatalf("paged results %v", got)
+	}
+}
+
+// 超过上限两倍时删掉最早的消息, 搜索结果不再包含
+func TestIndex_Trim(t *testing.T) {
+	msgs := make([]string, 31)
+	for i := range msgs {
+		msgs[i] = "same words"
+	}
+	msgs[0] = "oldest only"
+	idx := newTestIndex(10, msgs...)
+
+	// 第21条时删到10条, 之后又加了10条
+	if n := idx.Len(); n != 20 {
+		t.Fatalf("len %d after trim", n)
+	}
+	if got, _, _ := idx.Search(&Query{Text: "oldest"}); len(got) != 0 {
+		t.Fatalf("trimmed msg found %v", ids(got))
+	}
+	got, _, _ := idx.Search(&Query{Text: "same", Limit: 50})
+	if len(got) != 20 || got[19].Id != 120 {
+		t.Fatalf("got %v", ids(got))
+	}
+
+	idx.Add(&pb.HistoryChat{Content: "oldest again", Id: 1000})
+	if got, _, _ := idx.Search(&Query{Text: "oldest"}); !equalIDs(ids(got), []int64{1000}) {
+		t.Fatalf("got %v", ids(got))
+	}
+}